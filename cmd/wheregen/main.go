@@ -0,0 +1,68 @@
+// Command wheregen generates Go declarations of typed columns from the CREATE TABLE
+// statements in SQL DDL files. Usage:
+//
+//	go install github.com/rickb777/where/v2/cmd/wheregen@latest
+//	wheregen -pkg db -o db/columns.go schema.sql
+//
+// If no files are given, the DDL is read from standard input. Each table becomes a
+// variable holding a typed column for each of its columns (see package schema).
+//
+// To generate the declarations from a live database instead, call schema.Load and
+// schema.Generate from a small program that imports the database driver.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rickb777/where/v2/cmd/wheregen/schema"
+)
+
+func main() {
+	pkg := flag.String("pkg", "schema", "the package name of the generated code")
+	output := flag.String("o", "", "the output file (default standard output)")
+	flag.Parse()
+
+	if err := run(*pkg, *output, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "wheregen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg, output string, files []string) error {
+	var tables []schema.Table
+
+	if len(files) == 0 {
+		t, err := schema.ParseDDL(os.Stdin)
+		if err != nil {
+			return err
+		}
+		tables = t
+	}
+
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		t, err := schema.ParseDDL(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		tables = append(tables, t...)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := schema.Generate(buf, pkg, tables); err != nil {
+		return err
+	}
+
+	if output != "" {
+		return os.WriteFile(output, buf.Bytes(), 0644)
+	}
+	_, err := os.Stdout.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,399 @@
+// Package schema reads table definitions, either from SQL DDL or from a live database,
+// and generates Go source declaring typed columns for each table (see package typed).
+// Code that uses the generated columns, e.g.
+//
+//	wh := db.Users.Name.Eq("Fred").And(db.Users.Age.GtEq(18))
+//
+// will not compile if a column is misspelled, or if a value has the wrong type. This
+// package is used by the wheregen command.
+package schema
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// Table describes a table.
+type Table struct {
+	Name    string
+	Columns []Column
+}
+
+// Column describes a column of a table.
+type Column struct {
+	Name     string
+	Type     string // the SQL type, e.g. "VARCHAR(100)"
+	Nullable bool
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// ParseDDL reads the CREATE TABLE statements in some SQL DDL. Other statements are
+// ignored, as are table constraints such as PRIMARY KEY (...) and FOREIGN KEY (...).
+func ParseDDL(r io.Reader) ([]Table, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := tokenize(string(b))
+	var tables []Table
+
+	for i := 0; i < len(tokens); i++ {
+		if !isWord(tokens[i], "CREATE") {
+			continue
+		}
+
+		j := i + 1
+		for j < len(tokens) && isWord(tokens[j], "TEMP", "TEMPORARY", "UNLOGGED", "GLOBAL", "LOCAL") {
+			j++
+		}
+		if j >= len(tokens) || !isWord(tokens[j], "TABLE") {
+			continue
+		}
+		j++
+		if j+2 < len(tokens) && isWord(tokens[j], "IF") && isWord(tokens[j+1], "NOT") && isWord(tokens[j+2], "EXISTS") {
+			j += 3
+		}
+		if j+1 >= len(tokens) || tokens[j+1] != "(" {
+			return nil, fmt.Errorf("schema: expected table name and '(' after CREATE TABLE")
+		}
+
+		table := Table{Name: unquoteName(tokens[j])}
+		definitions, end := splitDefinitions(tokens, j+2)
+		if end < 0 {
+			return nil, fmt.Errorf("schema: missing ')' at end of table %s", table.Name)
+		}
+
+		for _, def := range definitions {
+			if len(def) < 2 || isWord(def[0], "CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "KEY", "INDEX", "EXCLUDE") {
+				continue
+			}
+			table.Columns = append(table.Columns, parseColumn(def))
+		}
+
+		tables = append(tables, table)
+		i = end
+	}
+
+	return tables, nil
+}
+
+// splitDefinitions splits the tokens between the parentheses of a CREATE TABLE statement
+// at the commas, starting after the '('. It returns the definitions and the index of the
+// closing ')', or -1 if there isn't one.
+func splitDefinitions(tokens []string, start int) ([][]string, int) {
+	var definitions [][]string
+	var current []string
+	depth := 0
+
+	for i := start; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(":
+			depth++
+		case ")":
+			if depth == 0 {
+				return append(definitions, current), i
+			}
+			depth--
+		case ",":
+			if depth == 0 {
+				definitions = append(definitions, current)
+				current = nil
+				continue
+			}
+		}
+		current = append(current, tokens[i])
+	}
+
+	return nil, -1
+}
+
+// parseColumn interprets a column definition such as "name VARCHAR(100) NOT NULL".
+func parseColumn(def []string) Column {
+	column := Column{Name: unquoteName(def[0]), Nullable: true}
+
+	i := 1
+	for ; i < len(def) && !isWord(def[i], "NOT", "NULL", "PRIMARY", "UNIQUE", "DEFAULT", "REFERENCES",
+		"CHECK", "CONSTRAINT", "COLLATE", "GENERATED", "AUTO_INCREMENT", "AUTOINCREMENT", "IDENTITY"); i++ {
+		switch {
+		case column.Type == "", def[i] == "(", def[i] == ")", def[i] == ",",
+			strings.HasSuffix(column.Type, "("), strings.HasSuffix(column.Type, ","):
+			column.Type += def[i]
+		default:
+			column.Type += " " + def[i]
+		}
+	}
+
+	for ; i < len(def); i++ {
+		switch {
+		case isWord(def[i], "NOT") && i+1 < len(def) && isWord(def[i+1], "NULL"):
+			column.Nullable = false
+		case isWord(def[i], "PRIMARY"):
+			column.Nullable = false
+		}
+	}
+
+	return column
+}
+
+// tokenize splits SQL into words, quoted names, string literals and punctuation,
+// dropping comments.
+func tokenize(s string) []string {
+	var tokens []string
+	rs := []rune(s)
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '-' && i+1 < len(rs) && rs[i+1] == '-':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			j := i + 2
+			for j+1 < len(rs) && !(rs[j] == '*' && rs[j+1] == '/') {
+				j++
+			}
+			i = j + 2
+
+		case r == '\'' || r == '"' || r == '`' || r == '[':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			j := i + 1
+			for j < len(rs) && rs[j] != closing {
+				j++
+			}
+			if j < len(rs) {
+				j++
+			}
+			tokens = append(tokens, string(rs[i:j]))
+			i = j
+
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			j := i + 1
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_' || rs[j] == '$' ||
+				(rs[j] == '.' && j+1 < len(rs) && rs[j+1] != '.')) {
+				j++
+			}
+			tokens = append(tokens, string(rs[i:j]))
+			i = j
+
+		default:
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+func isWord(token string, words ...string) bool {
+	for _, w := range words {
+		if strings.EqualFold(token, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// unquoteName removes the quote marks from a name, which may be prefixed, e.g. `"public"."users"`.
+func unquoteName(name string) string {
+	return strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(name)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Load reads the definitions of the tables in a schema from a live database. For SQLite,
+// the schema is ignored; for other databases, information_schema.columns is queried, so
+// the schema is typically "public" for Postgres, or the database name for MySQL.
+func Load(ctx context.Context, db *sql.DB, d dialect.Dialect, schema string) ([]Table, error) {
+	var query string
+	var args []any
+
+	switch d {
+	case dialect.Sqlite:
+		query = `SELECT m.name, p.name, p.type, CASE p."notnull" WHEN 0 THEN 'YES' ELSE 'NO' END` +
+			` FROM sqlite_master m JOIN pragma_table_info(m.name) p` +
+			` WHERE m.type='table' AND m.name NOT LIKE 'sqlite_%' ORDER BY m.name, p.cid`
+	default:
+		var wh string
+		wh, args = where.Where(where.Eq("table_schema", schema), d)
+		query = `SELECT table_name, column_name, data_type, is_nullable FROM information_schema.columns` +
+			wh + ` ORDER BY table_name, ordinal_position`
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []Table
+	for rows.Next() {
+		var table, column, typ, nullable string
+		if err = rows.Scan(&table, &column, &typ, &nullable); err != nil {
+			return nil, fmt.Errorf("schema: %w", err)
+		}
+		if len(tables) == 0 || tables[len(tables)-1].Name != table {
+			tables = append(tables, Table{Name: table})
+		}
+		t := &tables[len(tables)-1]
+		t.Columns = append(t.Columns, Column{Name: column, Type: typ, Nullable: strings.EqualFold(nullable, "YES")})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	return tables, nil
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Generate writes Go source declaring a variable for each table, in the package pkg.
+// Each variable is a struct holding the table name and a typed column for each column,
+// e.g. for a table "users",
+//
+//	var Users = struct {
+//		Table string
+//		ID    typed.Column[int64]
+//		Name  typed.TextColumn
+//	}{...}
+func Generate(w io.Writer, pkg string, tables []Table) error {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by wheregen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+
+	imports := []string{`"github.com/rickb777/where/v2/typed"`}
+	if usesTime(tables) {
+		imports = append([]string{`"time"`, ""}, imports...)
+	}
+	fmt.Fprintf(buf, "import (\n%s\n)\n", strings.Join(imports, "\n"))
+
+	for _, t := range tables {
+		name := goName(t.Name)
+		fmt.Fprintf(buf, "\n// %s holds the columns of the %s table.\n", name, t.Name)
+		fmt.Fprintf(buf, "var %s = struct {\n\tTable string\n", name)
+		for _, c := range t.Columns {
+			fmt.Fprintf(buf, "\t%s %s\n", fieldName(c.Name), columnType(c.Type))
+		}
+		fmt.Fprintf(buf, "}{\n\tTable: %q,\n", t.Name)
+		for _, c := range t.Columns {
+			fmt.Fprintf(buf, "\t%s: %s(%q),\n", fieldName(c.Name), columnConstructor(c.Type), c.Name)
+		}
+		buf.WriteString("}\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("schema: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// GoType gets the Go type corresponding to an SQL type. Unrecognised types give "any".
+func GoType(sqlType string) string {
+	base := strings.ToLower(strings.Fields(strings.ReplaceAll(sqlType+" ", "(", " "))[0])
+	switch {
+	case integerTypes[base]:
+		return "int64"
+	case base == "real" || strings.HasPrefix(base, "float") || strings.HasPrefix(base, "double") ||
+		base == "numeric" || base == "decimal" || base == "number" || base == "money":
+		return "float64"
+	case strings.HasPrefix(base, "bool") || base == "bit":
+		return "bool"
+	case strings.Contains(base, "char") || strings.Contains(base, "text") || strings.HasSuffix(base, "clob") ||
+		base == "uuid" || base == "enum" || base == "json" || base == "jsonb" || base == "xml" || base == "citext":
+		return "string"
+	case strings.HasPrefix(base, "date") || strings.HasPrefix(base, "time"):
+		return "time.Time"
+	case base == "bytea" || strings.HasSuffix(base, "blob") || strings.HasSuffix(base, "binary"):
+		return "[]byte"
+	}
+	return "any"
+}
+
+var integerTypes = map[string]bool{
+	"int": true, "integer": true, "int2": true, "int4": true, "int8": true, "smallint": true, "bigint": true,
+	"tinyint": true, "mediumint": true, "serial": true, "smallserial": true, "bigserial": true,
+}
+
+func columnType(sqlType string) string {
+	if t := GoType(sqlType); t != "string" {
+		return "typed.Column[" + t + "]"
+	}
+	return "typed.TextColumn"
+}
+
+func columnConstructor(sqlType string) string {
+	if t := GoType(sqlType); t != "string" {
+		return "typed.Col[" + t + "]"
+	}
+	return "typed.Text"
+}
+
+func usesTime(tables []Table) bool {
+	for _, t := range tables {
+		for _, c := range t.Columns {
+			if GoType(c.Type) == "time.Time" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldName gets the name of the struct field for a column; this must not clash with the
+// Table field.
+func fieldName(column string) string {
+	if name := goName(column); name != "Table" {
+		return name
+	}
+	return "Table_"
+}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true, "JSON": true, "SQL": true,
+	"URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goName converts a name such as "user_id" or "public.user_accounts" into an exported Go
+// name such as "UserID" or "UserAccounts".
+func goName(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	buf := &strings.Builder{}
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			buf.WriteString(upper)
+		} else {
+			rs := []rune(word)
+			buf.WriteRune(unicode.ToUpper(rs[0]))
+			buf.WriteString(string(rs[1:]))
+		}
+	}
+
+	s := buf.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "X" + s
+	}
+	return s
+}
@@ -0,0 +1,104 @@
+package schema_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2/cmd/wheregen/schema"
+)
+
+const ddl = `
+-- the users
+CREATE TABLE IF NOT EXISTS "users" (
+	id         BIGSERIAL PRIMARY KEY,
+	name       VARCHAR(100) NOT NULL, /* display name */
+	email_url  CHARACTER VARYING(200),
+	age        INTEGER,
+	balance    NUMERIC(10, 2) DEFAULT 0,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	CONSTRAINT users_name UNIQUE (name)
+);
+
+CREATE INDEX users_age ON users (age);
+
+CREATE TABLE public.user_groups (
+	user_id  BIGINT REFERENCES users (id),
+	group_id INT,
+	PRIMARY KEY (user_id, group_id)
+);
+`
+
+func TestParseDDL(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tables, err := schema.ParseDDL(strings.NewReader(ddl))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tables).To(Equal([]schema.Table{
+		{Name: "users", Columns: []schema.Column{
+			{Name: "id", Type: "BIGSERIAL"},
+			{Name: "name", Type: "VARCHAR(100)"},
+			{Name: "email_url", Type: "CHARACTER VARYING(200)", Nullable: true},
+			{Name: "age", Type: "INTEGER", Nullable: true},
+			{Name: "balance", Type: "NUMERIC(10,2)", Nullable: true},
+			{Name: "created_at", Type: "TIMESTAMP WITH TIME ZONE"},
+		}},
+		{Name: "public.user_groups", Columns: []schema.Column{
+			{Name: "user_id", Type: "BIGINT", Nullable: true},
+			{Name: "group_id", Type: "INT", Nullable: true},
+		}},
+	}))
+
+	_, err = schema.ParseDDL(strings.NewReader(`CREATE TABLE t (a INT`))
+	g.Expect(err).To(MatchError(`schema: missing ')' at end of table t`))
+}
+
+func TestGoType(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(schema.GoType("BIGINT")).To(Equal("int64"))
+	g.Expect(schema.GoType("DOUBLE PRECISION")).To(Equal("float64"))
+	g.Expect(schema.GoType("boolean")).To(Equal("bool"))
+	g.Expect(schema.GoType("NVARCHAR(50)")).To(Equal("string"))
+	g.Expect(schema.GoType("DATETIME2")).To(Equal("time.Time"))
+	g.Expect(schema.GoType("BYTEA")).To(Equal("[]byte"))
+	g.Expect(schema.GoType("INTERVAL")).To(Equal("any"))
+}
+
+func TestGenerate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tables, err := schema.ParseDDL(strings.NewReader(ddl))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	buf := &bytes.Buffer{}
+	err = schema.Generate(buf, "db", tables[1:])
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(buf.String()).To(Equal(`// Code generated by wheregen. DO NOT EDIT.
+
+package db
+
+import (
+	"github.com/rickb777/where/v2/typed"
+)
+
+// UserGroups holds the columns of the public.user_groups table.
+var UserGroups = struct {
+	Table   string
+	UserID  typed.Column[int64]
+	GroupID typed.Column[int64]
+}{
+	Table:   "public.user_groups",
+	UserID:  typed.Col[int64]("user_id"),
+	GroupID: typed.Col[int64]("group_id"),
+}
+`))
+
+	buf.Reset()
+	err = schema.Generate(buf, "db", tables[:1])
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(buf.String()).To(ContainSubstring("import (\n\t\"time\"\n\n\t\"github.com/rickb777/where/v2/typed\"\n)"))
+	g.Expect(buf.String()).To(ContainSubstring("\tEmailURL  typed.TextColumn\n"))
+	g.Expect(buf.String()).To(ContainSubstring("\tCreatedAt: typed.Col[time.Time](\"created_at\"),\n"))
+}
@@ -0,0 +1,135 @@
+// Package analyzer provides a go/analysis Analyzer that reports misuse of the where
+// package in consumer code. It checks calls to functions and methods in the where
+// package for
+//
+//   - column names built using fmt.Sprintf etc or string concatenation, which risk
+//     SQL injection unless the parts are trusted;
+//   - predicates (as for where.Literal and where.Predicate) that are not constants, which
+//     risk SQL injection if any part of them came from an external source;
+//   - a slice passed as the only value to a variadic function such as where.In, which
+//     is then treated as a single value; use where.InSlice or values... instead.
+//
+// Parameters are identified by name, so the checks apply to every function in the where
+// package that has a column or predicate parameter.
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Analyzer reports misuse of the where package.
+var Analyzer = &analysis.Analyzer{
+	Name:     "wherelint",
+	Doc:      "reports misuse of github.com/rickb777/where that risks SQL injection or incorrect SQL",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// wherePackages lists the import paths that are checked.
+var wherePackages = []string{"github.com/rickb777/where/v2", "github.com/rickb777/where"}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || !isWherePackage(fn.Pkg().Path()) {
+			return
+		}
+
+		sig := fn.Type().(*types.Signature)
+		params := sig.Params()
+
+		for i, arg := range call.Args {
+			if i >= params.Len() {
+				break
+			}
+
+			switch params.At(i).Name() {
+			case "column":
+				if isBuiltString(pass, arg) {
+					pass.Reportf(arg.Pos(), "where.%s: column name is built dynamically; this risks SQL injection", fn.Name())
+				}
+			case "predicate":
+				if !isConstant(pass, arg) {
+					pass.Reportf(arg.Pos(), "where.%s: predicate is not a constant; this risks SQL injection", fn.Name())
+				}
+			}
+		}
+
+		if sig.Variadic() && !call.Ellipsis.IsValid() && len(call.Args) == params.Len() {
+			last := call.Args[len(call.Args)-1]
+			if isSlice(pass.TypesInfo.TypeOf(last)) && isAnySlice(params.At(params.Len()-1).Type()) {
+				pass.Reportf(last.Pos(), "where.%s: slice passed as a single value; use %s... or where.InSlice", fn.Name(), types.ExprString(last))
+			}
+		}
+	})
+
+	return nil, nil
+}
+
+func isWherePackage(path string) bool {
+	for _, p := range wherePackages {
+		if path == p {
+			return true
+		}
+	}
+	return false
+}
+
+func isConstant(pass *analysis.Pass, e ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[e]
+	return ok && tv.Value != nil
+}
+
+// isBuiltString identifies non-constant string concatenation and calls to the
+// fmt.Sprint family.
+func isBuiltString(pass *analysis.Pass, e ast.Expr) bool {
+	if isConstant(pass, e) {
+		return false
+	}
+
+	switch x := ast.Unparen(e).(type) {
+	case *ast.BinaryExpr:
+		return true
+	case *ast.CallExpr:
+		fn, ok := typeutil.Callee(pass.TypesInfo, x).(*types.Func)
+		return ok && fn.Pkg() != nil && fn.Pkg().Path() == "fmt" && strings.HasPrefix(fn.Name(), "Sprint")
+	}
+	return false
+}
+
+// isSlice identifies slices and arrays, except []byte, which drivers accept as a single value.
+func isSlice(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		return !isByte(u.Elem())
+	case *types.Array:
+		return !isByte(u.Elem())
+	}
+	return false
+}
+
+func isByte(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Byte
+}
+
+// isAnySlice identifies the []any type of a variadic parameter.
+func isAnySlice(t types.Type) bool {
+	s, ok := t.(*types.Slice)
+	if !ok {
+		return false
+	}
+	i, ok := s.Elem().Underlying().(*types.Interface)
+	return ok && i.Empty()
+}
@@ -0,0 +1,32 @@
+package a
+
+import (
+	"fmt"
+
+	"github.com/rickb777/where/v2"
+)
+
+const table = "users"
+
+func examples(name, userInput string, ids []int, data []byte) {
+	where.Eq("name", name)
+	where.Eq(table+".name", name)
+	where.Eq(userInput, name)
+	where.Eq(fmt.Sprintf("%s.name", userInput), name) // want `where.Eq: column name is built dynamically`
+	where.Eq(userInput+".name", name)                 // want `where.Eq: column name is built dynamically`
+
+	where.Literal("age", " > 45")
+	where.Literal("age", " > "+userInput) // want `where.Literal: predicate is not a constant`
+	where.Predicate(userInput)            // want `where.Predicate: predicate is not a constant`
+	where.Predicate("EXISTS (SELECT 1)", name)
+
+	where.In("id", 1, 2, 3)
+	where.In("id", ids) // want `where.In: slice passed as a single value; use ids... or where.InSlice`
+	where.In("data", data)
+	where.InSlice("id", ids)
+	where.InStrings("name", "a", "b")
+
+	values := []any{1, 2}
+	where.In("id", values...)
+	where.Predicate("a = ? OR b = ?", values) // want `where.Predicate: slice passed as a single value`
+}
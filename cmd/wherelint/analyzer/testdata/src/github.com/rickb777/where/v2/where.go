@@ -0,0 +1,16 @@
+// Package where is a stub of the real package, for testing the analyzer.
+package where
+
+type Expression interface{}
+
+func Predicate(predicate string, value ...any) Expression { return nil }
+
+func Literal(column, predicate string, value ...any) Expression { return nil }
+
+func Eq(column string, value any) Expression { return nil }
+
+func In(column string, values ...any) Expression { return nil }
+
+func InSlice(column string, arg any) Expression { return nil }
+
+func InStrings[S ~string](column string, values ...S) Expression { return nil }
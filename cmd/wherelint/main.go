@@ -0,0 +1,19 @@
+// Command wherelint reports misuse of the github.com/rickb777/where package, such as
+// dynamically-built column names and predicates. Usage:
+//
+//	go install github.com/rickb777/where/v2/cmd/wherelint@latest
+//	wherelint ./...
+//
+// It can also be run using go vet:
+//
+//	go vet -vettool=$(which wherelint) ./...
+package main
+
+import (
+	"github.com/rickb777/where/v2/cmd/wherelint/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
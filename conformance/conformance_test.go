@@ -0,0 +1,57 @@
+//go:build integration
+
+package conformance
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/microsoft/go-mssqldb"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/wheretest"
+	_ "modernc.org/sqlite"
+)
+
+func TestSqlite(t *testing.T) {
+	conform(t, dialect.Sqlite, "sqlite", ":memory:")
+}
+
+func TestPostgres(t *testing.T) {
+	conform(t, dialect.Postgres, "pgx", os.Getenv("WHERE_POSTGRES_DSN"))
+}
+
+func TestMysql(t *testing.T) {
+	conform(t, dialect.Mysql, "mysql", os.Getenv("WHERE_MYSQL_DSN"))
+}
+
+func TestSqlServer(t *testing.T) {
+	conform(t, dialect.SqlServer, "sqlserver", os.Getenv("WHERE_SQLSERVER_DSN"))
+}
+
+func conform(t *testing.T, d dialect.Dialect, driverName, dsn string) {
+	if dsn == "" {
+		t.Skipf("no data source name for %s", d)
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1) // keeps in-memory SQLite on one connection
+
+	q := d.Quoter()
+	db.Exec("DROP TABLE " + q.Quote(wheretest.ConformanceTable))
+	if _, err = db.Exec(wheretest.ConformanceSchema(d)); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE " + q.Quote(wheretest.ConformanceTable))
+
+	corpus := wheretest.Corpus()
+	wheretest.AssertConform(t, db, d, wheretest.ConformanceTable, corpus)
+	wheretest.AssertConform(t, db, d, wheretest.ConformanceTable, corpus[:3], where.OrderBy("name").Limit(5))
+}
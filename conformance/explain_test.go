@@ -0,0 +1,35 @@
+//go:build integration
+
+package conformance
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/wheretest"
+)
+
+func TestExplainSqlite(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err = db.Exec(wheretest.ConformanceSchema(dialect.Sqlite)); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := where.Explain(context.Background(), db, wheretest.ConformanceTable, where.Eq("name", "Fred"), nil, dialect.Sqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(plan, "SCAN") {
+		t.Errorf("unexpected plan %q", plan)
+	}
+}
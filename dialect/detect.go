@@ -0,0 +1,164 @@
+package dialect
+
+import (
+	"github.com/rickb777/where/v2/quote"
+)
+
+// Detection holds the settings inferred by Detect. It is a format option that applies only
+// the settings that were detected, leaving the others unchanged, so it can be combined
+// with SetDefaults and with other options, such as a dialect or a table alias.
+type Detection struct {
+	// Quoter is the quoter used by the query, or nil if it has no quoted identifiers.
+	Quoter quote.Quoter
+
+	// Placeholder is the placeholder style used by the query, if HasPlaceholders is true.
+	Placeholder Flag
+
+	// PlaceholderOffset is the highest number used by numbered placeholders in the query.
+	PlaceholderOffset int
+
+	// HasPlaceholders is true if the query contains any placeholders.
+	HasPlaceholders bool
+}
+
+// Apply alters the configuration according to the detected settings.
+func (d Detection) Apply(config *FormatConfig) {
+	if d.Quoter != nil {
+		config.Quoter = d.Quoter
+	}
+	if d.HasPlaceholders {
+		config.Placeholder = d.Placeholder
+		config.PlaceholderOffset = d.PlaceholderOffset
+	}
+}
+
+// Detect inspects a query, typically a hand-written base query to which a WHERE clause
+// will be appended, and infers the quoting style and placeholder convention it already
+// uses. The result can be passed as a format option so that the appended clauses stay
+// consistent with the base query, e.g.
+//
+//	base := `SELECT * FROM "users" WHERE "tenant" = $1`
+//	sql, args := where.Where(expr, dialect.Detect(base))
+//
+// gives ANSI-quoted identifiers and placeholders that continue from "$2".
+//
+// The quoter is whichever of ANSI, Backticks or SquareBrackets occurs most often; it is
+// nil if the query contains no quoted identifiers. The placeholder is that of the first
+// placeholder found, i.e. Query, Dollar, AtP or Named, and for numbered placeholders the
+// PlaceholderOffset is the highest number used. Literal strings and comments are
+// ignored. The dialect is not inferred, so any dialect option or default is unaffected.
+func Detect(query string) Detection {
+	var fc Detection
+	var ansi, backticks, brackets int
+
+	setPlaceholder := func(f Flag) {
+		if !fc.HasPlaceholders {
+			fc.Placeholder = f
+			fc.HasPlaceholders = true
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		switch ch := query[i]; ch {
+		case '\'':
+			i = skipTo(query, i+1, "'")
+
+		case '-':
+			if i+1 < len(query) && query[i+1] == '-' {
+				i = skipTo(query, i+2, "\n")
+			}
+
+		case '/':
+			if i+1 < len(query) && query[i+1] == '*' {
+				i = skipTo(query, i+2, "*/")
+			}
+
+		case '"':
+			ansi++
+			i = skipTo(query, i+1, `"`)
+
+		case '`':
+			backticks++
+			i = skipTo(query, i+1, "`")
+
+		case '[':
+			// identifiers only, not array subscripts
+			if i+1 < len(query) && isLetter(query[i+1]) {
+				brackets++
+				i = skipTo(query, i+1, "]")
+			}
+
+		case '?':
+			setPlaceholder(Query)
+
+		case '$', '@':
+			j := i + 1
+			if ch == '@' {
+				if j >= len(query) || query[j] != 'p' {
+					continue
+				}
+				j++
+			}
+			n, end := number(query, j)
+			if end == j {
+				continue
+			}
+			f := Dollar
+			if ch == '@' {
+				f = AtP
+			}
+			setPlaceholder(f)
+			if fc.Placeholder == f && n > fc.PlaceholderOffset {
+				fc.PlaceholderOffset = n
+			}
+			i = end - 1
+
+		case ':':
+			if i+1 < len(query) && query[i+1] == ':' {
+				i++ // a cast, e.g. x::text
+			} else if i+1 < len(query) && isLetter(query[i+1]) && (i == 0 || !isWordChar(query[i-1])) {
+				setPlaceholder(Named)
+			}
+		}
+	}
+
+	switch {
+	case ansi == 0 && backticks == 0 && brackets == 0:
+		// unspecified
+	case ansi >= backticks && ansi >= brackets:
+		fc.Quoter = quote.ANSI
+	case backticks >= brackets:
+		fc.Quoter = quote.Backticks
+	default:
+		fc.Quoter = quote.SquareBrackets
+	}
+
+	return fc
+}
+
+// skipTo gives the index of the last byte of the terminator, or the end of the query.
+func skipTo(query string, from int, terminator string) int {
+	for i := from; i+len(terminator) <= len(query); i++ {
+		if query[i:i+len(terminator)] == terminator {
+			return i + len(terminator) - 1
+		}
+	}
+	return len(query)
+}
+
+func number(query string, from int) (n, end int) {
+	end = from
+	for end < len(query) && '0' <= query[end] && query[end] <= '9' {
+		n = n*10 + int(query[end]-'0')
+		end++
+	}
+	return n, end
+}
+
+func isLetter(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+}
+
+func isWordChar(ch byte) bool {
+	return isLetter(ch) || '0' <= ch && ch <= '9'
+}
@@ -2,6 +2,9 @@
 package dialect
 
 import (
+	"fmt"
+	"math"
+	"sort"
 	"strings"
 
 	"github.com/rickb777/where/v2/quote"
@@ -15,7 +18,7 @@ const (
 	// Sqlite identifies SQLite
 	Sqlite
 
-	// Mysql identifies MySQL (also works for MariaDB)
+	// Mysql identifies MySQL (this also works for MariaDB, except where they differ)
 	Mysql
 
 	// Postgres identifies PostgreSQL
@@ -23,9 +26,22 @@ const (
 
 	// SqlServer identifies SqlServer (MS-SQL)
 	SqlServer
+
+	// DB2 identifies IBM DB2
+	DB2
+
+	// Firebird identifies Firebird
+	Firebird
+
+	// Informix identifies IBM Informix
+	Informix
+
+	// MariaDB identifies MariaDB, which is largely compatible with MySQL
+	MariaDB
 )
 
 // These are defaults used by each dialect; they can be altered before first use.
+// Alternatively, where.SetDefaults provides a quoter for all dialects.
 var (
 	// SqliteQuoter uses ANSI double-quotes for Sqlite.
 	// This can be modified, e.g. to None, before first use.
@@ -42,22 +58,275 @@ var (
 	// MSSqlQuoter uses square brackets for MS-SQL.
 	// This can be modified, e.g. to None, before first use.
 	MSSqlQuoter = quote.SquareBrackets
+
+	// DB2Quoter uses ANSI double-quotes for DB2.
+	// This can be modified, e.g. to None, before first use.
+	DB2Quoter = quote.ANSI
+
+	// FirebirdQuoter uses ANSI double-quotes for Firebird.
+	// This can be modified, e.g. to None, before first use.
+	FirebirdQuoter = quote.ANSI
+
+	// MariaDBQuoter uses backticks for MariaDB.
+	// This can be modified, e.g. to None, before first use.
+	MariaDBQuoter = quote.Backticks
+
+	// InformixQuoter does not quote identifiers for Informix, because delimited identifiers
+	// require the DELIMIDENT setting. This can be modified, e.g. to ANSI, before first use.
+	InformixQuoter = quote.None
 )
 
-// Placeholder returns Query, Dollar or AtP.
-func (d Dialect) Placeholder() FormatOption {
+// IDBinding specifies how identifier values, such as UUIDs, are bound as query arguments.
+type IDBinding int
+
+const (
+	// IDAsString binds identifiers in their canonical string form, e.g. for a Postgres uuid column.
+	IDAsString IDBinding = iota
+
+	// IDAsBytes binds identifiers as 16-byte binary values, e.g. for a MySQL BINARY(16) column.
+	IDAsBytes
+)
+
+// These are the identifier bindings used by each dialect; they can be altered before first use.
+var (
+	// SqliteIDBinding binds identifiers as strings for Sqlite.
+	SqliteIDBinding = IDAsString
+
+	// PostgresIDBinding binds identifiers as strings for Postgres, suiting the uuid type.
+	PostgresIDBinding = IDAsString
+
+	// MySqlIDBinding binds identifiers as 16 bytes for MySQL, suiting BINARY(16) columns.
+	MySqlIDBinding = IDAsBytes
+
+	// MSSqlIDBinding binds identifiers as strings for MS-SQL, suiting the uniqueidentifier type.
+	MSSqlIDBinding = IDAsString
+
+	// MariaDBIDBinding binds identifiers as 16 bytes for MariaDB, suiting BINARY(16) columns.
+	// Change this to IDAsString for the uuid type (since MariaDB 10.7).
+	MariaDBIDBinding = IDAsBytes
+)
+
+// These are the maximum numbers of bind parameters per statement accepted by each dialect;
+// they can be altered before first use, e.g. to suit an older database version.
+var (
+	// SqliteMaxBindParams is the default SQLITE_MAX_VARIABLE_NUMBER since SQLite 3.32.
+	SqliteMaxBindParams = 32766
+
+	// PostgresMaxBindParams is limited by the Postgres wire protocol.
+	PostgresMaxBindParams = 65535
+
+	// MySqlMaxBindParams is the limit for MySQL prepared statements.
+	MySqlMaxBindParams = 65535
+
+	// MSSqlMaxBindParams is the limit for MS-SQL remote procedure calls.
+	MSSqlMaxBindParams = 2100
+
+	// MariaDBMaxBindParams is the limit for MariaDB prepared statements.
+	MariaDBMaxBindParams = 65535
+)
+
+// Option returns the format option that selects this dialect. This affects rendering that
+// differs between databases, such as the binding of identifier values. Note that a
+// Dialect is itself a FormatOption, so this is the same as using the dialect directly.
+func (d Dialect) Option() FormatOption {
+	return d
+}
+
+// IDBinding returns the corresponding MySqlIDBinding, PostgresIDBinding, SqliteIDBinding,
+// MSSqlIDBinding or MariaDBIDBinding. For undefined dialects, the DefaultDialect binding
+// is used.
+func (d Dialect) IDBinding() IDBinding {
+	switch d {
+	case Mysql:
+		return MySqlIDBinding
+	case Postgres:
+		return PostgresIDBinding
+	case Sqlite:
+		return SqliteIDBinding
+	case SqlServer:
+		return MSSqlIDBinding
+	case MariaDB:
+		return MariaDBIDBinding
+	case DB2, Firebird, Informix:
+		return IDAsString
+	}
+	if def, _, ok := registered(d); ok {
+		return def.IDBinding
+	}
+	if DefaultDialect != undefined {
+		return DefaultDialect.IDBinding()
+	}
+	return IDAsString
+}
+
+// MaxBindParams returns the corresponding MySqlMaxBindParams, PostgresMaxBindParams,
+// SqliteMaxBindParams, MSSqlMaxBindParams or MariaDBMaxBindParams. For undefined dialects, it returns 0, meaning no limit.
+func (d Dialect) MaxBindParams() int {
+	switch d {
+	case Mysql:
+		return MySqlMaxBindParams
+	case Postgres:
+		return PostgresMaxBindParams
+	case Sqlite:
+		return SqliteMaxBindParams
+	case SqlServer:
+		return MSSqlMaxBindParams
+	case MariaDB:
+		return MariaDBMaxBindParams
+	}
+	if def, _, ok := registered(d); ok {
+		return def.MaxBindParams
+	}
+	return 0
+}
+
+// MaxRowCount returns the largest value accepted by the dialect for the number of rows
+// limited or skipped by a query. Informix accepts 32-bit integers for SKIP and FIRST; the
+// others accept 64-bit integers. For registered dialects, zero in the definition means
+// the same as for the others.
+func (d Dialect) MaxRowCount() int64 {
+	switch d {
+	case Informix:
+		return math.MaxInt32
+	}
+	if def, _, ok := registered(d); ok && def.MaxRowCount > 0 {
+		return def.MaxRowCount
+	}
+	return math.MaxInt64
+}
+
+// LimitStyle returns the way that the dialect limits the number of rows returned.
+func (d Dialect) LimitStyle() LimitStyle {
+	switch d {
+	case SqlServer:
+		return Top
+	case DB2:
+		return FetchFirst
+	case Firebird:
+		return Rows
+	case Informix:
+		return SkipFirst
+	}
+	if def, _, ok := registered(d); ok {
+		return def.LimitStyle
+	}
+	return LimitOffset
+}
+
+// SupportsNullsOrdering tests whether the dialect accepts NULLS FIRST and NULLS LAST in
+// ORDER BY clauses. MySQL and SQL-Server do not.
+func (d Dialect) SupportsNullsOrdering() bool {
+	switch d {
+	case Sqlite, Postgres, DB2, Firebird, Informix:
+		return true
+	}
+	def, _, _ := registered(d)
+	return def.NullsOrdering
+}
+
+// SupportsLimit tests whether the dialect accepts LIMIT clauses. SQL-Server, DB2, Firebird and
+// Informix do not; they use other syntax instead (see LimitStyle).
+func (d Dialect) SupportsLimit() bool {
+	return d.LimitStyle() == LimitOffset
+}
+
+// SupportsReturning tests whether the dialect accepts RETURNING clauses on INSERT, UPDATE
+// and DELETE statements. SQLite (since 3.35), Postgres, Firebird and MariaDB (since 10.5) do;
+// MySQL does not and SQL-Server uses OUTPUT instead.
+func (d Dialect) SupportsReturning() bool {
+	switch d {
+	case Sqlite, Postgres, Firebird, MariaDB:
+		return true
+	}
+	def, _, _ := registered(d)
+	return def.Returning
+}
+
+// SupportsSetOperations tests whether the dialect accepts EXCEPT and INTERSECT as well as UNION.
+// MySQL only does so since 8.0.31, so it is reported as not supporting them; MariaDB does
+// since 10.3.
+func (d Dialect) SupportsSetOperations() bool {
+	switch d {
+	case Sqlite, Postgres, SqlServer, DB2, Firebird, Informix, MariaDB:
+		return true
+	}
+	def, _, _ := registered(d)
+	return def.SetOperations
+}
+
+// SupportsSequences tests whether the dialect provides sequences, i.e. CREATE SEQUENCE and
+// functions such as NEXTVAL. MySQL and SQLite do not; MariaDB does since 10.3.
+func (d Dialect) SupportsSequences() bool {
+	switch d {
+	case Postgres, SqlServer, DB2, Firebird, Informix, MariaDB:
+		return true
+	}
+	def, _, _ := registered(d)
+	return def.Sequences
+}
+
+// SupportsBooleanLiterals tests whether the dialect accepts the literals TRUE and FALSE.
+// SQL-Server and Informix do not, so 1 and 0 are used instead.
+func (d Dialect) SupportsBooleanLiterals() bool {
+	switch d {
+	case SqlServer, Informix:
+		return false
+	}
+	if def, _, ok := registered(d); ok {
+		return def.BooleanLiterals
+	}
+	return true
+}
+
+// EmptyStringIsNull tests whether the dialect treats empty strings as null, as Oracle does.
+// Comparisons with empty strings are then rendered as tests for null. None of the built-in
+// dialects do so; registered dialects can (see DialectDefinition).
+func (d Dialect) EmptyStringIsNull() bool {
+	def, _, _ := registered(d)
+	return def.EmptyStringIsNull
+}
+
+// SupportsAggregateFilter tests whether the dialect accepts FILTER (WHERE ...) clauses on
+// aggregate functions. SQLite (since 3.30) and Postgres do; for others, CASE expressions
+// are used instead.
+func (d Dialect) SupportsAggregateFilter() bool {
+	switch d {
+	case Sqlite, Postgres:
+		return true
+	}
+	def, _, _ := registered(d)
+	return def.AggregateFilter
+}
+
+// SupportsILike tests whether the dialect accepts ILIKE for case-insensitive pattern
+// matching. Postgres does; for others, both sides are converted with LOWER instead.
+// Undefined dialects are assumed to accept it.
+func (d Dialect) SupportsILike() bool {
+	switch d {
+	case Postgres, undefined:
+		return true
+	}
+	def, _, _ := registered(d)
+	return def.ILike
+}
+
+// Placeholder returns Query, Dollar, AtP or Named.
+func (d Dialect) Placeholder() Flag {
 	switch d {
 	case Postgres:
 		return Dollar
 	case SqlServer:
 		return AtP
 	}
+	if def, _, ok := registered(d); ok {
+		return def.Placeholder
+	}
 	return Query
 }
 
-// Quoter returns the corresponding MySqlQuoter, PostgresQuoter, SqliteQuoter
-// MSSqlQuoter or the quote.DefaultQuoter. All of these can be configured before
-// first use.
+// Quoter returns the corresponding MySqlQuoter, PostgresQuoter, SqliteQuoter, MSSqlQuoter,
+// DB2Quoter, FirebirdQuoter, InformixQuoter, MariaDBQuoter or the
+// quote.DefaultQuoter. All of these can be configured before first use.
 func (d Dialect) Quoter() quote.Quoter {
 	switch d {
 	case Mysql:
@@ -68,6 +337,17 @@ func (d Dialect) Quoter() quote.Quoter {
 		return SqliteQuoter
 	case SqlServer:
 		return MSSqlQuoter
+	case DB2:
+		return DB2Quoter
+	case Firebird:
+		return FirebirdQuoter
+	case Informix:
+		return InformixQuoter
+	case MariaDB:
+		return MariaDBQuoter
+	}
+	if def, _, ok := registered(d); ok && def.Quoter != nil {
+		return def.Quoter
 	}
 	return quote.DefaultQuoter
 }
@@ -83,33 +363,84 @@ func (d Dialect) String() string {
 		return "Postgres"
 	case SqlServer:
 		return "SqlServer"
+	case DB2:
+		return "DB2"
+	case Firebird:
+		return "Firebird"
+	case Informix:
+		return "Informix"
+	case MariaDB:
+		return "MariaDB"
+	}
+	if _, name, ok := registered(d); ok {
+		return name
 	}
 	return ""
 }
 
+var dialectNames = map[string]Dialect{
+	"sqlite":      Sqlite,
+	"sqlite3":     Sqlite,
+	"mysql":       Mysql,
+	"mariadb":     MariaDB,
+	"postgres":    Postgres,
+	"postgresql":  Postgres,
+	"pgx":         Postgres,
+	"sqlserver":   SqlServer,
+	"sql-server":  SqlServer,
+	"mssql":       SqlServer,
+	"db2":         DB2,
+	"firebird":    Firebird,
+	"firebirdsql": Firebird,
+	"informix":    Informix,
+	"ifx":         Informix,
+}
+
 // Pick finds a dialect that matches by name, ignoring letter case.
 // It matches:
 //
 //   - "sqlite", "sqlite3"
 //   - "mysql"
+//   - "mariadb"
 //   - "postgres", "postgresql", "pgx"
 //   - "sqlserver", "sql-server", "mssql"
+//   - "db2"
+//   - "firebird", "firebirdsql"
+//   - "informix", "ifx"
 //
-// It returns 0 if not found.
+// It also matches the names of registered dialects (see Register).
+// It returns 0 if not found; PickE reports this as an error instead.
 func Pick(name string) Dialect {
-	switch strings.ToLower(name) {
-	case "sqlite", "sqlite3":
-		return Sqlite
-	case "mysql":
-		return Mysql
-	case "postgres", "postgresql", "pgx":
-		return Postgres
-	case "sqlserver", "sql-server", "mssql":
-		return SqlServer
+	d, _ := PickE(name)
+	return d
+}
+
+// PickE finds a dialect that matches by name, in the same way as Pick, except that it
+// returns an error if the name is not known. This is preferable when the name comes
+// from configuration, because a misspelt name would otherwise go unnoticed.
+func PickE(name string) (Dialect, error) {
+	lower := strings.ToLower(name)
+	d, ok := dialectNames[lower]
+	if !ok {
+		d, ok = pickRegistered(lower)
+	}
+	if !ok {
+		return undefined, fmt.Errorf("dialect: unknown dialect %q; expected one of %s", name, strings.Join(Names(), ", "))
+	}
+	return d, nil
+}
+
+// Names lists the names accepted by Pick and PickE, in alphabetical order. This includes
+// the names of registered dialects.
+func Names() []string {
+	names := registeredNames()
+	for n := range dialectNames {
+		names = append(names, n)
 	}
-	return undefined
+	sort.Strings(names)
+	return names
 }
 
 // DefaultDialect is Sqlite, chosen as being probably the simplest. This can be
-// altered before first use.
+// altered before first use. Prefer where.SetDefaults, which is safe for concurrent use.
 var DefaultDialect = Sqlite
@@ -0,0 +1,151 @@
+package dialect
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2/quote"
+)
+
+func TestPickE(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, name := range Names() {
+		d, err := PickE(strings.ToUpper(name))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(d).To(Equal(Pick(name)))
+		g.Expect(d).NotTo(BeZero())
+	}
+
+	g.Expect(PickE("PostgreSQL")).To(Equal(Postgres))
+	g.Expect(Pick("oracle")).To(BeZero())
+
+	_, err := PickE("postgress")
+	g.Expect(err).To(MatchError(ContainSubstring(`dialect: unknown dialect "postgress"; expected one of `)))
+	g.Expect(err).To(MatchError(HaveSuffix(`mssql, mysql, pgx, postgres, postgresql, sql-server, sqlite, sqlite3, sqlserver`)))
+}
+
+func TestRegister(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	vertica := Register("Vertica", DialectDefinition{
+		Aliases:       []string{"vsql"},
+		Quoter:        quote.ANSI,
+		Placeholder:   Query,
+		MaxBindParams: 32000,
+	})
+	db2 := Register("DB2x", DialectDefinition{Placeholder: Query, LimitStyle: Top, IDBinding: IDAsBytes})
+
+	g.Expect(vertica).NotTo(Equal(db2))
+	g.Expect(vertica.String()).To(Equal("Vertica"))
+	g.Expect(Pick("VSQL")).To(Equal(vertica))
+	g.Expect(PickE("vertica")).To(Equal(vertica))
+	g.Expect(Names()).To(ContainElements("vertica", "vsql", "db2x"))
+
+	g.Expect(vertica.Quoter()).To(Equal(quote.ANSI))
+	g.Expect(vertica.MaxBindParams()).To(Equal(32000))
+	g.Expect(vertica.LimitStyle()).To(Equal(LimitOffset))
+	g.Expect(db2.Quoter()).To(Equal(quote.DefaultQuoter))
+	g.Expect(db2.IDBinding()).To(Equal(IDAsBytes))
+	g.Expect(db2.LimitStyle()).To(Equal(Top))
+	g.Expect(ConfigFor(vertica)).To(Equal(FormatConfig{Dialect: vertica, Quoter: quote.ANSI}))
+
+	g.Expect(func() { Register("vertica", DialectDefinition{}) }).To(Panic())
+	g.Expect(func() { Register("MSSQL", DialectDefinition{}) }).To(PanicWith(`dialect: Register called twice for name "MSSQL"`))
+	g.Expect(func() { Register("", DialectDefinition{}) }).To(PanicWith("dialect: Register called with an empty name"))
+	g.Expect(func() { Register("Exasol", DialectDefinition{Aliases: []string{""}}) }).To(PanicWith("dialect: Register called with an empty name"))
+	g.Expect(Dialect(999).String()).To(BeEmpty())
+}
+
+func TestCapabilities(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Postgres.SupportsNullsOrdering()).To(BeTrue())
+	g.Expect(Mysql.SupportsNullsOrdering()).To(BeFalse())
+	g.Expect(SqlServer.SupportsNullsOrdering()).To(BeFalse())
+
+	g.Expect(Sqlite.SupportsLimit()).To(BeTrue())
+	g.Expect(SqlServer.SupportsLimit()).To(BeFalse())
+
+	g.Expect(Postgres.SupportsReturning()).To(BeTrue())
+	g.Expect(Mysql.SupportsReturning()).To(BeFalse())
+
+	g.Expect(SqlServer.MaxBindParams()).To(Equal(2100))
+	g.Expect(undefined.MaxBindParams()).To(BeZero())
+
+	g.Expect(Informix.MaxRowCount()).To(Equal(int64(math.MaxInt32)))
+	g.Expect(Postgres.MaxRowCount()).To(Equal(int64(math.MaxInt64)))
+
+	g.Expect(Postgres.SupportsBooleanLiterals()).To(BeTrue())
+	g.Expect(SqlServer.SupportsBooleanLiterals()).To(BeFalse())
+	g.Expect(undefined.SupportsBooleanLiterals()).To(BeTrue())
+	g.Expect(Postgres.EmptyStringIsNull()).To(BeFalse())
+
+	g.Expect(Postgres.SupportsILike()).To(BeTrue())
+	g.Expect(Mysql.SupportsILike()).To(BeFalse())
+	g.Expect(undefined.SupportsILike()).To(BeTrue())
+
+	g.Expect(Sqlite.SupportsAggregateFilter()).To(BeTrue())
+	g.Expect(Mysql.SupportsAggregateFilter()).To(BeFalse())
+
+	custom := Register("Custom", DialectDefinition{NullsOrdering: true, Returning: true, LimitStyle: Top})
+	g.Expect(custom.SupportsBooleanLiterals()).To(BeFalse())
+	g.Expect(custom.SupportsNullsOrdering()).To(BeTrue())
+	g.Expect(custom.SupportsReturning()).To(BeTrue())
+	g.Expect(custom.SupportsLimit()).To(BeFalse())
+	g.Expect(custom.EmptyStringIsNull()).To(BeFalse())
+
+	emptyIsNull := Register("EmptyIsNull", DialectDefinition{EmptyStringIsNull: true})
+	g.Expect(emptyIsNull.EmptyStringIsNull()).To(BeTrue())
+}
+
+func TestMariaDB(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Pick("MariaDB")).To(Equal(MariaDB))
+	g.Expect(MariaDB.String()).To(Equal("MariaDB"))
+	g.Expect(ConfigFor(MariaDB)).To(Equal(ConfigFor(Mysql).with(MariaDB)))
+	g.Expect(MariaDB.IDBinding()).To(Equal(IDAsBytes))
+
+	g.Expect(MariaDB.SupportsReturning()).To(BeTrue())
+	g.Expect(Mysql.SupportsReturning()).To(BeFalse())
+	g.Expect(MariaDB.SupportsSetOperations()).To(BeTrue())
+	g.Expect(Mysql.SupportsSetOperations()).To(BeFalse())
+	g.Expect(MariaDB.SupportsSequences()).To(BeTrue())
+	g.Expect(Mysql.SupportsSequences()).To(BeFalse())
+	g.Expect(MariaDB.SupportsNullsOrdering()).To(BeFalse())
+}
+
+func (fc FormatConfig) with(d Dialect) FormatConfig {
+	fc.Dialect = d
+	return fc
+}
+
+func TestDetect(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Detect(`SELECT * FROM users`)).To(Equal(Detection{}))
+	g.Expect(Detect(`SELECT * FROM "users" WHERE "tenant" = $1 AND "x"::text = $12 -- $99`)).
+		To(Equal(Detection{Quoter: quote.ANSI, Placeholder: Dollar, PlaceholderOffset: 12, HasPlaceholders: true}))
+	g.Expect(Detect("SELECT * FROM `users` WHERE `tenant` = ? AND note = '[a] \"b\" $3'")).
+		To(Equal(Detection{Quoter: quote.Backticks, Placeholder: Query, HasPlaceholders: true}))
+	g.Expect(Detect(`SELECT * FROM [users] WHERE [tenant] = @p2 /* "x" */`)).
+		To(Equal(Detection{Quoter: quote.SquareBrackets, Placeholder: AtP, PlaceholderOffset: 2, HasPlaceholders: true}))
+	g.Expect(Detect(`SELECT * FROM users WHERE tags[1] = :tag AND t @> '{}'`)).
+		To(Equal(Detection{Placeholder: Named, HasPlaceholders: true}))
+}
+
+func TestDetection_Apply(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// only the detected settings are altered
+	fc := FormatConfig{Dialect: Postgres, Placeholder: Dollar, PlaceholderOffset: 3, TableAlias: "t", Comment: "/*x*/"}
+	Detect("SELECT * FROM `users` WHERE `tenant` = ?").Apply(&fc)
+	g.Expect(fc).To(Equal(FormatConfig{Dialect: Postgres, Quoter: quote.Backticks, Placeholder: Query, TableAlias: "t", Comment: "/*x*/"}))
+
+	fc = FormatConfig{Dialect: Mysql, Quoter: quote.Backticks, Placeholder: Dollar}
+	Detect(`SELECT * FROM users`).Apply(&fc)
+	g.Expect(fc).To(Equal(FormatConfig{Dialect: Mysql, Quoter: quote.Backticks, Placeholder: Dollar}))
+}
@@ -0,0 +1,81 @@
+package dialect
+
+import (
+	"context"
+
+	"github.com/rickb777/where/v2/quote"
+)
+
+// FormatConfig holds the settings that control formatting. It is useful when these are
+// decided in one place, such as per request or per tenant, and then applied in shared code
+// that formats expressions (see NewContext). Format options supplied explicitly take
+// precedence over the corresponding settings here. Zero-valued fields are treated
+// as unspecified.
+type FormatConfig struct {
+	// Dialect selects dialect-specific rendering. If zero, DefaultDialect is used.
+	Dialect Dialect
+
+	// Quoter quotes identifiers. If nil, quote.DefaultQuoter is used.
+	Quoter quote.Quoter
+
+	// Placeholder is one of Query, Dollar, AtP, Inline or Named.
+	Placeholder Flag
+
+	// PlaceholderOffset is the number of numbered placeholders already used by the
+	// statement, e.g. 2 gives "$3" as the first placeholder. This allows expressions
+	// to be appended to a base query that has its own placeholders.
+	PlaceholderOffset int
+
+	// TableAlias, if not blank, prefixes every unqualified column in expressions, e.g.
+	// "t" gives "t.age" for the column "age". Columns that already have a prefix are
+	// left unchanged.
+	TableAlias string
+
+	// KeywordCase determines the letter case of generated SQL keywords.
+	KeywordCase KeywordCase
+
+	// Spacing determines the whitespace around operators and after commas in predicates.
+	Spacing Spacing
+
+	// InValues, if positive, is the number of values from which IN lists are rendered
+	// as "IN (VALUES (?),(?),...)" for Postgres, whose planner handles these better than
+	// long IN lists. Other dialects are unaffected.
+	InValues int
+
+	// InArray, if true, renders IN lists as "= ANY(?)" for Postgres, binding all the values
+	// as a single array argument. The statement text is then the same whatever the number
+	// of values. This requires a driver that accepts slices as array parameters, such as
+	// pgx. It takes precedence over InValues. Other dialects are unaffected, as are
+	// inlined values (see Inline), which are listed as usual.
+	InArray bool
+
+	// Keywords, if not nil, provides replacements for generated SQL keywords, for
+	// backends that accept SQL-like syntax with different keywords. It maps the
+	// upper-case keyword, such as "WHERE", "AND", "OR", "NOT" or "ORDER BY", to its
+	// replacement, which is used verbatim (i.e. regardless of KeywordCase).
+	// Keywords within predicates are not affected.
+	Keywords map[string]string
+
+	// Comment, if not blank, is appended to formatted clauses. It must be a
+	// complete SQL comment, e.g. "/*route='%2Fhome'*/".
+	Comment string
+}
+
+// ConfigFor returns the configuration normally used for a dialect, i.e. using its
+// placeholder style and its quoter.
+func ConfigFor(d Dialect) FormatConfig {
+	return FormatConfig{Dialect: d, Quoter: d.Quoter(), Placeholder: d.Placeholder()}
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the parent context that carries a format configuration.
+func NewContext(parent context.Context, config FormatConfig) context.Context {
+	return context.WithValue(parent, contextKey{}, config)
+}
+
+// FromContext gets the format configuration carried by a context, if any.
+func FromContext(ctx context.Context) (FormatConfig, bool) {
+	config, ok := ctx.Value(contextKey{}).(FormatConfig)
+	return config, ok
+}
@@ -1,16 +1,35 @@
 package dialect
 
-// FormatOption provides controls for where-expression formatting.
-type FormatOption int
+import (
+	"github.com/rickb777/where/v2/quote"
+)
+
+// FormatOption provides controls for where-expression formatting. The simple options
+// are the Flag constants below. Dialect values are also options: they select the dialect
+// used for rendering that differs between databases. Options that carry values, such as
+// custom quoters, are provided by the where package (see where.WithQuoter etc).
+//
+// Where there are several Flag values of the same kind, i.e. placeholder flags or quoting
+// flags, or several Dialect values, the first one takes precedence. The options that carry
+// values are applied in order, so the last one of those takes precedence.
+type FormatOption interface {
+	// Apply alters the configuration according to the option.
+	Apply(config *FormatConfig)
+}
+
+// Flag is the type of the simple format options.
+type Flag int
 
 // These options affect how placeholders are renderered.
 const (
 	// Query indicates placeholders using queries '?'. For Sqlite & MySql.
 	// Because where-expressions are constructed using queries, this option
 	// specifies that no change is needed.
-	Query FormatOption = iota
+	Query Flag = iota
 
 	// Dollar indicates placeholders using numbered $1, $2, ... format. For PostgreSQL.
+	// This does not select the Postgres dialect, which must also be given for the
+	// rendering that differs between databases, such as ILIKE.
 	Dollar
 
 	// AtP indicates placeholders using numbered @p1, @p2, ... format. For SQL-Server.
@@ -18,6 +37,10 @@ const (
 
 	// Inline indicates that each placeholder is removed and its value is inlined.
 	Inline
+
+	// Named indicates named placeholders derived from the column names, e.g. :name_1.
+	// The arguments are then sql.NamedArg values. For Oracle (e.g. the godror driver).
+	Named
 )
 
 // These options affect how column name identifiers are quoted, if required.
@@ -25,7 +48,7 @@ const (
 // Otherwise, it is optional.
 const (
 	// NoQuotes indicates identifiers will not be enclosed in quote marks.
-	NoQuotes FormatOption = iota + 10
+	NoQuotes Flag = iota + 10
 
 	// ANSIQuotes indicates identifiers will be enclosed in double quote marks. For Postgres.
 	ANSIQuotes
@@ -36,3 +59,90 @@ const (
 	// SquareBrackets indicates identifiers will be enclosed in square brackets. For SQL-Server.
 	SquareBrackets
 )
+
+// Apply alters the configuration according to the option.
+func (f Flag) Apply(config *FormatConfig) {
+	switch f {
+	case Query, Dollar, AtP, Inline, Named:
+		config.Placeholder = f
+	case NoQuotes:
+		config.Quoter = quote.None
+	case ANSIQuotes:
+		config.Quoter = quote.ANSI
+	case Backticks:
+		config.Quoter = quote.Backticks
+	case SquareBrackets:
+		config.Quoter = quote.SquareBrackets
+	}
+}
+
+// Apply selects this dialect.
+func (d Dialect) Apply(config *FormatConfig) {
+	config.Dialect = d
+}
+
+// Apply replaces the settings in config with those specified in this configuration,
+// i.e. its non-zero fields. The other settings are left unchanged.
+func (fc FormatConfig) Apply(config *FormatConfig) {
+	if fc.Dialect != 0 {
+		config.Dialect = fc.Dialect
+	}
+	if fc.Quoter != nil {
+		config.Quoter = fc.Quoter
+	}
+	if fc.Placeholder != Query {
+		config.Placeholder = fc.Placeholder
+	}
+	if fc.PlaceholderOffset != 0 {
+		config.PlaceholderOffset = fc.PlaceholderOffset
+	}
+	if fc.TableAlias != "" {
+		config.TableAlias = fc.TableAlias
+	}
+	if fc.KeywordCase != UpperCase {
+		config.KeywordCase = fc.KeywordCase
+	}
+	if fc.Spacing != Compact {
+		config.Spacing = fc.Spacing
+	}
+	if fc.InValues != 0 {
+		config.InValues = fc.InValues
+	}
+	if fc.InArray {
+		config.InArray = true
+	}
+	if fc.Keywords != nil {
+		config.Keywords = fc.Keywords
+	}
+	if fc.Comment != "" {
+		config.Comment = fc.Comment
+	}
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// KeywordCase determines the letter case of SQL keywords generated by formatting.
+type KeywordCase int
+
+const (
+	// UpperCase renders keywords in upper case, e.g. "WHERE", "AND". This is the default.
+	UpperCase KeywordCase = iota
+
+	// LowerCase renders keywords in lower case, e.g. "where", "and".
+	LowerCase
+)
+
+//-------------------------------------------------------------------------------------------------
+
+// Spacing determines the whitespace around operators and after commas in formatted predicates.
+type Spacing int
+
+const (
+	// Compact renders predicates without optional spaces, e.g. "name=?" and "IN (?,?)".
+	// This is the default.
+	Compact Spacing = iota
+
+	// Spaced renders single spaces around comparison operators and after commas, e.g.
+	// "name = ?" and "IN (?, ?)".
+	Spaced
+)
@@ -0,0 +1,159 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rickb777/where/v2/quote"
+)
+
+// LimitStyle determines how a dialect limits the number of rows returned by a query.
+type LimitStyle int
+
+const (
+	// LimitOffset uses "LIMIT n OFFSET m", as for most databases.
+	LimitOffset LimitStyle = iota
+
+	// Top uses "TOP (n)" after "SELECT", as for SQL-Server.
+	Top
+
+	// FetchFirst uses "OFFSET m ROWS FETCH FIRST n ROWS ONLY", as for DB2.
+	FetchFirst
+
+	// Rows uses "ROWS m TO n", as for Firebird.
+	Rows
+
+	// SkipFirst uses "SKIP m FIRST n" after "SELECT", as for Informix.
+	SkipFirst
+)
+
+// DialectDefinition describes a database that is not built in, allowing it to be used with
+// the where package, including Pick, Quoter, Placeholder and query constraint formatting.
+// Zero-valued fields give the usual defaults.
+type DialectDefinition struct {
+	// Aliases are further names for the dialect, accepted by Pick.
+	Aliases []string
+
+	// Quoter quotes identifiers. If nil, quote.DefaultQuoter is used.
+	Quoter quote.Quoter
+
+	// Placeholder is Query, Dollar, AtP or Named.
+	Placeholder Flag
+
+	// IDBinding specifies how identifier values, such as UUIDs, are bound.
+	IDBinding IDBinding
+
+	// MaxBindParams is the maximum number of bind parameters per statement, or 0 for no limit.
+	MaxBindParams int
+
+	// LimitStyle determines how query constraints limit the number of rows.
+	LimitStyle LimitStyle
+
+	// MaxRowCount is the largest limit or offset accepted, or 0 for the 64-bit maximum.
+	MaxRowCount int64
+
+	// NullsOrdering indicates support for NULLS FIRST and NULLS LAST in ORDER BY clauses.
+	NullsOrdering bool
+
+	// Returning indicates support for RETURNING clauses.
+	Returning bool
+
+	// SetOperations indicates support for EXCEPT and INTERSECT.
+	SetOperations bool
+
+	// Sequences indicates support for sequences.
+	Sequences bool
+
+	// BooleanLiterals indicates support for the literals TRUE and FALSE.
+	BooleanLiterals bool
+
+	// EmptyStringIsNull indicates that empty strings are treated as null.
+	EmptyStringIsNull bool
+
+	// AggregateFilter indicates support for FILTER (WHERE ...) clauses on aggregate functions.
+	AggregateFilter bool
+
+	// ILike indicates support for ILIKE, i.e. case-insensitive LIKE.
+	ILike bool
+}
+
+// firstRegistered is the value of the first dialect added by Register; lower values
+// are reserved for the built-in dialects.
+const firstRegistered Dialect = 100
+
+var registry = struct {
+	sync.RWMutex
+	names       []string
+	definitions []DialectDefinition
+	byName      map[string]Dialect
+}{
+	byName: make(map[string]Dialect),
+}
+
+// Register adds a dialect for a database that is not built in, such as Vertica, returning
+// the new Dialect value. The name is used by String; it and the aliases are also accepted
+// by Pick, ignoring letter case.
+//
+// Register is typically called from an init function. It panics if the name or an alias
+// is empty or is already in use.
+func Register(name string, def DialectDefinition) Dialect {
+	registry.Lock()
+	defer registry.Unlock()
+
+	names := append([]string{name}, def.Aliases...)
+	for _, n := range names {
+		lower := strings.ToLower(n)
+		if lower == "" {
+			panic("dialect: Register called with an empty name")
+		}
+		_, builtIn := dialectNames[lower]
+		_, exists := registry.byName[lower]
+		if builtIn || exists {
+			panic(fmt.Sprintf("dialect: Register called twice for name %q", n))
+		}
+	}
+
+	d := firstRegistered + Dialect(len(registry.definitions))
+	registry.names = append(registry.names, name)
+	registry.definitions = append(registry.definitions, def)
+	for _, n := range names {
+		registry.byName[strings.ToLower(n)] = d
+	}
+	return d
+}
+
+// registered gets the definition of a registered dialect.
+func registered(d Dialect) (DialectDefinition, string, bool) {
+	if d < firstRegistered {
+		return DialectDefinition{}, "", false
+	}
+
+	registry.RLock()
+	defer registry.RUnlock()
+
+	i := int(d - firstRegistered)
+	if i >= len(registry.definitions) {
+		return DialectDefinition{}, "", false
+	}
+	return registry.definitions[i], registry.names[i], true
+}
+
+// pickRegistered finds a registered dialect by its lower-case name.
+func pickRegistered(name string) (Dialect, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	d, ok := registry.byName[name]
+	return d, ok
+}
+
+// registeredNames lists the lower-case names of all registered dialects.
+func registeredNames() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.byName))
+	for n := range registry.byName {
+		names = append(names, n)
+	}
+	return names
+}
@@ -0,0 +1,167 @@
+// Package jsonpred provides where-expressions for filtering on JSON columns. Each condition
+// is rendered for the dialect in use when it is formatted:
+//
+//   - Postgres uses the jsonb operators @> and ->> and the jsonb_path_exists function;
+//   - MySQL and MariaDB use JSON_CONTAINS, JSON_CONTAINS_PATH and JSON_EXTRACT;
+//   - SQLite uses json_type and json_extract;
+//   - other dialects use the standard JSON_EXISTS and JSON_VALUE functions.
+//
+// JSONContains has no equivalent except for Postgres, MySQL and MariaDB. For any other
+// dialect, it is rendered using JSON_CONTAINS, which the database will reject;
+// where.VerifyDialectSupport reports this in advance.
+//
+// Paths are written in the SQL/JSON path syntax shared by these databases, e.g. "$.a.b"
+// or "$.tags[0]". For Postgres, JSONExtractEq accepts only paths consisting of member
+// names and array indexes.
+package jsonpred
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+type kind int
+
+const (
+	contains kind = iota
+	pathExists
+	extractEq
+)
+
+// condition is a JSON condition, rendered according to the dialect.
+type condition struct {
+	kind   kind
+	column string
+	path   string
+	value  any
+	err    error // from marshalling the document
+}
+
+// JSONContains returns a condition that a JSON column contains a document, i.e. that the
+// document's members and array elements are all present in the column's value. The
+// document is either JSON text (a string or []byte) or any value that can be marshalled
+// as JSON. Only Postgres, MySQL and MariaDB support this (see where.VerifyDialectSupport).
+//
+// If the document cannot be marshalled, the condition is invalid: where.CheckPlaceholders
+// and where.Validate report the error. The document is then bound unchanged.
+func JSONContains(column string, doc any) where.Expression {
+	text, err := jsonText(doc)
+	if err != nil {
+		return where.Wrap(condition{kind: contains, column: column, value: doc, err: err})
+	}
+	return where.Wrap(condition{kind: contains, column: column, value: text})
+}
+
+// JSONPathExists returns a condition that a JSON column has a value at the path.
+func JSONPathExists(column, path string) where.Expression {
+	return where.Wrap(condition{kind: pathExists, column: column, path: path})
+}
+
+// JSONExtractEq returns a condition that the value at the path in a JSON column equals the
+// value given. The comparison is made as text, so the value is bound as a string; for
+// example, the number 10 matches the JSON number 10 and the string "10".
+func JSONExtractEq(column, path string, value any) where.Expression {
+	if _, isString := value.(string); !isString {
+		value = fmt.Sprint(value)
+	}
+	return where.Wrap(condition{kind: extractEq, column: column, path: path, value: value})
+}
+
+func jsonText(doc any) (string, error) {
+	switch d := doc.(type) {
+	case string:
+		return d, nil
+	case []byte:
+		return string(d), nil
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("jsonpred: JSONContains document: %w", err)
+	}
+	return string(b), nil
+}
+
+// Validate implements where.Validator.
+func (c condition) Validate() error {
+	return c.err
+}
+
+// Format formats the condition, returning the formatted string and the list of arguments.
+func (c condition) Format(option ...dialect.FormatOption) (string, []any) {
+	d := where.ResolveConfig(option...).Dialect
+	column := where.QuoteColumn(c.column, option...)
+
+	var sql string
+	var args []any
+
+	switch {
+	case d == dialect.Postgres:
+		sql, args = c.postgres(column)
+	case d == dialect.Mysql, d == dialect.MariaDB, c.kind == contains:
+		// the other dialects have no form of contains; VerifyDialectSupport reports them
+		sql, args = c.mysql(column)
+	case d == dialect.Sqlite:
+		sql, args = c.sqlite(column)
+	default:
+		sql, args = c.standard(column)
+	}
+
+	return where.Predicate(sql, args...).Format(option...)
+}
+
+func (c condition) postgres(column string) (string, []any) {
+	switch c.kind {
+	case contains:
+		return column + " @> ?::jsonb", []any{c.value}
+	case pathExists:
+		return "jsonb_path_exists(" + column + ", ?::jsonpath)", []any{c.path}
+	}
+
+	keys := pathKeys(c.path)
+	if len(keys) == 1 {
+		return column + "->>?=?", []any{keys[0], c.value}
+	}
+	return column + "#>>?=?", []any{"{" + strings.Join(keys, ",") + "}", c.value}
+}
+
+func (c condition) mysql(column string) (string, []any) {
+	switch c.kind {
+	case contains:
+		return "JSON_CONTAINS(" + column + ", ?)", []any{c.value}
+	case pathExists:
+		return "JSON_CONTAINS_PATH(" + column + ", 'one', ?)", []any{c.path}
+	}
+	return "JSON_UNQUOTE(JSON_EXTRACT(" + column + ", ?))=?", []any{c.path, c.value}
+}
+
+func (c condition) sqlite(column string) (string, []any) {
+	switch c.kind {
+	case pathExists:
+		return "json_type(" + column + ", ?) IS NOT NULL", []any{c.path}
+	}
+	return "CAST(json_extract(" + column + ", ?) AS TEXT)=?", []any{c.path, c.value}
+}
+
+func (c condition) standard(column string) (string, []any) {
+	switch c.kind {
+	case pathExists:
+		return "JSON_EXISTS(" + column + ", ?)", []any{c.path}
+	}
+	return "JSON_VALUE(" + column + ", ?)=?", []any{c.path, c.value}
+}
+
+// pathKeys splits a path such as "$.a.b[0]" into its keys, i.e. "a", "b" and "0".
+func pathKeys(path string) []string {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	return strings.FieldsFunc(path, func(r rune) bool { return r == '.' })
+}
+
+func (c condition) String() string {
+	sql, _ := c.Format(dialect.NoQuotes, dialect.Inline)
+	return sql
+}
@@ -0,0 +1,82 @@
+package jsonpred_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/jsonpred"
+)
+
+func TestJSONContains(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Eq("id", 1).And(jsonpred.JSONContains("attrs", map[string]any{"colour": "red"}))
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar, dialect.ANSIQuotes)
+	g.Expect(sql).To(Equal(` WHERE "id"=$1 AND ("attrs" @> $2::jsonb)`))
+	g.Expect(args).To(Equal([]any{1, `{"colour":"red"}`}))
+
+	sql, args = where.Where(jsonpred.JSONContains("attrs", `[1,2]`), dialect.Mysql, dialect.Backticks)
+	g.Expect(sql).To(Equal(" WHERE JSON_CONTAINS(`attrs`, ?)"))
+	g.Expect(args).To(Equal([]any{`[1,2]`}))
+
+	// other dialects cannot express this, which is reported rather than hidden
+	sql, _ = where.Where(jsonpred.JSONContains("attrs", `[1]`), dialect.Sqlite)
+	g.Expect(sql).To(Equal(` WHERE JSON_CONTAINS(attrs, ?)`))
+	g.Expect(jsonpred.JSONContains("attrs", `[1]`).String()).To(Equal(`JSON_CONTAINS(attrs, '[1]')`))
+	g.Expect(where.CheckPlaceholders(jsonpred.JSONContains("attrs", `[1]`))).To(Succeed())
+
+	issues := where.VerifyDialectSupport(wh, dialect.Sqlite)
+	g.Expect(issues).To(HaveLen(1))
+	g.Expect(issues[0].Feature).To(Equal("JSON_CONTAINS"))
+	g.Expect(where.VerifyDialectSupport(wh, dialect.Postgres)).To(BeEmpty())
+	g.Expect(where.VerifyDialectSupport(wh, dialect.MariaDB)).To(BeEmpty())
+}
+
+func TestJSONContains_invalidDocument(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := jsonpred.JSONContains("attrs", map[string]any{"f": func() {}})
+
+	g.Expect(where.CheckPlaceholders(wh)).To(MatchError(ContainSubstring("jsonpred: JSONContains document: json: unsupported type")))
+	g.Expect(where.Validate(wh, []string{"attrs"})).To(HaveOccurred())
+}
+
+func TestJSONPathExists(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := jsonpred.JSONPathExists("attrs", "$.size")
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE jsonb_path_exists(attrs, $1::jsonpath)`))
+	g.Expect(args).To(Equal([]any{"$.size"}))
+
+	sql, _ = where.Where(wh, dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE JSON_CONTAINS_PATH(attrs, 'one', ?)`))
+
+	sql, _ = where.Where(wh, dialect.Sqlite, where.WithTableAlias("p"))
+	g.Expect(sql).To(Equal(` WHERE json_type(p.attrs, ?) IS NOT NULL`))
+
+	sql, _ = where.Where(wh, dialect.DB2)
+	g.Expect(sql).To(Equal(` WHERE JSON_EXISTS(attrs, ?)`))
+}
+
+func TestJSONExtractEq(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sql, args := where.Where(jsonpred.JSONExtractEq("attrs", "$.size", 10), dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE attrs->>$1=$2`))
+	g.Expect(args).To(Equal([]any{"size", "10"}))
+
+	sql, args = where.Where(jsonpred.JSONExtractEq("attrs", "$.dims[0].w", "x"), dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE attrs#>>$1=$2`))
+	g.Expect(args).To(Equal([]any{"{dims,0,w}", "x"}))
+
+	sql, args = where.Where(jsonpred.JSONExtractEq("attrs", "$.size", "L"), dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE JSON_UNQUOTE(JSON_EXTRACT(attrs, ?))=?`))
+	g.Expect(args).To(Equal([]any{"$.size", "L"}))
+
+	g.Expect(jsonpred.JSONExtractEq("attrs", "$.size", "L").String()).To(Equal(`CAST(json_extract(attrs, '$.size') AS TEXT)='L'`))
+}
@@ -3,12 +3,20 @@ package predicate
 const (
 	IsNull               = " IS NULL"
 	IsNotNull            = " IS NOT NULL"
+	IsTrue               = "=TRUE"
+	IsFalse              = "=FALSE"
 	EqualTo              = "=?"
 	NotEqualTo           = "<>?"
+	NotDistinctFrom      = " IS NOT DISTINCT FROM ?"
 	GreaterThan          = ">?"
 	GreaterThanOrEqualTo = ">=?"
 	LessThan             = "<?"
 	LessThanOrEqualTo    = "<=?"
 	Between              = " BETWEEN ? AND ?"
+	NotBetween           = " NOT BETWEEN ? AND ?"
+	BetweenSymmetric     = " BETWEEN SYMMETRIC ? AND ?"
 	Like                 = " LIKE ?"
+	NotLike              = " NOT LIKE ?"
+	ILike                = " ILIKE ?"
+	Regexp               = " REGEXP ?"
 )
@@ -26,32 +26,74 @@ var ascDesc = []string{
 type orderingTerm struct {
 	column string
 	dir    int
+	rank   bool // column is the document of a full-text search
+	query  any  // the search, for rank terms
 }
 
-type QueryConstraint struct {
+// QueryConstraint is a constraint on a query, typically appended to it after any
+// WHERE clause. Constraint is the standard implementation, providing ORDER BY, LIMIT
+// and OFFSET; other implementations can supply extra dialect-specific clauses such
+// as hints or sampling.
+type QueryConstraint interface {
+	// Format formats the constraint for the dialect. The result is blank or begins with a space.
+	Format(d dialect.Dialect, option ...dialect.FormatOption) string
+
+	// FormatTOP formats the part of the constraint, if any, that is placed after
+	// "SELECT [DISTINCT] " and before the list of column names. Only SQL-Server uses this.
+	FormatTOP(d dialect.Dialect) string
+}
+
+// Constraint is the standard query constraint, providing ORDER BY, LIMIT and OFFSET
+// clauses (and TOP for SQL-Server). Its methods use a fluent style; start with OrderBy,
+// Limit or Offset.
+type Constraint struct {
 	orderBy       []orderingTerm
 	nulls         int
-	limit, offset int
+	limit, offset int64
+}
+
+var _ QueryConstraint = &Constraint{}
+
+// Format formats the SQL expressions. Format cannot return arguments, so the search
+// text of any OrderByRank is inlined as a quoted literal; use FormatArgs instead to
+// have it passed as an argument.
+func (qc *Constraint) Format(d dialect.Dialect, option ...dialect.FormatOption) string {
+	s, _ := qc.FormatArgs(d, inlined(option)...)
+	return s
 }
 
-//var _ QueryConstraint = &queryConstraint{}
+// inlined adds the option to inline the values, so there are no arguments.
+func inlined(option []dialect.FormatOption) []dialect.FormatOption {
+	return append(option[:len(option):len(option)], WithPlaceholder(dialect.Inline))
+}
 
-// Format formats the SQL expressions.
-func (qc *QueryConstraint) Format(d dialect.Dialect, option ...dialect.FormatOption) string {
+// FormatArgs formats the SQL expressions, as for Format, also returning the arguments
+// needed by OrderByRank, if any. Use WithPlaceholderOffset if numbered placeholders are
+// needed after those of the WHERE clause.
+func (qc *Constraint) FormatArgs(d dialect.Dialect, option ...dialect.FormatOption) (string, []any) {
 	if qc == nil {
-		return ""
+		return "", nil
 	}
 
+	return qc.format(newConfig(dialect.FormatConfig{Dialect: d}, option))
+}
+
+func (qc *Constraint) format(c config) (string, []any) {
+	s, args := qc.compose(c)
+	return replacePlaceholders(s, args, c, c.PlaceholderOffset+1)
+}
+
+func (qc *Constraint) compose(c config) (string, []any) {
 	b := new(strings.Builder)
 	b.Grow(qc.estimateStringLength())
+	var args []any
 
-	q := quoterFromOptions(formatOptions(option).Quoter())
-
-	if len(qc.orderBy) > 0 {
-		b.WriteString(" ORDER BY")
+	terms := qc.orderTerms(c.Dialect)
+	if len(terms) > 0 {
+		b.WriteString(c.keyword(" ORDER BY"))
 		hasDesc := false
 
-		for _, col := range qc.orderBy {
+		for _, col := range terms {
 			if col.dir == desc {
 				hasDesc = true
 				break
@@ -59,58 +101,134 @@ func (qc *QueryConstraint) Format(d dialect.Dialect, option ...dialect.FormatOpt
 		}
 
 		sep := " "
-		for _, col := range qc.orderBy {
+		for _, col := range terms {
 			b.WriteString(sep)
-			q.QuoteW(b, col.column)
+			if col.rank {
+				args = append(args, qc.writeRank(b, c, col)...)
+			} else if strings.HasPrefix(col.column, rawPrefix) {
+				b.WriteString(col.column[len(rawPrefix):])
+			} else {
+				c.Quoter.QuoteW(b, col.column)
+			}
 			if hasDesc {
-				b.WriteString(ascDesc[col.dir])
+				b.WriteString(c.keyword(ascDesc[col.dir]))
 			}
 			sep = ", "
 		}
 
 		switch qc.nulls {
 		case first:
-			b.WriteString(" NULLS FIRST")
+			b.WriteString(c.keyword(" NULLS FIRST"))
 		case last:
-			b.WriteString(" NULLS LAST")
+			b.WriteString(c.keyword(" NULLS LAST"))
 		}
 	}
 
-	if qc.limit > 0 && d != dialect.SqlServer {
-		b.WriteString(" LIMIT ")
-		b.WriteString(strconv.Itoa(qc.limit))
+	switch c.Dialect.LimitStyle() {
+	case dialect.LimitOffset:
+		qc.writeNumber(b, c, " LIMIT ", qc.limit, "")
+		qc.writeNumber(b, c, " OFFSET ", qc.offset, "")
+
+	case dialect.Top:
+		qc.writeNumber(b, c, " OFFSET ", qc.offset, "")
+
+	case dialect.FetchFirst:
+		qc.writeNumber(b, c, " OFFSET ", qc.offset, " ROWS")
+		qc.writeNumber(b, c, " FETCH FIRST ", qc.limit, " ROWS ONLY")
+
+	case dialect.Rows:
+		if qc.limit > 0 {
+			qc.writeNumber(b, c, " ROWS ", qc.offset+1, "")
+			qc.writeNumber(b, c, " TO ", qc.offset+qc.limit, "")
+		} else {
+			qc.writeNumber(b, c, " OFFSET ", qc.offset, " ROWS")
+		}
 	}
 
-	if qc.offset > 0 {
-		b.WriteString(" OFFSET ")
-		b.WriteString(strconv.Itoa(qc.offset))
+	if b.Len() > 0 {
+		b.WriteString(c.comment())
 	}
 
-	return b.String()
+	return b.String(), args
 }
 
-// FormatTOP formats the SQL 'TOP' expression using the given dialect. Only SQL-Server uses this;
+// orderTerms gets the ordering terms, omitting any rank terms that the dialect cannot express.
+func (qc *Constraint) orderTerms(d dialect.Dialect) []orderingTerm {
+	switch d {
+	case dialect.Postgres, dialect.Mysql, dialect.MariaDB:
+		return qc.orderBy
+	}
+
+	terms := make([]orderingTerm, 0, len(qc.orderBy))
+	for _, col := range qc.orderBy {
+		if !col.rank {
+			terms = append(terms, col)
+		}
+	}
+	return terms
+}
+
+// writeRank writes the relevance score of a full-text search, returning its argument.
+func (qc *Constraint) writeRank(b *strings.Builder, c config, col orderingTerm) []any {
+	if c.Dialect == dialect.Mysql || c.Dialect == dialect.MariaDB {
+		b.WriteString(c.keyword("MATCH ("))
+		for i, column := range strings.Split(col.column, ",") {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			c.Quoter.QuoteW(b, strings.TrimSpace(column))
+		}
+		b.WriteString(c.keyword(") AGAINST (?)"))
+	} else {
+		b.WriteString("ts_rank(")
+		c.Quoter.QuoteW(b, col.column)
+		b.WriteString(", plainto_tsquery(?))")
+	}
+
+	if c.Placeholder == dialect.Named {
+		return namedSlots("rank", []any{col.query})
+	}
+	return []any{col.query}
+}
+
+func (qc *Constraint) writeNumber(b *strings.Builder, c config, before string, n int64, after string) {
+	if n > 0 {
+		b.WriteString(c.keyword(before))
+		b.WriteString(strconv.FormatInt(n, 10))
+		b.WriteString(c.keyword(after))
+	}
+}
+
+// FormatTOP formats the SQL 'TOP' expression using the given dialect. Only the dialects whose
+// limit style is dialect.Top (e.g. SQL-Server) or dialect.SkipFirst (e.g. Informix) use this;
 // for other dialects, it returns an empty string. Insert the returned string into your query
 // after "SELECT [DISTINCT] " and before the list of column names.
-func (qc *QueryConstraint) FormatTOP(d dialect.Dialect) string {
+func (qc *Constraint) FormatTOP(d dialect.Dialect) string {
 	if qc == nil {
 		return ""
 	}
 
-	if d != dialect.SqlServer || qc.limit == 0 {
-		return ""
-	}
-
 	b := new(strings.Builder)
-	b.Grow(12)
-	b.WriteString(" TOP (")
-	b.WriteString(strconv.Itoa(qc.limit))
-	b.WriteString(")")
+	c := config{FormatConfig: dialect.FormatConfig{Dialect: d}}
+
+	switch d.LimitStyle() {
+	case dialect.Top:
+		if qc.limit > 0 {
+			b.Grow(12)
+			b.WriteString(" TOP (")
+			b.WriteString(strconv.FormatInt(qc.limit, 10))
+			b.WriteString(")")
+		}
+
+	case dialect.SkipFirst:
+		qc.writeNumber(b, c, " SKIP ", qc.offset, "")
+		qc.writeNumber(b, c, " FIRST ", qc.limit, "")
+	}
 
 	return b.String()
 }
 
-func (qc *QueryConstraint) estimateStringLength() (n int) {
+func (qc *Constraint) estimateStringLength() (n int) {
 	if len(qc.orderBy) > 0 {
 		n += 14 // " ORDER BY" and " DESC"
 		for _, col := range qc.orderBy {
@@ -129,6 +247,6 @@ func (qc *QueryConstraint) estimateStringLength() (n int) {
 	return n
 }
 
-func (qc *QueryConstraint) String() string {
-	return qc.Format(dialect.DefaultDialect)
+func (qc *Constraint) String() string {
+	return qc.Format(dialect.DefaultDialect, dialect.Inline)
 }
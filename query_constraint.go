@@ -4,28 +4,44 @@ package where
 // The columns passed in here will be quoted according to the quoter in use when built.
 // Be careful not to allow injection attacks: do not include a string from an external
 // source in the columns.
-func OrderBy(column ...string) *QueryConstraint {
-	return &QueryConstraint{orderBy: makeTerms(column)}
+func OrderBy(column ...string) *Constraint {
+	return &Constraint{orderBy: makeTerms(column)}
+}
+
+// OrderByRank orders the results by their relevance to a full-text search, most relevant
+// first. See Constraint.OrderByRank.
+func OrderByRank(document string, query any) *Constraint {
+	return new(Constraint).OrderByRank(document, query)
 }
 
 // Limit sets the upper limit on the number of records to be returned.
 // The default value, 0, suppresses any limit.
 //
 // As a special case, for SQL-Server, this produces the 'TOP' expression (see FormatTOP).
-func Limit(n int) *QueryConstraint {
-	return &QueryConstraint{limit: n}
+func Limit(n int) *Constraint {
+	return &Constraint{limit: int64(n)}
+}
+
+// Limit64 is as for Limit, but accepts 64-bit values regardless of the platform.
+func Limit64(n int64) *Constraint {
+	return &Constraint{limit: n}
 }
 
 // Offset sets the offset into the result set; previous items will be discarded.
-func Offset(n int) *QueryConstraint {
-	return &QueryConstraint{offset: n}
+func Offset(n int) *Constraint {
+	return &Constraint{offset: int64(n)}
+}
+
+// Offset64 is as for Offset, but accepts 64-bit values regardless of the platform.
+func Offset64(n int64) *Constraint {
+	return &Constraint{offset: n}
 }
 
 // OrderBy lists the column(s) by which the database will be asked to sort its results.
 // The columns passed in here will be quoted according to the needs of the selected dialect.
 // Be careful not to allow injection attacks: do not include a string from an external
 // source in the columns.
-func (qc *QueryConstraint) OrderBy(column ...string) *QueryConstraint {
+func (qc *Constraint) OrderBy(column ...string) *Constraint {
 	// previous unset columns default to asc
 	for i := 0; i < len(qc.orderBy); i++ {
 		if qc.orderBy[i].dir == unset {
@@ -37,6 +53,22 @@ func (qc *QueryConstraint) OrderBy(column ...string) *QueryConstraint {
 	return qc
 }
 
+// OrderByRank adds ordering by relevance to a full-text search, most relevant first. The
+// query is the search text, which is passed as an argument; use FormatArgs to obtain it
+// (Format inlines it instead).
+//
+//   - For PostgreSQL, the document is a tsvector column and the ordering term is
+//     "ts_rank(document, plainto_tsquery(?)) DESC".
+//   - For MySQL and MariaDB, the document lists the columns of a FULLTEXT index, separated by commas,
+//     and the ordering term is "MATCH (columns) AGAINST (?) DESC".
+//
+// Other dialects have no standard equivalent, so the term is omitted for them.
+func (qc *Constraint) OrderByRank(document string, query any) *Constraint {
+	qc.OrderBy()
+	qc.orderBy = append(qc.orderBy, orderingTerm{column: document, dir: desc, rank: true, query: query})
+	return qc
+}
+
 func makeTerms(column []string) []orderingTerm {
 	terms := make([]orderingTerm, len(column))
 	for i, c := range column {
@@ -45,7 +77,7 @@ func makeTerms(column []string) []orderingTerm {
 	return terms
 }
 
-func (qc *QueryConstraint) setDirection(dir int) *QueryConstraint {
+func (qc *Constraint) setDirection(dir int) *Constraint {
 	for i := len(qc.orderBy) - 1; i >= 0; i-- {
 		if qc.orderBy[i].dir == unset {
 			qc.orderBy[i].dir = dir
@@ -58,20 +90,20 @@ func (qc *QueryConstraint) setDirection(dir int) *QueryConstraint {
 
 // Asc sets the sort order to be ascending for the columns specified previously,
 // not including those already set.
-func (qc *QueryConstraint) Asc() *QueryConstraint {
+func (qc *Constraint) Asc() *Constraint {
 	return qc.setDirection(asc)
 }
 
 // Desc sets the sort order to be descending for the columns specified previously,
 // not including those already set.
-func (qc *QueryConstraint) Desc() *QueryConstraint {
+func (qc *Constraint) Desc() *Constraint {
 	return qc.setDirection(desc)
 }
 
 // NullsFirst can be used to control whether nulls appear before non-null values
 // in the sort ordering. By default, null values sort as if larger than any non-null value;
 // that is, NULLS FIRST is the default for DESC order, and NULLS LAST otherwise.
-func (qc *QueryConstraint) NullsFirst() *QueryConstraint {
+func (qc *Constraint) NullsFirst() *Constraint {
 	qc.nulls = first
 	return qc
 }
@@ -79,20 +111,32 @@ func (qc *QueryConstraint) NullsFirst() *QueryConstraint {
 // NullsLast can be used to control whether nulls appear after non-null values
 // in the sort ordering. By default, null values sort as if larger than any non-null value;
 // that is, NULLS FIRST is the default for DESC order, and NULLS LAST otherwise.
-func (qc *QueryConstraint) NullsLast() *QueryConstraint {
+func (qc *Constraint) NullsLast() *Constraint {
 	qc.nulls = last
 	return qc
 }
 
 // Limit sets the upper limit on the number of records to be returned.
-func (qc *QueryConstraint) Limit(n int) *QueryConstraint {
+func (qc *Constraint) Limit(n int) *Constraint {
+	qc.limit = int64(n)
+	return qc
+}
+
+// Limit64 is as for Limit, but accepts 64-bit values regardless of the platform.
+func (qc *Constraint) Limit64(n int64) *Constraint {
 	qc.limit = n
 	return qc
 }
 
 // Offset sets the offset into the result set. The database will skip earlier records.
 // It is usually important to set the order of results explicitly (see OrderBy).
-func (qc *QueryConstraint) Offset(n int) *QueryConstraint {
+func (qc *Constraint) Offset(n int) *Constraint {
+	qc.offset = int64(n)
+	return qc
+}
+
+// Offset64 is as for Offset, but accepts 64-bit values regardless of the platform.
+func (qc *Constraint) Offset64(n int64) *Constraint {
 	qc.offset = n
 	return qc
 }
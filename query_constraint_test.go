@@ -8,10 +8,11 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/rickb777/where/v2"
 	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/quote"
 )
 
 var queryConstraintAnsiQuoteCases = []struct {
-	qc  *where.QueryConstraint
+	qc  *where.Constraint
 	exp string
 }{
 	{exp: "", qc: nil},
@@ -37,7 +38,7 @@ var queryConstraintAnsiQuoteCases = []struct {
 	{exp: ` ORDER BY "foo" DESC NULLS LAST LIMIT 10 OFFSET 20`, qc: where.OrderBy("foo").Desc().Limit(10).Offset(20).NullsLast()},
 }
 
-var topConstraintCases = map[string]*where.QueryConstraint{
+var topConstraintCases = map[string]*where.Constraint{
 	`1`:          nil,
 	`2`:          where.Limit(0),
 	`3 TOP (10)`: where.Limit(10),
@@ -98,10 +99,52 @@ func TestQueryConstraint_SqlServer(t *testing.T) {
 	}
 }
 
+func TestQueryConstraint_FetchStyles(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		qc                 *where.Constraint
+		db2, firebird, ifx string
+		ifxTop             string
+	}{
+		{
+			qc:       where.OrderBy("name").Limit(10),
+			db2:      ` ORDER BY "name" FETCH FIRST 10 ROWS ONLY`,
+			firebird: ` ORDER BY "name" ROWS 1 TO 10`,
+			ifx:      ` ORDER BY name`,
+			ifxTop:   ` FIRST 10`,
+		},
+		{
+			qc:       where.Limit(10).Offset(20),
+			db2:      ` OFFSET 20 ROWS FETCH FIRST 10 ROWS ONLY`,
+			firebird: ` ROWS 21 TO 30`,
+			ifxTop:   ` SKIP 20 FIRST 10`,
+		},
+		{
+			qc:       where.Offset(20),
+			db2:      ` OFFSET 20 ROWS`,
+			firebird: ` OFFSET 20 ROWS`,
+			ifxTop:   ` SKIP 20`,
+		},
+	}
+
+	for _, c := range cases {
+		g.Expect(c.qc.Format(dialect.DB2, where.WithQuoter(dialect.DB2.Quoter()))).To(Equal(c.db2))
+		g.Expect(c.qc.FormatTOP(dialect.DB2)).To(BeEmpty())
+		g.Expect(c.qc.Format(dialect.Firebird, where.WithQuoter(dialect.Firebird.Quoter()))).To(Equal(c.firebird))
+		g.Expect(c.qc.Format(dialect.Informix, where.WithQuoter(dialect.Informix.Quoter()))).To(Equal(c.ifx))
+		g.Expect(c.qc.FormatTOP(dialect.Informix)).To(Equal(c.ifxTop))
+	}
+
+	s := where.Limit(5).Offset(1).Format(dialect.DB2, where.WithKeywordCase(dialect.LowerCase))
+	g.Expect(s).To(Equal(` offset 1 rows fetch first 5 rows only`))
+	g.Expect(dialect.Pick("IFX")).To(Equal(dialect.Informix))
+}
+
 func TestNilQueryConstraint_SqlServer(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	var qc where.QueryConstraint
+	var qc where.Constraint
 
 	top := qc.FormatTOP(dialect.SqlServer)
 	sql := qc.Format(dialect.SqlServer)
@@ -129,7 +172,7 @@ func ExampleOrderBy() {
 	//
 }
 
-func ExampleQueryConstraint_NullsLast() {
+func ExampleConstraint_NullsLast() {
 	// OrderBy also includes a "NULLS LAST" phrase.
 	qc := where.OrderBy("foo").NullsLast()
 
@@ -140,7 +183,7 @@ func ExampleQueryConstraint_NullsLast() {
 	// Output:  ORDER BY "foo" NULLS LAST
 }
 
-func ExampleQueryConstraint_NullsFirst() {
+func ExampleConstraint_NullsFirst() {
 	// OrderBy also includes a "NULLS LAST" phrase.
 	qc := where.OrderBy("foo").NullsFirst()
 
@@ -179,3 +222,64 @@ func ExampleOffset() {
 
 	// Output: OFFSET 20
 }
+
+// sample is a custom query constraint.
+type sample struct {
+	percent int
+	where.QueryConstraint
+}
+
+func (s sample) Format(d dialect.Dialect, option ...dialect.FormatOption) string {
+	return fmt.Sprintf(" TABLESAMPLE SYSTEM (%d)", s.percent) + s.QueryConstraint.Format(d, option...)
+}
+
+func TestCustomQueryConstraint(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var qc where.QueryConstraint = sample{percent: 10, QueryConstraint: where.Limit(5)}
+
+	g.Expect(qc.Format(dialect.Postgres)).To(Equal(` TABLESAMPLE SYSTEM (10) LIMIT 5`))
+	g.Expect(qc.FormatTOP(dialect.Postgres)).To(BeEmpty())
+}
+
+var sybase = dialect.Register("Sybase", dialect.DialectDefinition{Quoter: quote.SquareBrackets, LimitStyle: dialect.Top})
+
+func TestQueryConstraint_RegisteredDialect(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	qc := where.OrderBy("name").Limit(10).Offset(20)
+
+	g.Expect(qc.Format(sybase, where.WithQuoter(sybase.Quoter()))).To(Equal(` ORDER BY [name] OFFSET 20`))
+	g.Expect(qc.FormatTOP(sybase)).To(Equal(` TOP (10)`))
+	g.Expect(dialect.Pick("sybase")).To(Equal(sybase))
+}
+
+func TestQueryConstraint_OrderByRank(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	qc := where.OrderByRank("doc", "fat cat").OrderBy("name").Limit(10)
+
+	s, args := qc.FormatArgs(dialect.Postgres, dialect.Dollar, where.WithPlaceholderOffset(2))
+	g.Expect(s).To(Equal(` ORDER BY ts_rank(doc, plainto_tsquery($3)) DESC, name ASC LIMIT 10`))
+	g.Expect(args).To(Equal([]any{"fat cat"}))
+
+	s, args = where.OrderBy("name").Desc().OrderByRank("title, body", "fat cat").FormatArgs(dialect.Mysql)
+	g.Expect(s).To(Equal(` ORDER BY name DESC, MATCH (title, body) AGAINST (?) DESC`))
+	g.Expect(args).To(Equal([]any{"fat cat"}))
+
+	s, args = qc.FormatArgs(dialect.Sqlite)
+	g.Expect(s).To(Equal(` ORDER BY name LIMIT 10`))
+	g.Expect(args).To(BeNil())
+
+	s = qc.Format(dialect.Postgres, dialect.Inline)
+	g.Expect(s).To(Equal(` ORDER BY ts_rank(doc, plainto_tsquery('fat cat')) DESC, name ASC LIMIT 10`))
+
+	s, args = where.OrderByRank("title", "fat cat").FormatArgs(dialect.MariaDB)
+	g.Expect(s).To(Equal(` ORDER BY MATCH (title) AGAINST (?) DESC`))
+	g.Expect(args).To(Equal([]any{"fat cat"}))
+
+	// Format has no arguments, so the search text is inlined
+	g.Expect(qc.Format(dialect.Postgres, dialect.Dollar)).To(Equal(` ORDER BY ts_rank(doc, plainto_tsquery('fat cat')) DESC, name ASC LIMIT 10`))
+	g.Expect(where.OrderByRank("doc", "it's").Format(dialect.Mysql)).To(Equal(` ORDER BY MATCH (doc) AGAINST ('it''s') DESC`))
+	g.Expect(qc.Format(dialect.Sqlite)).To(Equal(` ORDER BY name LIMIT 10`))
+}
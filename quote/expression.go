@@ -0,0 +1,119 @@
+package quote
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Expression quotes the column names within a simple SQL expression, such as
+// "LOWER(name)" or "COALESCE(p.nickname, p.name)", using the quoter. See MapIdentifiers.
+func Expression(q Quoter, expr string) string {
+	return MapIdentifiers(expr, q.Quote)
+}
+
+// MapIdentifiers finds the column names within a simple SQL expression and replaces each
+// one using fn, leaving everything else unchanged. Prefixed names such as "p.name" are
+// passed to fn whole.
+//
+// This is a tokenizer, not a parser. Names followed by '(' are taken to be functions,
+// and SQL keywords (such as AND, CASE and NULL), the word after AS or COLLATE, string
+// literals, numbers and quoted identifiers are all left unchanged.
+func MapIdentifiers(expr string, fn func(identifier string) string) string {
+	buf := &strings.Builder{}
+	buf.Grow(len(expr) + 8)
+
+	rs := []rune(expr)
+	verbatim := false // the previous word was AS or COLLATE
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case r == '\'' || r == '"' || r == '`' || r == '[':
+			end := closing(rs, i)
+			buf.WriteString(string(rs[i:end]))
+			i = end
+
+		case unicode.IsDigit(r):
+			j := i + 1
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			buf.WriteString(string(rs[i:j]))
+			i = j
+
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(rs) && isNameRune(rs, j) {
+				j++
+			}
+			word := string(rs[i:j])
+			upper := strings.ToUpper(word)
+
+			switch {
+			case verbatim, keywords[upper], isCall(rs, j):
+				buf.WriteString(word)
+			default:
+				buf.WriteString(fn(word))
+			}
+			verbatim = upper == "AS" || upper == "COLLATE"
+			i = j
+
+		default:
+			buf.WriteRune(r)
+			i++
+		}
+	}
+
+	return buf.String()
+}
+
+// isNameRune tests whether the rune at i continues a name, possibly with a prefix.
+func isNameRune(rs []rune, i int) bool {
+	r := rs[i]
+	if r == '.' {
+		return i+1 < len(rs) && (unicode.IsLetter(rs[i+1]) || rs[i+1] == '_')
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$'
+}
+
+// isCall tests whether the next non-space rune after i is '('.
+func isCall(rs []rune, i int) bool {
+	for ; i < len(rs); i++ {
+		if !unicode.IsSpace(rs[i]) {
+			return rs[i] == '('
+		}
+	}
+	return false
+}
+
+// closing finds the end of a quoted string or identifier starting at i. Doubled quote
+// marks within it are escapes.
+func closing(rs []rune, i int) int {
+	end := rs[i]
+	if end == '[' {
+		end = ']'
+	}
+
+	for j := i + 1; j < len(rs); j++ {
+		if rs[j] == end {
+			if j+1 < len(rs) && rs[j+1] == end {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(rs)
+}
+
+// keywords are the words that are never treated as column names.
+var keywords = map[string]bool{
+	"ALL": true, "AND": true, "ANY": true, "AS": true, "ASC": true, "BETWEEN": true,
+	"BOTH": true, "BY": true, "CASE": true, "COLLATE": true, "CURRENT_DATE": true,
+	"CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "DAY": true, "DESC": true,
+	"DISTINCT": true, "ELSE": true, "END": true, "ESCAPE": true, "FALSE": true, "FOR": true,
+	"FROM": true, "HOUR": true, "ILIKE": true, "IN": true, "INTERVAL": true, "IS": true,
+	"LEADING": true, "LIKE": true, "MINUTE": true, "MONTH": true, "NOT": true, "NULL": true,
+	"ON": true, "OR": true, "SECOND": true, "SIMILAR": true, "THEN": true, "TO": true,
+	"TRAILING": true, "TRUE": true, "UNKNOWN": true, "WHEN": true, "YEAR": true,
+}
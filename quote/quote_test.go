@@ -107,3 +107,71 @@ func TestAnsiQuote(t *testing.T) {
 		g.Expect(s2).To(Equal(c.expected))
 	}
 }
+
+func TestPickE(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, name := range Names() {
+		q, err := PickE(strings.ToUpper(name))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(q).To(Equal(Pick(name)))
+	}
+
+	g.Expect(PickE("Backticks")).To(Equal(Backticks))
+
+	q, err := PickE("ansii")
+	g.Expect(err).To(MatchError(HavePrefix(`quote: unknown quoter "ansii"; expected one of ansi, backtick, backticks, ms-sql,`)))
+	g.Expect(q).To(Equal(None))
+}
+
+func TestANSIUnicode(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]string{
+		"":         ``,
+		"ccc":      `"ccc"`,
+		"a.ccc":    `"a"."ccc"`,
+		"_id":      `"_id"`,
+		"2fa":      `"2fa"`,
+		"price$":   `"price$"`,
+		"café":     `U&"caf\00E9"`,
+		"d́jà":     `U&"d\0301j\00E0"`,
+		"t.naïve€": `"t".U&"na\00EFve\20AC"`,
+		"emoji😀":   `U&"emoji\+01F600"`,
+		"a ccc":    `a ccc`,
+		"count(*)": `count(*)`,
+		"a-b":      `a-b`,
+		`we"ird`:   `we"ird`,
+	}
+
+	for identifier, expected := range cases {
+		g.Expect(ANSIUnicode.Quote(identifier)).To(Equal(expected), identifier)
+
+		buf := &strings.Builder{}
+		Pick("unicode").QuoteW(buf, identifier)
+		g.Expect(buf.String()).To(Equal(expected), identifier)
+	}
+}
+
+func TestExpression(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]string{
+		"name":                             `"name"`,
+		"LOWER(name)":                      `LOWER("name")`,
+		"COALESCE(p.nickname, p.name, '')": `COALESCE("p"."nickname", "p"."name", '')`,
+		"lower (name) || 'x''s'":           `lower ("name") || 'x''s'`,
+		"CAST(age AS INTEGER) + 1.5":       `CAST("age" AS INTEGER) + 1.5`,
+		`EXTRACT(YEAR FROM "at")`:          `EXTRACT(YEAR FROM "at")`,
+		"CASE WHEN a IS NULL THEN b END":   `CASE WHEN "a" IS NULL THEN "b" END`,
+		"name COLLATE nocase":              `"name" COLLATE nocase`,
+		"[x] + y":                          `[x] + "y"`,
+	}
+
+	for expr, expected := range cases {
+		g.Expect(Expression(ANSI, expr)).To(Equal(expected), expr)
+	}
+
+	g.Expect(Expression(Backticks, "UPPER(code)")).To(Equal("UPPER(`code`)"))
+	g.Expect(Expression(None, "UPPER(code)")).To(Equal("UPPER(code)"))
+}
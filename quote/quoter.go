@@ -5,6 +5,9 @@
 //   - square brackets used by SQLServer, or
 //   - no quotes at all.
 //
+// There is also a variant of ANSI quoting that uses the SQL-standard U&"..." form for
+// identifiers with non-ASCII characters, e.g. for PostgreSQL and DB2.
+//
 // For prefixed identifiers containing a dot ('.'), the quote marks are applied separately
 // to the prefix(es) and the identifier itself.
 //
@@ -13,9 +16,13 @@
 package quote
 
 import (
+	"fmt"
 	"io"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Quoter wraps identifiers in quote marks. Compound identifiers, i.e. those with an alias
@@ -45,28 +52,66 @@ var (
 
 	// SquareBrackets wraps identifies in '[' and ']'. For MS SQL/SQL-Server.
 	SquareBrackets = quoter{before: "[", between: "].[", after: "]"}
+
+	// ANSIUnicode wraps identifiers in double-quote marks, like ANSI. Identifiers containing
+	// characters outside the usual letters, digits and underscores are quoted too, provided
+	// they don't look like expressions (i.e. they contain no ASCII spaces or punctuation
+	// other than '_' and '$'). Those with non-ASCII characters use the Unicode escape form
+	// U&"..." so that they don't depend on the client encoding. For PostgreSQL, DB2 etc.
+	ANSIUnicode = unicodeQuoter{}
 )
 
 var (
 	// DefaultQuoter does not change identifiers and is used by default.
 	// Change this to affect the default setting for every SQL construction function.
+	// Prefer where.SetDefaults, which is safe for concurrent use.
 	DefaultQuoter = none
 )
 
+var quoterNames = map[string]Quoter{
+	"none":       none,
+	"ansi":       ANSI,
+	"postgres":   ANSI,
+	"sqlite":     ANSI,
+	"sqlite3":    ANSI,
+	"backtick":   Backticks,
+	"backticks":  Backticks,
+	"mysql":      Backticks,
+	"mssql":      SquareBrackets,
+	"ms-sql":     SquareBrackets,
+	"sql-server": SquareBrackets,
+	"unicode":    ANSIUnicode,
+}
+
 // Pick picks a quoter based on the names "ansi", "backtick" (aliases "backticks") or "none",
 // ignoring case. Other options are also permitted: "sqlite", "sqlite3", "postgres",
-// "mysql", "mssql", "ms-sql", "sql-server". The default is none.
+// "mysql", "mssql", "ms-sql", "sql-server", and "unicode" for ANSIUnicode. The default is none.
+//
+// Unknown names silently give none; PickE reports them instead.
 func Pick(name string) Quoter {
-	switch strings.ToLower(name) {
-	case "ansi", "postgres", "sqlite", "sqlite3":
-		return ANSI
-	case "backtick", "backticks", "mysql":
-		return Backticks
-	case "mssql", "ms-sql", "sql-server":
-		return SquareBrackets
-	default:
-		return none
+	q, _ := PickE(name)
+	return q
+}
+
+// PickE picks a quoter in the same way as Pick, except that it returns an error if the
+// name is not known. This is preferable when the name comes from configuration, because
+// a misspelt name would otherwise go unnoticed.
+func PickE(name string) (Quoter, error) {
+	q, ok := quoterNames[strings.ToLower(name)]
+	if !ok {
+		return none, fmt.Errorf("quote: unknown quoter %q; expected one of %s", name, strings.Join(Names(), ", "))
 	}
+	return q, nil
+}
+
+// Names lists the names accepted by Pick and PickE, in alphabetical order.
+func Names() []string {
+	names := make([]string, 0, len(quoterNames))
+	for n := range quoterNames {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
 }
 
 //-------------------------------------------------------------------------------------------------
@@ -121,6 +166,89 @@ func quoteW(w io.StringWriter, before, sep, after string, names ...string) {
 
 //-------------------------------------------------------------------------------------------------
 
+// unicodeQuoter wraps identifiers in double-quote marks, using Unicode escapes where needed.
+type unicodeQuoter struct{}
+
+func (q unicodeQuoter) Quote(identifier string) string {
+	if len(identifier) == 0 {
+		return ""
+	}
+
+	w := new(strings.Builder)
+	w.Grow(len(identifier) + 8)
+	q.QuoteW(w, identifier)
+	return w.String()
+}
+
+func (unicodeQuoter) QuoteW(w io.StringWriter, identifier string) {
+	if len(identifier) > 0 {
+		names := strings.Split(identifier, ".")
+
+		for _, name := range names {
+			// if any name looks like an expression, we leave the entire string unaltered
+			if !validIdentifier.MatchString(name) && !escapableIdentifier(name) {
+				_, _ = w.WriteString(identifier)
+				return
+			}
+		}
+
+		for i, name := range names {
+			if i > 0 {
+				_, _ = w.WriteString(".")
+			}
+			unicodeQuoteW(w, name)
+		}
+	}
+}
+
+// escapableIdentifier tests whether a name can be quoted, i.e. it is not empty and it
+// contains no ASCII spaces or punctuation except '_' and '$'.
+func escapableIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r == '_', r == '$':
+		case r < utf8.RuneSelf && !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			return false
+		case !unicode.IsGraphic(r), unicode.IsSpace(r):
+			return false
+		}
+	}
+	return true
+}
+
+func unicodeQuoteW(w io.StringWriter, name string) {
+	ascii := true
+	for i := 0; i < len(name); i++ {
+		if name[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+
+	if ascii {
+		quoteW(w, `"`, "", `"`, name)
+		return
+	}
+
+	_, _ = w.WriteString(`U&"`)
+	for _, r := range name {
+		switch {
+		case r < utf8.RuneSelf:
+			_, _ = w.WriteString(string(r))
+		case r <= 0xFFFF:
+			_, _ = w.WriteString(fmt.Sprintf(`\%04X`, r))
+		default:
+			_, _ = w.WriteString(fmt.Sprintf(`\+%06X`, r))
+		}
+	}
+	_, _ = w.WriteString(`"`)
+}
+
+//-------------------------------------------------------------------------------------------------
+
 type noQuoter string
 
 func (noQuoter) Quote(identifier string) string              { return identifier }
@@ -0,0 +1,137 @@
+// Package typed provides columns that carry the Go type of their values, so that the
+// conditions built on them are checked by the compiler. For example
+//
+//	var (
+//		Name = typed.Text("name")
+//		Age  = typed.Col[int]("age")
+//	)
+//
+//	wh := Name.Like("F%").And(Age.Between(18, 65))
+//
+// would not compile if, say, Age.Eq("ten") had been written instead. The conditions are
+// ordinary where-expressions, so they can be combined with any others.
+package typed
+
+import (
+	"github.com/rickb777/where/v2"
+)
+
+// Column is a column whose values have type T.
+type Column[T any] struct {
+	name string
+}
+
+// Col returns a column with the given name, whose values have type T.
+func Col[T any](name string) Column[T] {
+	return Column[T]{name: name}
+}
+
+// Name gets the name of the column.
+func (c Column[T]) Name() string {
+	return c.name
+}
+
+// Eq returns an equality condition on the column.
+func (c Column[T]) Eq(value T) where.Expression {
+	return where.Eq(c.name, value)
+}
+
+// NotEq returns a not-equal condition on the column.
+func (c Column[T]) NotEq(value T) where.Expression {
+	return where.NotEq(c.name, value)
+}
+
+// Gt returns a greater than condition on the column.
+func (c Column[T]) Gt(value T) where.Expression {
+	return where.Gt(c.name, value)
+}
+
+// GtEq returns a greater than or equal condition on the column.
+func (c Column[T]) GtEq(value T) where.Expression {
+	return where.GtEq(c.name, value)
+}
+
+// Lt returns a less than condition on the column.
+func (c Column[T]) Lt(value T) where.Expression {
+	return where.Lt(c.name, value)
+}
+
+// LtEq returns a less than or equal condition on the column.
+func (c Column[T]) LtEq(value T) where.Expression {
+	return where.LtEq(c.name, value)
+}
+
+// Between returns a between condition on the column.
+func (c Column[T]) Between(a, b T) where.Expression {
+	return where.Between(c.name, a, b)
+}
+
+// NotBetween returns a not between condition on the column.
+func (c Column[T]) NotBetween(a, b T) where.Expression {
+	return where.NotBetween(c.name, a, b)
+}
+
+// In returns an 'IN' condition on the column. If there are no values, this becomes a no-op.
+func (c Column[T]) In(values ...T) where.Expression {
+	return where.In(c.name, anys(values)...)
+}
+
+// NotIn returns a 'NOT IN' condition on the column. If there are no values, this becomes
+// a no-op.
+func (c Column[T]) NotIn(values ...T) where.Expression {
+	return where.NotIn(c.name, anys(values)...)
+}
+
+// EqOrNull returns an equality condition on the column if the value is not nil,
+// or an 'IS NULL' condition otherwise.
+func (c Column[T]) EqOrNull(value *T) where.Expression {
+	if value == nil {
+		return where.Null(c.name)
+	}
+	return where.Eq(c.name, *value)
+}
+
+// Null returns an 'IS NULL' condition on the column.
+func (c Column[T]) Null() where.Expression {
+	return where.Null(c.name)
+}
+
+// NotNull returns an 'IS NOT NULL' condition on the column.
+func (c Column[T]) NotNull() where.Expression {
+	return where.NotNull(c.name)
+}
+
+func anys[T any](values []T) []any {
+	result := make([]any, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// TextColumn is a column of strings, which also has pattern-matching conditions.
+type TextColumn struct {
+	Column[string]
+}
+
+// Text returns a column of strings with the given name.
+func Text(name string) TextColumn {
+	return TextColumn{Column: Col[string](name)}
+}
+
+// Like returns a pattern-matching condition on the column.
+func (c TextColumn) Like(pattern string) where.Expression {
+	return where.Like(c.name, pattern)
+}
+
+// NotLike returns a negated pattern-matching condition on the column.
+func (c TextColumn) NotLike(pattern string) where.Expression {
+	return where.NotLike(c.name, pattern)
+}
+
+// ILike returns a case-insensitive pattern-matching condition on the column.
+func (c TextColumn) ILike(pattern string) where.Expression {
+	return where.ILike(c.name, pattern)
+}
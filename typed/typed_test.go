@@ -0,0 +1,56 @@
+package typed_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/typed"
+)
+
+var (
+	id      = typed.Col[int64]("id")
+	name    = typed.Text("name")
+	age     = typed.Col[int]("age")
+	created = typed.Col[time.Time]("created")
+)
+
+func TestColumn(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	t0 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	t1 := t0.AddDate(0, 1, 0)
+
+	cases := []struct {
+		wh       where.Expression
+		expected string
+		args     []any
+	}{
+		{id.Eq(1), `id=$1`, []any{int64(1)}},
+		{id.NotEq(1), `id<>$1`, []any{int64(1)}},
+		{age.Gt(18), `age>$1`, []any{18}},
+		{age.GtEq(18), `age>=$1`, []any{18}},
+		{age.Lt(65), `age<$1`, []any{65}},
+		{age.LtEq(65), `age<=$1`, []any{65}},
+		{created.Between(t0, t1), `created BETWEEN $1 AND $2`, []any{t0, t1}},
+		{id.In(1, 2, 3), `id IN ($1,$2,$3)`, []any{int64(1), int64(2), int64(3)}},
+		{id.NotIn(1, 2), `id NOT IN ($1,$2)`, []any{int64(1), int64(2)}},
+		{id.In(), ``, nil},
+		{age.Null(), `age IS NULL`, nil},
+		{age.NotNull(), `age IS NOT NULL`, nil},
+		{age.EqOrNull(nil), `age IS NULL`, nil},
+		{name.Like("F%"), `name LIKE $1`, []any{"F%"}},
+		{name.NotLike("F%"), `name NOT LIKE $1`, []any{"F%"}},
+		{name.Eq("Fred").And(age.Gt(18)), `name=$1 AND age>$2`, []any{"Fred", 18}},
+	}
+
+	for _, c := range cases {
+		sql, args := c.wh.Format(dialect.Dollar)
+		g.Expect(sql).To(Equal(c.expected))
+		g.Expect(args).To(Equal(c.args))
+	}
+
+	g.Expect(name.Name()).To(Equal("name"))
+}
@@ -6,26 +6,49 @@ package where
 
 import (
 	"github.com/rickb777/where/v2/dialect"
-	"github.com/rickb777/where/v2/quote"
 )
 
-// Expression is an element in a WHERE clause. Expressions consist of simple conditions or
-// more complex clauses of multiple conditions.
-type Expression interface {
+// Node is the minimal contract for an element in a WHERE clause. Packages can implement
+// Node to provide their own kinds of expression; these can then be combined with the
+// expressions provided here using And, Or and Not, or adapted using Wrap.
+type Node interface {
 	// String prints the expression with inlined values inserted instead of placeholders.
 	// Column names are not quoted.
 	String() string
 
 	// Format formats the (nested) expression as a string containing placeholders etc.
 	// It doesn't include the WHERE or HAVING conjunction word.
-	Format(option ...dialect.FormatOption) (string, []interface{})
-	// doFormat formats the (nested) expression as a string containing placeholders etc.
-	doFormat(quoter quote.Quoter) (string, []interface{})
+	//
+	// When a node is nested within a clause, this is called with a dialect.FormatConfig
+	// option that specifies dialect.Query placeholders; the placeholders will then be
+	// replaced by the enclosing clause as needed.
+	Format(option ...dialect.FormatOption) (string, []any)
+}
+
+// Expression is an element in a WHERE clause. Expressions consist of simple conditions or
+// more complex clauses of multiple conditions. Expressions are Nodes that also provide
+// methods to combine them fluently.
+type Expression interface {
+	Node
 
 	// And concatenates this expression with another such that both must evaluate true.
-	And(Expression) Expression
+	And(Node) Expression
 	// Or concatenates this expression with another such that either must evaluate true.
-	Or(Expression) Expression
+	Or(Node) Expression
+}
+
+// Wrap adapts a Node so that it can be used as an Expression, i.e. supporting the fluent
+// And and Or methods. Expressions are returned unchanged.
+func Wrap(node Node) Expression {
+	if exp, isExpression := node.(Expression); isExpression {
+		return exp
+	}
+	return wrapper{node: node}
+}
+
+// wrapper adapts foreign nodes to be Expressions.
+type wrapper struct {
+	node Node
 }
 
 const (
@@ -37,7 +60,7 @@ const (
 // If the expression is empty or nil, the returned string will be blank.
 // Optional parameters may be supplied. Otherwise, by default, quote.DefaultQuoter is used
 // and the result will contain '?' style placeholders.
-func Where(wh Expression, option ...dialect.FormatOption) (string, []interface{}) {
+func Where(wh Node, option ...dialect.FormatOption) (string, []interface{}) {
 	return format(whereConjunction, wh, option...)
 }
 
@@ -45,28 +68,66 @@ func Where(wh Expression, option ...dialect.FormatOption) (string, []interface{}
 // If the expression is empty or nil, the returned string will be blank.
 // Optional parameters may be supplied. Otherwise, by default, quote.DefaultQuoter is used
 // and the result will contain '?' style placeholders.
-func Having(wh Expression, option ...dialect.FormatOption) (string, []interface{}) {
+func Having(wh Node, option ...dialect.FormatOption) (string, []interface{}) {
 	return format(havingConjunction, wh, option...)
 }
 
+// WhereAnd constructs the SQL fragment " AND (...)", without any WHERE keyword. This is
+// intended for appending to existing queries that already have a WHERE clause; use
+// WithPlaceholderOffset to continue the numbering of their placeholders, e.g.
+//
+//	sql, args := where.WhereAnd(filter, dialect.Dollar, where.WithPlaceholderOffset(2))
+//	query := "SELECT * FROM t WHERE a=$1 AND b=$2" + sql
+//
+// If the expression is empty or nil, the returned string will be blank.
+func WhereAnd(wh Node, option ...dialect.FormatOption) (string, []any) {
+	return continuation(" AND ", wh, option)
+}
+
+// WhereOr constructs the SQL fragment " OR (...)", without any WHERE keyword, as for WhereAnd.
+// Beware that AND binds more tightly than OR in the existing WHERE clause.
+func WhereOr(wh Node, option ...dialect.FormatOption) (string, []any) {
+	return continuation(" OR ", wh, option)
+}
+
+func continuation(conjunction string, wh Node, option []dialect.FormatOption) (string, []any) {
+	if wh == nil {
+		return "", nil
+	}
+
+	expression, args := formatNodeWith(wh, dialect.FormatConfig{}, option)
+	if expression == "" {
+		return "", nil
+	}
+
+	c := newConfig(dialect.FormatConfig{}, option)
+	return c.keyword(conjunction) + "(" + expression + ")" + c.comment(), args
+}
+
 // format constructs the sql clause beginning with some verb/adverb.
-func format(conjunction string, wh Expression, option ...dialect.FormatOption) (string, []interface{}) {
+func format(conjunction string, wh Node, option ...dialect.FormatOption) (string, []any) {
+	return formatWith(conjunction, wh, dialect.FormatConfig{}, option)
+}
+
+// formatWith constructs the sql clause beginning with some verb/adverb, using a base configuration.
+func formatWith(conjunction string, wh Node, base dialect.FormatConfig, option []dialect.FormatOption) (string, []any) {
 	if wh == nil {
 		return "", nil
 	}
 
-	expression, args := wh.Format(option...)
+	expression, args := formatNodeWith(wh, base, option)
 	if expression == "" {
 		return "", nil
 	}
 
-	return conjunction + expression, args
+	c := newConfig(base, option)
+	return c.keyword(conjunction) + expression + c.comment(), args
 }
 
 //-------------------------------------------------------------------------------------------------
 
 type not struct {
-	expression Expression
+	expression Node
 }
 
 //-------------------------------------------------------------------------------------------------
@@ -97,7 +158,10 @@ type Condition struct {
 
 // Clause is a compound expression. It contains a list of zero or more expressions and
 // notes whether to conjoin them using 'AND' or 'OR'.
+//
+// Clauses are immutable: And and Or return new clauses, so a base clause can be extended
+// in several different ways, and shared between goroutines, without any interference.
 type Clause struct {
-	wheres      []Expression
+	wheres      []Node
 	conjunction string
 }
@@ -0,0 +1,51 @@
+package where
+
+// Builder accumulates conditions imperatively, which suits code that gathers filters
+// across many branches. All the accumulated conditions are AND-ed together by Build.
+// Nil expressions and no-ops are ignored, so no bookkeeping is needed.
+//
+// The zero value is ready to use. A Builder is not safe for concurrent use.
+type Builder struct {
+	wheres []Node
+}
+
+// NewBuilder returns a builder, optionally starting with some conditions.
+func NewBuilder(exp ...Node) *Builder {
+	b := &Builder{}
+	return b.Add(exp...)
+}
+
+// Add adds conditions, which will be AND-ed together.
+func (b *Builder) Add(exp ...Node) *Builder {
+	for _, e := range exp {
+		if e != nil {
+			b.wheres = append(b.wheres, e)
+		}
+	}
+	return b
+}
+
+// AddIf adds conditions only if cond is true.
+func (b *Builder) AddIf(cond bool, exp ...Node) *Builder {
+	if cond {
+		b.Add(exp...)
+	}
+	return b
+}
+
+// Any adds a group of conditions that are OR-ed together, i.e. any of them may be true.
+func (b *Builder) Any(exp ...Node) *Builder {
+	return b.Add(Or(exp...))
+}
+
+// Len gets the number of conditions added so far, including any no-ops.
+func (b *Builder) Len() int {
+	return len(b.wheres)
+}
+
+// Build returns an expression in which all the accumulated conditions are AND-ed.
+// If there are none, a no-op is returned. The builder can still be used afterwards;
+// the returned expression is not affected by subsequent additions.
+func (b *Builder) Build() Expression {
+	return And(b.wheres...)
+}
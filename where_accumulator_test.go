@@ -0,0 +1,34 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestBuilder(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var b where.Builder
+	g.Expect(b.Build().String()).To(BeEmpty())
+
+	b.Add(nameIsFred, nil).
+		AddIf(false, ageGt5Int).
+		AddIf(true, ageLt10Int).
+		Any(where.Eq("colour", "red"), where.Eq("colour", "blue"), nil).
+		Any()
+
+	first := b.Build()
+
+	sql, args := where.Where(first, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE name=$1 AND age<$2 AND (colour=$3 OR colour=$4)`))
+	g.Expect(args).To(Equal([]any{"Fred", 10, "red", "blue"}))
+
+	b.Add(where.Null("x"))
+	g.Expect(b.Build().String()).To(Equal(`name='Fred' AND age<10 AND (colour='red' OR colour='blue') AND x IS NULL`))
+	g.Expect(first.String()).To(Equal(`name='Fred' AND age<10 AND (colour='red' OR colour='blue')`))
+
+	g.Expect(where.NewBuilder(nameIsJohn).Build()).To(Equal(nameIsJohn))
+}
@@ -0,0 +1,152 @@
+package where
+
+import "github.com/rickb777/where/v2/predicate"
+
+// Aggregate returns a condition on an aggregate function of a column, e.g.
+//
+//   - where.Aggregate("SUM", "amount", predicate.GreaterThan, 100)
+//
+// gives the condition SUM("amount")>?. The column is quoted if a formatting option
+// specifies this, but the function name is not. Use "*" as the column for COUNT(*).
+// Such conditions are intended for use with Having.
+//
+// Be careful not to allow injection attacks: do not include a string from an external
+// source in the function or predicate.
+func Aggregate(function, column, predicate string, value ...any) Expression {
+	return AggregateFilter(function, column, nil).Compare(predicate, value...)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// CountEq returns an equality condition on COUNT(column).
+func CountEq(column string, value any) Expression {
+	return Aggregate("COUNT", column, predicate.EqualTo, value)
+}
+
+// CountGt returns a greater than condition on COUNT(column).
+func CountGt(column string, value any) Expression {
+	return Aggregate("COUNT", column, predicate.GreaterThan, value)
+}
+
+// CountGtEq returns a greater than or equal condition on COUNT(column).
+func CountGtEq(column string, value any) Expression {
+	return Aggregate("COUNT", column, predicate.GreaterThanOrEqualTo, value)
+}
+
+// CountLt returns a less than condition on COUNT(column).
+func CountLt(column string, value any) Expression {
+	return Aggregate("COUNT", column, predicate.LessThan, value)
+}
+
+// CountLtEq returns a less than or equal condition on COUNT(column).
+func CountLtEq(column string, value any) Expression {
+	return Aggregate("COUNT", column, predicate.LessThanOrEqualTo, value)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// SumEq returns an equality condition on SUM(column).
+func SumEq(column string, value any) Expression {
+	return Aggregate("SUM", column, predicate.EqualTo, value)
+}
+
+// SumGt returns a greater than condition on SUM(column).
+func SumGt(column string, value any) Expression {
+	return Aggregate("SUM", column, predicate.GreaterThan, value)
+}
+
+// SumGtEq returns a greater than or equal condition on SUM(column).
+func SumGtEq(column string, value any) Expression {
+	return Aggregate("SUM", column, predicate.GreaterThanOrEqualTo, value)
+}
+
+// SumLt returns a less than condition on SUM(column).
+func SumLt(column string, value any) Expression {
+	return Aggregate("SUM", column, predicate.LessThan, value)
+}
+
+// SumLtEq returns a less than or equal condition on SUM(column).
+func SumLtEq(column string, value any) Expression {
+	return Aggregate("SUM", column, predicate.LessThanOrEqualTo, value)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// AvgEq returns an equality condition on AVG(column).
+func AvgEq(column string, value any) Expression {
+	return Aggregate("AVG", column, predicate.EqualTo, value)
+}
+
+// AvgGt returns a greater than condition on AVG(column).
+func AvgGt(column string, value any) Expression {
+	return Aggregate("AVG", column, predicate.GreaterThan, value)
+}
+
+// AvgGtEq returns a greater than or equal condition on AVG(column).
+func AvgGtEq(column string, value any) Expression {
+	return Aggregate("AVG", column, predicate.GreaterThanOrEqualTo, value)
+}
+
+// AvgLt returns a less than condition on AVG(column).
+func AvgLt(column string, value any) Expression {
+	return Aggregate("AVG", column, predicate.LessThan, value)
+}
+
+// AvgLtEq returns a less than or equal condition on AVG(column).
+func AvgLtEq(column string, value any) Expression {
+	return Aggregate("AVG", column, predicate.LessThanOrEqualTo, value)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// MinEq returns an equality condition on MIN(column).
+func MinEq(column string, value any) Expression {
+	return Aggregate("MIN", column, predicate.EqualTo, value)
+}
+
+// MinGt returns a greater than condition on MIN(column).
+func MinGt(column string, value any) Expression {
+	return Aggregate("MIN", column, predicate.GreaterThan, value)
+}
+
+// MinGtEq returns a greater than or equal condition on MIN(column).
+func MinGtEq(column string, value any) Expression {
+	return Aggregate("MIN", column, predicate.GreaterThanOrEqualTo, value)
+}
+
+// MinLt returns a less than condition on MIN(column).
+func MinLt(column string, value any) Expression {
+	return Aggregate("MIN", column, predicate.LessThan, value)
+}
+
+// MinLtEq returns a less than or equal condition on MIN(column).
+func MinLtEq(column string, value any) Expression {
+	return Aggregate("MIN", column, predicate.LessThanOrEqualTo, value)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// MaxEq returns an equality condition on MAX(column).
+func MaxEq(column string, value any) Expression {
+	return Aggregate("MAX", column, predicate.EqualTo, value)
+}
+
+// MaxGt returns a greater than condition on MAX(column).
+func MaxGt(column string, value any) Expression {
+	return Aggregate("MAX", column, predicate.GreaterThan, value)
+}
+
+// MaxGtEq returns a greater than or equal condition on MAX(column).
+func MaxGtEq(column string, value any) Expression {
+	return Aggregate("MAX", column, predicate.GreaterThanOrEqualTo, value)
+}
+
+// MaxLt returns a less than condition on MAX(column).
+func MaxLt(column string, value any) Expression {
+	return Aggregate("MAX", column, predicate.LessThan, value)
+}
+
+// MaxLtEq returns a less than or equal condition on MAX(column).
+func MaxLtEq(column string, value any) Expression {
+	return Aggregate("MAX", column, predicate.LessThanOrEqualTo, value)
+}
@@ -0,0 +1,138 @@
+package where
+
+import (
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// FilteredAggregate is an aggregate function applied only to the rows that match a filter,
+// e.g. "COUNT(*) FILTER (WHERE status=?)". It can be formatted for use in a select list,
+// or compared with a value to make a condition for a HAVING clause (see Compare).
+//
+// For dialects that support it (see dialect.Dialect.SupportsAggregateFilter), the FILTER
+// clause is used. Otherwise, it is emulated using a CASE expression, e.g.
+// "COUNT(CASE WHEN status=? THEN 1 END)" or "SUM(CASE WHEN status=? THEN amount END)".
+//
+// Use AggregateFilter to construct these.
+type FilteredAggregate struct {
+	function, column string
+	filter           Node
+}
+
+// AggregateFilter returns an aggregate function of a column, e.g. "SUM", applied only to
+// the rows that match the filter. Use "*" as the column for COUNT(*). If the filter is nil
+// or empty, the plain aggregate function is used.
+//
+// Be careful not to allow injection attacks: do not include a string from an external
+// source in the function.
+func AggregateFilter(function, column string, filter Node) FilteredAggregate {
+	return FilteredAggregate{function: function, column: column, filter: filter}
+}
+
+// Compare returns a condition on the filtered aggregate, e.g.
+//
+//	where.AggregateFilter("COUNT", "*", where.Eq("status", "late")).Compare(predicate.GreaterThan, 3)
+//
+// gives the condition COUNT(*) FILTER (WHERE status=?)>?. Such conditions are intended
+// for use with Having.
+func (fa FilteredAggregate) Compare(predicate string, value ...any) Expression {
+	return aggregateCondition{aggregate: fa, predicate: predicate, args: value}
+}
+
+// Format formats the filtered aggregate, returning the formatted string and the list of
+// arguments. This suits select lists; use WithPlaceholderOffset if numbered placeholders
+// are needed after it.
+func (fa FilteredAggregate) Format(option ...dialect.FormatOption) (string, []any) {
+	return formatTop(fa, dialect.FormatConfig{}, option)
+}
+
+func (fa FilteredAggregate) doFormat(c config) (string, []any) {
+	var filter string
+	var args []any
+	if fa.filter != nil {
+		filter, args = formatNode(fa.filter, c)
+	}
+
+	buf := &strings.Builder{}
+	buf.WriteString(c.keyword(fa.function))
+	buf.WriteByte('(')
+
+	switch {
+	case filter == "":
+		c.quoteColumn(buf, fa.column)
+		buf.WriteByte(')')
+
+	case c.Dialect.SupportsAggregateFilter():
+		c.quoteColumn(buf, fa.column)
+		buf.WriteString(c.keyword(") FILTER (WHERE "))
+		buf.WriteString(filter)
+		buf.WriteByte(')')
+
+	default:
+		buf.WriteString(c.keyword("CASE WHEN "))
+		buf.WriteString(filter)
+		buf.WriteString(c.keyword(" THEN "))
+		if fa.column == "*" {
+			buf.WriteByte('1')
+		} else {
+			c.quoteColumn(buf, fa.column)
+		}
+		buf.WriteString(c.keyword(" END"))
+		buf.WriteByte(')')
+	}
+
+	return buf.String(), args
+}
+
+// slotName gives the basis for named placeholders, e.g. "sum_amount".
+func (fa FilteredAggregate) slotName() string {
+	function := strings.ToLower(fa.function)
+	if fa.column == "*" {
+		return function
+	}
+	return function + "_" + plainColumn(fa.column)
+}
+
+func (fa FilteredAggregate) String() string {
+	sql, _ := fa.Format(dialect.NoQuotes, dialect.Inline)
+	return sql
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// aggregateCondition compares a filtered aggregate with some value(s).
+type aggregateCondition struct {
+	aggregate FilteredAggregate
+	predicate string
+	args      []any
+}
+
+// And combines two conditions into a clause that requires they are both true.
+func (exp aggregateCondition) And(other Node) Expression {
+	return Clause{wheres: []Node{exp}, conjunction: and}.And(other)
+}
+
+// Or combines two conditions into a clause that requires either is true.
+func (exp aggregateCondition) Or(other Node) Expression {
+	return Clause{wheres: []Node{exp}, conjunction: or}.Or(other)
+}
+
+// Format formats an expression, returning the formatted string and the list of arguments.
+func (exp aggregateCondition) Format(option ...dialect.FormatOption) (string, []any) {
+	return formatTop(exp, dialect.FormatConfig{}, option)
+}
+
+func (exp aggregateCondition) doFormat(c config) (string, []any) {
+	sql, args := exp.aggregate.doFormat(c)
+	predicate, values := c.castTypedArgs(c.spacing(c.keywords(exp.predicate)), exp.args)
+	if c.Placeholder == dialect.Named {
+		values = namedSlots(exp.aggregate.slotName(), values)
+	}
+	return sql + predicate, nilIfEmpty(append(args, values...))
+}
+
+func (exp aggregateCondition) String() string {
+	sql, _ := exp.Format(dialect.NoQuotes, dialect.Inline)
+	return sql
+}
@@ -0,0 +1,43 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/predicate"
+)
+
+func TestAggregateFilter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	late := where.AggregateFilter("COUNT", "*", where.Eq("status", "late"))
+	paid := where.AggregateFilter("SUM", "amount", where.Eq("status", "paid").And(where.Gt("age", 5)))
+
+	s, args := late.Format(dialect.Postgres, dialect.Dollar)
+	g.Expect(s).To(Equal(`COUNT(*) FILTER (WHERE status=$1)`))
+	g.Expect(args).To(Equal([]any{"late"}))
+
+	s, args = late.Format(dialect.Mysql)
+	g.Expect(s).To(Equal(`COUNT(CASE WHEN status=? THEN 1 END)`))
+	g.Expect(args).To(Equal([]any{"late"}))
+
+	s, args = paid.Format(dialect.SqlServer, dialect.SquareBrackets, dialect.AtP)
+	g.Expect(s).To(Equal(`SUM(CASE WHEN [status]=@p1 AND [age]>@p2 THEN [amount] END)`))
+	g.Expect(args).To(Equal([]any{"paid", 5}))
+
+	g.Expect(where.AggregateFilter("MAX", "age", nil).String()).To(Equal(`MAX(age)`))
+
+	wh := late.Compare(predicate.GreaterThan, 3).And(paid.Compare(predicate.LessThan, 100))
+
+	s, args = where.Having(wh, dialect.Postgres, dialect.ANSIQuotes, dialect.Dollar)
+	g.Expect(s).To(Equal(` HAVING COUNT(*) FILTER (WHERE "status"=$1)>$2 AND SUM("amount") FILTER (WHERE "status"=$3 AND "age">$4)<$5`))
+	g.Expect(args).To(Equal([]any{"late", 3, "paid", 5, 100}))
+
+	s, args = where.Having(wh, dialect.Mysql)
+	g.Expect(s).To(Equal(` HAVING COUNT(CASE WHEN status=? THEN 1 END)>? AND SUM(CASE WHEN status=? AND age>? THEN amount END)<?`))
+	g.Expect(args).To(Equal([]any{"late", 3, "paid", 5, 100}))
+
+	g.Expect(wh.String()).To(Equal(`COUNT(*) FILTER (WHERE status='late')>3 AND SUM(amount) FILTER (WHERE status='paid' AND age>5)<100`))
+}
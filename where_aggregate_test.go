@@ -0,0 +1,40 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/predicate"
+)
+
+func TestAggregates(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		wh       where.Expression
+		expected string
+	}{
+		{wh: where.CountGt("*", 5), expected: ` HAVING COUNT(*)>$1`},
+		{wh: where.CountEq("id", 5), expected: ` HAVING COUNT("id")=$1`},
+		{wh: where.SumGtEq("amount", 5), expected: ` HAVING SUM("amount")>=$1`},
+		{wh: where.AvgLt("o.price", 5), expected: ` HAVING AVG("o"."price")<$1`},
+		{wh: where.MinLtEq("age", 5), expected: ` HAVING MIN("age")<=$1`},
+		{wh: where.MaxGt("age", 5), expected: ` HAVING MAX("age")>$1`},
+		{wh: where.Aggregate("COUNT", "DISTINCT x", predicate.NotEqualTo, 5), expected: ` HAVING COUNT(DISTINCT x)<>$1`},
+	}
+
+	for _, c := range cases {
+		sql, args := where.Having(c.wh, dialect.ANSIQuotes, dialect.Dollar)
+		g.Expect(sql).To(Equal(c.expected))
+		g.Expect(args).To(Equal([]any{5}))
+	}
+
+	wh := where.CountGt("*", 1).And(where.SumLt("amount", 100))
+	g.Expect(wh.String()).To(Equal(`COUNT(*)>1 AND SUM(amount)<100`))
+
+	sql, args := where.Having(wh, dialect.Named, where.WithKeywordCase(dialect.LowerCase))
+	g.Expect(sql).To(Equal(` having count(*)>:count_1 and sum(amount)<:sum_amount_1`))
+	g.Expect(args).To(HaveLen(2))
+}
@@ -0,0 +1,156 @@
+package where
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// ArgError reports an argument that the database driver would not accept.
+type ArgError struct {
+	// Node is the node containing the argument.
+	Node Node
+
+	// Index is the position of the argument within the node's arguments.
+	Index int
+
+	// Arg is the offending argument.
+	Arg any
+
+	// Err is the error reported by the value converter.
+	Err error
+}
+
+func (e *ArgError) Error() string {
+	return fmt.Sprintf("where: argument %d (%T) in %s: %v", e.Index, e.Arg, e.Node, e.Err)
+}
+
+func (e *ArgError) Unwrap() error {
+	return e.Err
+}
+
+// CheckArgTypes verifies that every argument in an expression tree is of a type that can
+// be bound as a query parameter. This catches mistakes, such as passing a struct or
+// a slice as a value, that would otherwise only be reported when the query is executed.
+//
+// The converter determines which types are acceptable. If it is nil,
+// driver.DefaultParameterConverter is used, as for database/sql; this accepts the
+// driver.Value types, driver.Valuer implementations and types derived from them.
+//
+// The result is nil if all the arguments are acceptable; otherwise it contains an *ArgError
+// for each offending argument (see errors.As).
+func CheckArgTypes(wh Node, converter driver.ValueConverter) error {
+	if converter == nil {
+		converter = driver.DefaultParameterConverter
+	}
+
+	var errs []error
+	check := func(n Node, args []any) {
+		for i, arg := range args {
+			if _, err := converter.ConvertValue(unwrapArg(arg)); err != nil {
+				errs = append(errs, &ArgError{Node: n, Index: i, Arg: arg, Err: err})
+			}
+		}
+	}
+
+	Walk(wh, func(n Node) bool {
+		switch x := n.(type) {
+		case Condition:
+			check(n, x.Args)
+		case Clause, not, Composite:
+			return true
+		default:
+			_, args := n.Format(dialect.Query)
+			check(n, args)
+		}
+		return true
+	})
+
+	return errors.Join(errs...)
+}
+
+// Placeholders counts the '?' placeholders in the SQL for an expression tree, formatted
+// with the options given. This can be compared with the number of arguments (see Args) to
+// detect predicates that do not match their arguments, before the query is executed.
+//
+// The options matter because some conditions are rendered differently for each dialect,
+// sometimes with repeated placeholders.
+func Placeholders(wh Node, option ...dialect.FormatOption) int {
+	sql, _ := queryForm(wh, option)
+	return countPlaceholders(sql)
+}
+
+// Args gets the arguments for an expression tree, formatted with the options given, in
+// the order of their placeholders. The result is nil if there are none.
+func Args(wh Node, option ...dialect.FormatOption) []any {
+	_, args := queryForm(wh, option)
+	return args
+}
+
+// CheckPlaceholders verifies that every condition in an expression tree has as many arguments as its
+// predicate has '?' placeholders. This catches mistakes, such as Literal("a", "=?") without
+// a value, that would otherwise only be reported by the database driver at execution time.
+// Custom nodes are checked using their Format method unless they implement Composite, in
+// which case their children are checked instead. Custom nodes that implement Validator
+// are also validated, and any error is included in the result.
+//
+// A '?' within a quoted string or identifier is not a placeholder, e.g. in " = 'why?'".
+// Every other '?' is, which means that the Postgres jsonb operators ?, ?| and ?& cannot be
+// written in predicates; use the equivalent functions jsonb_exists, jsonb_exists_any and
+// jsonb_exists_all instead.
+//
+// The result is nil if there are no mismatches; otherwise the error wraps ErrArgCount for
+// each one.
+func CheckPlaceholders(wh Node) error {
+	var errs []error
+	check := func(n Node, predicate string, args []any) {
+		if p := countPlaceholders(predicate); p != len(args) {
+			errs = append(errs, fmt.Errorf("%w: %d placeholders but %d arguments in %s", ErrArgCount, p, len(args), n))
+		}
+	}
+
+	Walk(wh, func(n Node) bool {
+		if v, ok := n.(Validator); ok {
+			if err := v.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		switch x := n.(type) {
+		case Condition:
+			check(n, x.Predicate, x.Args)
+		case aggregateCondition:
+			check(n, x.predicate, x.args)
+		case Clause, not, Composite:
+			return true
+		default:
+			sql, args := n.Format(dialect.Query)
+			check(n, sql, args)
+		}
+		return true
+	})
+
+	return errors.Join(errs...)
+}
+
+// queryForm formats an expression tree with '?' placeholders, whatever the options specify.
+func queryForm(wh Node, option []dialect.FormatOption) (string, []any) {
+	if wh == nil {
+		return "", nil
+	}
+	option = append(option[:len(option):len(option)], WithPlaceholder(dialect.Query))
+	return formatNode(wh, newConfig(dialect.FormatConfig{}, option))
+}
+
+// unwrapArg gets the value that would be bound, using the default identifier binding.
+func unwrapArg(arg any) any {
+	switch a := arg.(type) {
+	case TypedArg:
+		return unwrapArg(a.Value)
+	case idArg:
+		return a.bind(dialect.IDAsString)
+	}
+	return arg
+}
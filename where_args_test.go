@@ -0,0 +1,118 @@
+package where_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+type point struct{ x, y int }
+
+// intsOnly is a value converter that accepts only integers.
+type intsOnly struct{}
+
+func (intsOnly) ConvertValue(v any) (driver.Value, error) {
+	if i, ok := v.(int); ok {
+		return int64(i), nil
+	}
+	return nil, errors.New("not an int")
+}
+
+func TestCheckArgTypes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	good := where.And(nameIsFred, ageGt5Int, where.Eq("born", time.Now()), where.Eq("level", size("L")),
+		where.Eq("id", where.Typed("x", "uuid")), where.EqID("ref", "f47ac10b-58cc-0372-8567-0e02b2c3d479"),
+		where.Eq("data", []byte{1}), where.Null("x"))
+	g.Expect(where.CheckArgTypes(good, nil)).To(Succeed())
+
+	bad := where.Between("at", point{1, 2}, 3)
+	err := where.CheckArgTypes(where.Or(nameIsFred, where.Not(bad), where.Eq("ids", []int{1, 2})), nil)
+	g.Expect(err).To(HaveOccurred())
+
+	var argErr *where.ArgError
+	g.Expect(errors.As(err, &argErr)).To(BeTrue())
+	g.Expect(argErr.Node).To(Equal(bad))
+	g.Expect(argErr.Index).To(Equal(0))
+	g.Expect(argErr.Arg).To(Equal(point{1, 2}))
+	g.Expect(err.Error()).To(ContainSubstring(`where: argument 0 (where_test.point) in at BETWEEN`))
+	g.Expect(err.Error()).To(ContainSubstring(`where: argument 0 ([]int) in ids=`))
+
+	err = where.CheckArgTypes(where.Wrap(tagsContain{1, "b"}), intsOnly{})
+	g.Expect(errors.As(err, &argErr)).To(BeTrue())
+	g.Expect(argErr.Index).To(Equal(1))
+	g.Expect(argErr.Unwrap()).To(MatchError("not an int"))
+}
+
+func TestPlaceholdersAndArgs(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(nameIsFred, where.Between("age", 12, 18), where.Null("city"), where.Wrap(tagsContain{1, 2}))
+	g.Expect(where.Placeholders(wh, dialect.Dollar)).To(Equal(5))
+	g.Expect(where.Args(wh, dialect.Dollar)).To(Equal([]any{"Fred", 12, 18, 1, 2}))
+
+	// inlining does not affect the result
+	g.Expect(where.Placeholders(wh, dialect.Inline)).To(Equal(5))
+	g.Expect(where.Args(wh, dialect.Inline)).To(HaveLen(5))
+
+	// some conditions depend on the dialect
+	safe := where.EqNullSafe("name", "Fred")
+	g.Expect(where.Placeholders(safe, dialect.Postgres)).To(Equal(1))
+	g.Expect(where.Placeholders(safe, dialect.SqlServer)).To(Equal(2))
+	g.Expect(where.Args(safe, dialect.SqlServer)).To(Equal([]any{"Fred", "Fred"}))
+
+	mismatched := where.Predicate("a=? AND b=?", 1)
+	g.Expect(where.Placeholders(mismatched)).To(Equal(2))
+	g.Expect(where.Args(mismatched)).To(Equal([]any{1}))
+
+	g.Expect(where.Placeholders(nil)).To(Equal(0))
+	g.Expect(where.Args(where.NoOp())).To(BeNil())
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	t0 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	valid := where.And(
+		nameIsFred, where.Between("age", 1, 2), where.In("x", 1, nil), where.NotIn("y", 1, 2),
+		where.EqNullSafe("a", 1), where.LikeEscape("b", "x%", '!'), where.WithinLast("c", time.Hour),
+		where.OnDate("d", t0), where.EqAnyOf("e", []int{1, 2}), where.Exists("SELECT 1 WHERE z=?", 1),
+		where.AggregateFilter("SUM", "f", where.Eq("g", 1)).Compare(">?", 2), where.Wrap(tagsContain{1, 2}),
+	)
+	g.Expect(where.CheckPlaceholders(valid)).To(Succeed())
+	g.Expect(where.CheckPlaceholders(nil)).To(Succeed())
+
+	err := where.CheckPlaceholders(where.And(where.Literal("a", "=?"), where.Or(nameIsFred, where.Predicate("b=?", 1, 2))))
+	g.Expect(err).To(MatchError(where.ErrArgCount))
+	g.Expect(err.Error()).To(Equal("where: wrong number of arguments: 1 placeholders but 0 arguments in a=?\n" +
+		"where: wrong number of arguments: 1 placeholders but 2 arguments in b=1"))
+
+	err = where.CheckPlaceholders(where.Wrap(tagsContain{1}))
+	g.Expect(err).To(MatchError(where.ErrArgCount))
+}
+
+func TestCheck_quoted(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	quoted := where.Literal("note", " = 'why?' OR note=?", "x").And(where.Literal(`"a?"`, "=?", 1))
+	g.Expect(where.CheckPlaceholders(quoted)).To(Succeed())
+	g.Expect(where.Placeholders(quoted)).To(Equal(2))
+
+	sql, args, err := where.WhereE(quoted, dialect.Dollar)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sql).To(Equal(` WHERE note = 'why?' OR note=$1 AND "a?"=$2`))
+	g.Expect(args).To(Equal([]any{"x", 1}))
+
+	sql, _ = where.Where(quoted, dialect.Inline)
+	g.Expect(sql).To(Equal(` WHERE note = 'why?' OR note='x' AND "a?"=1`))
+
+	g.Expect(where.ReplacePlaceholders(`a='?' AND b=?`, dialect.Dollar)).To(Equal(`a='?' AND b=$1`))
+
+	// the jsonb operators are placeholders
+	g.Expect(where.CheckPlaceholders(where.Literal("data", " ? 'key'"))).To(MatchError(where.ErrArgCount))
+}
@@ -0,0 +1,183 @@
+package where
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/predicate"
+)
+
+const (
+	alwaysTrue  = "TRUE"
+	alwaysFalse = "FALSE"
+)
+
+// True returns a condition that is always true. This is rendered as "TRUE", or as "1=1"
+// for dialects that don't support boolean literals (see dialect.SupportsBooleanLiterals).
+func True() Expression {
+	return Condition{Predicate: alwaysTrue}
+}
+
+// False returns a condition that is always false. This is rendered as "FALSE", or as "1=0"
+// for dialects that don't support boolean literals (see dialect.SupportsBooleanLiterals).
+func False() Expression {
+	return Condition{Predicate: alwaysFalse}
+}
+
+// IsTrue returns a condition that a boolean column is true. This is rendered as "column=TRUE",
+// or as "column=1" for dialects that don't support boolean literals.
+func IsTrue(column string) Expression {
+	return Literal(column, predicate.IsTrue)
+}
+
+// IsFalse returns a condition that a boolean column is false. This is rendered as "column=FALSE",
+// or as "column=0" for dialects that don't support boolean literals.
+func IsFalse(column string) Expression {
+	return Literal(column, predicate.IsFalse)
+}
+
+// numericBooleans gives the replacements for predicates containing boolean literals.
+var numericBooleans = map[string]string{
+	alwaysTrue:        "1=1",
+	alwaysFalse:       "1=0",
+	predicate.IsTrue:  "=1",
+	predicate.IsFalse: "=0",
+}
+
+// adapt rewrites a condition to allow for dialect differences, so that the same condition
+// gives valid SQL for every dialect:
+//
+//   - boolean literals are replaced by 1 and 0 for dialects without them;
+//   - equality with an empty string is replaced by a test for null for dialects that treat
+//     empty strings as null, as Oracle does, for which such equality is never true;
+//   - IS NOT DISTINCT FROM is replaced by the equivalent for dialects without it;
+//   - backslash escape characters are doubled in LIKE...ESCAPE for MySQL and MariaDB;
+//   - the date arithmetic of WithinLast is rendered for the dialect;
+//   - BETWEEN SYMMETRIC is emulated for dialects other than Postgres;
+//   - REGEXP is replaced by the operator or function for the dialect;
+//   - ILIKE is replaced by LIKE, with both sides in lower case, for dialects without it;
+//   - IN lists are rendered using an array or VALUES for Postgres, if required (see
+//     WithInArray and WithInValues).
+func (c config) adapt(exp Condition) Condition {
+	if !c.Dialect.SupportsBooleanLiterals() {
+		if replacement, ok := numericBooleans[exp.Predicate]; ok {
+			exp.Predicate = replacement
+			return exp
+		}
+	}
+
+	if c.Dialect.EmptyStringIsNull() && len(exp.Args) == 1 && exp.Args[0] == "" {
+		switch exp.Predicate {
+		case predicate.EqualTo:
+			exp.Predicate = predicate.IsNull
+			exp.Args = nil
+		case predicate.NotEqualTo:
+			exp.Predicate = predicate.IsNotNull
+			exp.Args = nil
+		}
+	}
+
+	if exp.Predicate == predicate.NotDistinctFrom && len(exp.Args) == 1 {
+		switch c.Dialect {
+		case dialect.Mysql, dialect.MariaDB:
+			exp.Predicate = " <=> ?"
+		case dialect.SqlServer, dialect.Informix:
+			column := c.quotedColumn(exp.Column)
+			exp.Column = ""
+			exp.Predicate = "(" + column + "=? OR (" + column + " IS NULL AND ? IS NULL))"
+			exp.Args = []any{exp.Args[0], exp.Args[0]}
+		}
+	}
+
+	if strings.HasPrefix(exp.Predicate, likeEscape) && (c.Dialect == dialect.Mysql || c.Dialect == dialect.MariaDB) {
+		exp.Predicate = strings.ReplaceAll(exp.Predicate, `\`, `\\`)
+	}
+
+	if strings.HasPrefix(exp.Predicate, intervalPrefix) && strings.HasSuffix(exp.Predicate, intervalSuffix) {
+		exp.Predicate = adaptInterval(exp.Predicate, c.Dialect)
+	}
+
+	if exp.Predicate == predicate.BetweenSymmetric && len(exp.Args) == 2 && c.Dialect != dialect.Postgres {
+		exp = c.betweenSymmetric(exp)
+	}
+
+	if exp.Predicate == predicate.Regexp {
+		switch c.Dialect {
+		case dialect.Postgres:
+			exp.Predicate = " ~ ?"
+		case dialect.SqlServer, dialect.DB2:
+			exp.Predicate = "REGEXP_LIKE(" + c.quotedColumn(exp.Column) + ", ?)"
+			exp.Column = ""
+		}
+	}
+
+	if exp.Predicate == predicate.ILike && !c.Dialect.SupportsILike() {
+		exp.Predicate = c.keyword("LOWER(") + c.quotedColumn(exp.Column) + c.keyword(") LIKE LOWER(?)")
+		exp.Column = ""
+	}
+
+	if c.Dialect == dialect.Postgres && isInList(exp.Predicate, len(exp.Args)) {
+		switch {
+		case c.InArray && c.Placeholder != dialect.Inline && !hasTypedArg(exp.Args):
+			exp.Predicate = "=ANY(?)"
+			exp.Args = []any{arrayArg(exp.Args, c.Dialect)}
+		case c.InValues > 0 && len(exp.Args) >= c.InValues:
+			exp.Predicate = " IN (VALUES " + strings.Repeat("(?),", len(exp.Args)-1) + "(?))"
+		}
+	}
+
+	return exp
+}
+
+// betweenSymmetric emulates BETWEEN SYMMETRIC by comparing both ways round. The limits
+// are not put in order here, even when they could be: the SQL must not depend on the
+// values, so that it can be cached and compiled (see Cache and Compile). Besides, the
+// database collation decides the order of strings.
+func (c config) betweenSymmetric(exp Condition) Condition {
+	a, b := exp.Args[0], exp.Args[1]
+	column := c.quotedColumn(exp.Column)
+	exp.Column = ""
+	exp.Predicate = "(" + column + predicate.Between + " OR " + column + predicate.Between + ")"
+	exp.Args = []any{a, b, b, a}
+	return exp
+}
+
+// arrayArg makes a slice holding the values, ready to be bound (see typedSlice).
+func arrayArg(values []any, d dialect.Dialect) any {
+	return typedSlice(bindArgs(values, d))
+}
+
+// typedSlice makes a slice holding the values. If all the values have the same type, the
+// slice has that element type, e.g. []string; otherwise it is []any.
+func typedSlice(values []any) any {
+	if len(values) == 0 {
+		return values
+	}
+	t := reflect.TypeOf(values[0])
+	if t == nil {
+		return values
+	}
+	for _, v := range values[1:] {
+		if reflect.TypeOf(v) != t {
+			return values
+		}
+	}
+
+	slice := reflect.MakeSlice(reflect.SliceOf(t), len(values), len(values))
+	for i, v := range values {
+		slice.Index(i).Set(reflect.ValueOf(v))
+	}
+	return slice.Interface()
+}
+
+// dialectLiteral renders a value inline, allowing for dialects without boolean literals.
+func dialectLiteral(v any, d dialect.Dialect) string {
+	if b, ok := v.(bool); ok && !d.SupportsBooleanLiterals() {
+		if b {
+			return "1"
+		}
+		return "0"
+	}
+	return literalValue(v)
+}
@@ -0,0 +1,189 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/quote"
+)
+
+// oracle is registered as Oracle treats empty strings as null, unlike the built-in dialects.
+var oracle = dialect.Register("Oracle", dialect.DialectDefinition{
+	Aliases:           []string{"godror"},
+	Quoter:            quote.ANSI,
+	Placeholder:       dialect.Named,
+	LimitStyle:        dialect.FetchFirst,
+	NullsOrdering:     true,
+	Sequences:         true,
+	EmptyStringIsNull: true,
+})
+
+func TestBooleanConditions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(where.IsTrue("active"), where.IsFalse("deleted"), where.True().Or(where.False()))
+
+	sql, args := where.Where(wh, dialect.Postgres)
+	g.Expect(sql).To(Equal(` WHERE active=TRUE AND deleted=FALSE AND (TRUE OR FALSE)`))
+	g.Expect(args).To(BeNil())
+
+	sql, _ = where.Where(wh, dialect.SqlServer)
+	g.Expect(sql).To(Equal(` WHERE active=1 AND deleted=0 AND (1=1 OR 1=0)`))
+
+	sql, _ = where.Where(where.Eq("active", true).And(where.Eq("name", "x")), dialect.SqlServer, dialect.Inline)
+	g.Expect(sql).To(Equal(` WHERE active=1 AND name='x'`))
+
+	sql, _ = where.Where(where.Eq("active", true), dialect.Mysql, dialect.Inline)
+	g.Expect(sql).To(Equal(` WHERE active=true`))
+
+	g.Expect(where.VerifyDialectSupport(wh, dialect.SqlServer)).To(BeEmpty())
+	g.Expect(where.True().String()).To(Equal(`TRUE`))
+}
+
+func TestEmptyStringConditions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Eq("name", "").And(where.NotEq("title", "")).And(where.Eq("code", "x"))
+
+	sql, args := where.Where(wh, dialect.ConfigFor(oracle))
+	g.Expect(sql).To(Equal(` WHERE "name" IS NULL AND "title" IS NOT NULL AND "code"=:code_1`))
+	g.Expect(args).To(HaveLen(1))
+
+	sql, args = where.Where(wh, dialect.ConfigFor(dialect.Postgres))
+	g.Expect(sql).To(Equal(` WHERE "name"=$1 AND "title"<>$2 AND "code"=$3`))
+	g.Expect(args).To(Equal([]any{"", "", "x"}))
+}
+
+func TestInValues(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.In("id", 1, 2, 3).And(where.In("x", 4))
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar, where.WithInValues(3))
+	g.Expect(sql).To(Equal(` WHERE id IN (VALUES ($1),($2),($3)) AND x IN ($4)`))
+	g.Expect(args).To(Equal([]any{1, 2, 3, 4}))
+
+	sql, _ = where.Where(wh, dialect.Mysql, where.WithInValues(3))
+	g.Expect(sql).To(Equal(` WHERE id IN (?,?,?) AND x IN (?)`))
+
+	sql, _ = where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE id IN ($1,$2,$3) AND x IN ($4)`))
+}
+
+func TestInArray(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.In("id", 1, 2, 3).And(where.In("name", "a", 2)).And(where.Eq("x", 4))
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar, where.WithInArray())
+	g.Expect(sql).To(Equal(` WHERE id=ANY($1) AND name=ANY($2) AND x=$3`))
+	g.Expect(args).To(Equal([]any{[]int{1, 2, 3}, []any{"a", 2}, 4}))
+
+	sql, args = where.Where(where.In("id", 5, 6), dialect.Postgres, dialect.Dollar, where.WithInArray(), where.WithInValues(1))
+	g.Expect(sql).To(Equal(` WHERE id=ANY($1)`))
+	g.Expect(args).To(Equal([]any{[]int{5, 6}}))
+
+	sql, _ = where.Where(wh, dialect.Mysql, where.WithInArray())
+	g.Expect(sql).To(Equal(` WHERE id IN (?,?,?) AND name IN (?,?) AND x=?`))
+
+	sql, args = where.Where(wh, dialect.Postgres, dialect.Inline, where.WithInArray())
+	g.Expect(sql).To(Equal(` WHERE id IN (1,2,3) AND name IN ('a',2) AND x=4`))
+	g.Expect(args).To(BeNil())
+}
+
+func TestILike(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.ILike("name", "fr%")
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE name ILIKE $1`))
+	g.Expect(args).To(Equal([]any{"fr%"}))
+
+	sql, _ = where.Where(wh, dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE LOWER(name) LIKE LOWER(?)`))
+
+	sql, _ = where.Where(wh, dialect.SqlServer, dialect.AtP, dialect.SquareBrackets, where.WithKeywordCase(dialect.LowerCase))
+	g.Expect(sql).To(Equal(` where lower([name]) like lower(@p1)`))
+
+	g.Expect(wh.String()).To(Equal(`LOWER(name) LIKE LOWER('fr%')`))
+}
+
+func TestEqNullSafe(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.EqNullSafe("a", 1).And(where.Eq("b", 2))
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE a IS NOT DISTINCT FROM $1 AND b=$2`))
+	g.Expect(args).To(Equal([]any{1, 2}))
+
+	sql, _ = where.Where(wh, dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE a <=> ? AND b=?`))
+
+	sql, args = where.Where(wh, dialect.SqlServer, dialect.AtP, dialect.SquareBrackets, where.WithTableAlias("t"))
+	g.Expect(sql).To(Equal(` WHERE ([t].[a]=@p1 OR ([t].[a] IS NULL AND @p2 IS NULL)) AND [t].[b]=@p3`))
+	g.Expect(args).To(Equal([]any{1, 1, 2}))
+}
+
+func TestRegexp(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Regexp("name", "^F")
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE name ~ $1`))
+	g.Expect(args).To(Equal([]any{"^F"}))
+
+	sql, _ = where.Where(wh, dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE name REGEXP ?`))
+
+	sql, _ = where.Where(wh, dialect.DB2, dialect.ANSIQuotes)
+	g.Expect(sql).To(Equal(` WHERE REGEXP_LIKE("name", ?)`))
+
+	g.Expect(where.VerifyDialectSupport(wh, dialect.SqlServer)).To(BeEmpty())
+}
+
+func TestBetweenSymmetric(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.BetweenSymmetric("age", 18, 12)
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE age BETWEEN SYMMETRIC $1 AND $2`))
+	g.Expect(args).To(Equal([]any{18, 12}))
+
+	// the SQL does not depend on the values
+	sql, args = where.Where(wh, dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE (age BETWEEN ? AND ? OR age BETWEEN ? AND ?)`))
+	g.Expect(args).To(Equal([]any{18, 12, 12, 18}))
+
+	sql, args = where.Where(where.BetweenSymmetric("name", "b", "a"), dialect.SqlServer, dialect.AtP)
+	g.Expect(sql).To(Equal(` WHERE (name BETWEEN @p1 AND @p2 OR name BETWEEN @p3 AND @p4)`))
+	g.Expect(args).To(Equal([]any{"b", "a", "a", "b"}))
+
+	sql, args = where.Where(where.NotBetween("age", 12, 18), dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE age NOT BETWEEN ? AND ?`))
+	g.Expect(args).To(Equal([]any{12, 18}))
+}
+
+func TestLikeEscape(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.LikeEscape("name", where.EscapeLike(`50%_off\`, '\\')+"%", '\\')
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE name LIKE $1 ESCAPE '\'`))
+	g.Expect(args).To(Equal([]any{`50\%\_off\\%`}))
+
+	sql, _ = where.Where(wh, dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE name LIKE ? ESCAPE '\\'`))
+
+	sql, args = where.Where(where.LikeEscape("code", "a!%%", '!'), dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE code LIKE ? ESCAPE '!'`))
+	g.Expect(args).To(Equal([]any{"a!%%"}))
+
+	g.Expect(where.LikeEscape("code", "x", '\'').String()).To(Equal(`code LIKE 'x' ESCAPE ''''`))
+}
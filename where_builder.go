@@ -1,7 +1,9 @@
 package where
 
 import (
+	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/rickb777/where/v2/predicate"
@@ -19,6 +21,25 @@ func Predicate(predicate string, value ...any) Expression {
 	return Condition{Predicate: predicate, Args: value}
 }
 
+// Exists returns an 'EXISTS' condition on a sub-query, e.g.
+//
+//   - where.Exists(`SELECT 1 FROM orders WHERE orders.customer_id = customers.id AND total > ?`, 100)
+//
+// The sub-query must use '?' placeholders. Its arguments are merged with those of the
+// enclosing expression, in order, and are numbered accordingly when the expression is
+// formatted.
+//
+// Be careful not to allow injection attacks: do not include a string from an external
+// source in the sub-query.
+func Exists(subquery string, value ...any) Expression {
+	return Predicate("EXISTS ("+subquery+")", value...)
+}
+
+// NotExists returns a 'NOT EXISTS' condition on a sub-query, as for Exists.
+func NotExists(subquery string, value ...any) Expression {
+	return Predicate("NOT EXISTS ("+subquery+")", value...)
+}
+
 // Literal returns a literal condition on a column. For example
 //
 //   - where.Literal("age", " > 45")
@@ -29,11 +50,45 @@ func Predicate(predicate string, value ...any) Expression {
 // Be careful not to allow injection attacks: do not include a string from an external
 // source in the column or predicate.
 //
+// Every '?' in the predicate is a placeholder, except within a quoted string or identifier.
+// So the Postgres jsonb operators ?, ?| and ?& cannot be used; use the functions
+// jsonb_exists, jsonb_exists_any and jsonb_exists_all instead.
+//
 // This function is the basis for most other predicates.
 func Literal(column, predicate string, value ...any) Expression {
 	return Condition{Column: column, Predicate: predicate, Args: value}
 }
 
+// Expr returns a condition on a column expression, such as a function call, e.g.
+//
+//   - where.Expr("LOWER(name)", "=?", "fred")
+//
+// Unlike Literal, the column names within the expression are quoted and given any table
+// alias, as for plain columns, e.g. giving LOWER("name")=$1. Function names, keywords
+// and literals are left unchanged (see quote.MapIdentifiers).
+//
+// Be careful not to allow injection attacks: do not include a string from an external
+// source in the expression or predicate.
+func Expr(expression, predicate string, value ...any) Expression {
+	return Condition{Column: exprPrefix + expression, Predicate: predicate, Args: value}
+}
+
+// Raw marks a column, or a column expression, that must be used exactly as given, without
+// quoting and without any table alias. It can be passed to any function that accepts a
+// column, e.g.
+//
+//   - where.Eq(where.Raw("COALESCE(nickname, name)"), "Fred")
+//   - where.OrderBy(where.Raw("Name"))
+//
+// Otherwise, columns are quoted unless they do not look like identifiers, which depends
+// on the quoter.
+//
+// Be careful not to allow injection attacks: do not include a string from an external
+// source in the column.
+func Raw(column string) string {
+	return rawPrefix + column
+}
+
 // Null returns an 'IS NULL' condition on a column.
 func Null(column string) Expression {
 	return Literal(column, predicate.IsNull)
@@ -49,11 +104,42 @@ func Eq(column string, value any) Expression {
 	return Literal(column, predicate.EqualTo, value)
 }
 
+// EqOrNull returns an equality condition on a column if the value is not nil, or an
+// 'IS NULL' condition if it is. Pointers are dereferenced, so a nil pointer also gives
+// 'IS NULL'. This suits optional fields, e.g.
+//
+//	var nickname *string // nil when absent
+//	where.EqOrNull("nickname", nickname)
+func EqOrNull(column string, value any) Expression {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return Null(column)
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return Null(column)
+	}
+	return Eq(column, v.Interface())
+}
+
 // NotEq returns a not equal condition on a column.
 func NotEq(column string, value any) Expression {
 	return Literal(column, predicate.NotEqualTo, value)
 }
 
+// EqNullSafe returns a null-safe equality condition on a column, which is true when both
+// sides are null, unlike Eq. This is rendered according to the dialect:
+//   - "IS NOT DISTINCT FROM ?" for Postgres, SQLite and most others;
+//   - "<=> ?" for MySQL and MariaDB;
+//   - "(column=? OR (column IS NULL AND ? IS NULL))" for SQL-Server and Informix,
+//     binding the value twice.
+func EqNullSafe(column string, value any) Expression {
+	return Literal(column, predicate.NotDistinctFrom, value)
+}
+
 // Gt returns a greater than condition on a column.
 func Gt(column string, value any) Expression {
 	return Literal(column, predicate.GreaterThan, value)
@@ -79,11 +165,73 @@ func Between(column string, a, b any) Expression {
 	return Literal(column, predicate.Between, a, b)
 }
 
+// NotBetween returns a negated range condition on a column, i.e. "NOT BETWEEN ? AND ?".
+func NotBetween(column string, a, b any) Expression {
+	return Literal(column, predicate.NotBetween, a, b)
+}
+
+// BetweenSymmetric returns a range condition on a column that holds whichever way round
+// the limits are given. This is rendered as "BETWEEN SYMMETRIC ? AND ?" for Postgres. For
+// other dialects, the limits are compared both ways round, i.e.
+// "(column BETWEEN ? AND ? OR column BETWEEN ? AND ?)", so each limit is bound twice and
+// there are four arguments instead of two.
+func BetweenSymmetric(column string, a, b any) Expression {
+	return Literal(column, predicate.BetweenSymmetric, a, b)
+}
+
 // Like returns a pattern-matching condition on a column. Be careful: this can hurt performance.
 func Like(column string, pattern string) Expression {
 	return Literal(column, predicate.Like, pattern)
 }
 
+// LikeEscape returns a pattern-matching condition on a column, as for Like, in which the
+// escape character marks the following '%' or '_' as a literal character rather than a
+// wildcard, e.g. "LIKE ? ESCAPE '\'". See EscapeLike.
+func LikeEscape(column string, pattern string, escape rune) Expression {
+	return Literal(column, likeEscape+strings.ReplaceAll(string(escape), "'", "''")+"'", pattern)
+}
+
+const likeEscape = " LIKE ? ESCAPE '"
+
+// EscapeLike escapes the wildcards '%' and '_', and the escape character itself, in a
+// string, so that it can be matched literally using LikeEscape. This allows patterns to
+// be built safely from user input, e.g.
+//
+//	where.LikeEscape("name", where.EscapeLike(prefix, '\\')+"%", '\\')
+func EscapeLike(s string, escape rune) string {
+	e := string(escape)
+	return strings.NewReplacer(e, e+e, "%", e+"%", "_", e+"_").Replace(s)
+}
+
+// NotLike returns a negated pattern-matching condition on a column, i.e. "NOT LIKE ?".
+// This is preferable to negating Like, which gives "NOT (column LIKE ?)".
+func NotLike(column string, pattern string) Expression {
+	return Literal(column, predicate.NotLike, pattern)
+}
+
+// ILike returns a case-insensitive pattern-matching condition on a column. This is
+// rendered as "ILIKE ?" for Postgres, or as "LOWER(column) LIKE LOWER(?)" for dialects
+// that lack ILIKE (see dialect.SupportsILike).
+//
+// Beware that the dialect must be given, e.g. dialect.Postgres or
+// dialect.ConfigFor(dialect.Postgres). The dialect.Dollar flag selects only the
+// placeholders, so on its own it gives the LOWER form of the default dialect (see
+// dialect.DefaultDialect), which is SQLite unless altered.
+func ILike(column string, pattern string) Expression {
+	return Literal(column, predicate.ILike, pattern)
+}
+
+// Regexp returns a regular-expression matching condition on a column. This is rendered
+// according to the dialect:
+//   - "~ ?" for Postgres;
+//   - "REGEXP ?" for MySQL, MariaDB and SQLite (which needs a regexp function to be loaded);
+//   - "REGEXP_LIKE(column, ?)" for SQL-Server (since 2025) and DB2.
+//
+// The regular-expression syntax itself varies between databases.
+func Regexp(column string, pattern string) Expression {
+	return Literal(column, predicate.Regexp, pattern)
+}
+
 // In returns an 'IN' condition on a column.
 //   - If there are no values, this becomes a no-op.
 //   - If any value is nil, an 'IS NULL' expression is OR-ed with the 'IN' expression.
@@ -176,7 +324,7 @@ func InSlice(column string, arg any) Expression {
 
 	result := NoOp()
 	if len(v) > 0 {
-		result = Condition{column, buf.String(), v}
+		result = Condition{Column: column, Predicate: buf.String(), Args: v}
 	}
 
 	if hasNull {
@@ -186,6 +334,126 @@ func InSlice(column string, arg any) Expression {
 	return result
 }
 
+// NotIn returns a 'NOT IN' condition on a column.
+//   - If there are no values, this becomes a no-op.
+//   - If any value is nil, an 'IS NOT NULL' expression is AND-ed with the 'NOT IN' expression.
+//
+// This differs from negating In, which gives "NOT (col IN (...))" and which excludes no
+// rows when a nil value is present.
+func NotIn(column string, values ...any) Expression {
+	args := make([]any, 0, len(values))
+	hasNull := false
+	for _, arg := range values {
+		if arg == nil {
+			hasNull = true
+		} else {
+			args = append(args, arg)
+		}
+	}
+
+	result := NoOp()
+	if len(args) > 0 {
+		result = Condition{Column: column, Predicate: " NOT IN (" + strings.Repeat(",?", len(args))[1:] + ")", Args: args}
+	}
+
+	if hasNull {
+		result = And(result, NotNull(column))
+	}
+
+	return result
+}
+
+// NotInSlice returns a 'NOT IN' condition on a column.
+//   - If arg is nil, this becomes a no-op.
+//   - arg is reflectively expanded as an array or slice to use all the contained values.
+//   - If any value is nil, an 'IS NOT NULL' expression is AND-ed with the 'NOT IN' expression.
+func NotInSlice(column string, arg any) Expression {
+	if arg == nil {
+		return NoOp()
+	}
+
+	value := reflect.ValueOf(arg)
+
+	switch value.Kind() {
+	case reflect.Array, reflect.Slice:
+		// continue below
+	default:
+		panic("arg must be an array or slice")
+	}
+
+	values := make([]any, value.Len())
+	for j := range values {
+		vj := value.Index(j)
+		switch vj.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if vj.IsNil() {
+				continue // leaves nil
+			}
+		}
+		values[j] = vj.Interface()
+	}
+
+	return NotIn(column, values...)
+}
+
+// InChunked returns an 'IN' condition on a column, as for In, except that long lists of values
+// are split into chunks of at most chunkSize values. The chunks are OR-ed together, e.g.
+// "(id IN (?,?) OR id IN (?))". This avoids database limits on the number of values in a
+// list, or on the number of parameters in each IN list.
+//
+// If chunkSize is zero or negative, the values are not split.
+func InChunked(column string, values []any, chunkSize int) Expression {
+	if chunkSize <= 0 || len(values) <= chunkSize {
+		return In(column, values...)
+	}
+
+	nonNull := make([]any, 0, len(values))
+	hasNull := false
+	for _, v := range values {
+		if v == nil {
+			hasNull = true
+		} else {
+			nonNull = append(nonNull, v)
+		}
+	}
+
+	chunks := make([]Node, 0, len(nonNull)/chunkSize+2)
+	for len(nonNull) > 0 {
+		n := min(chunkSize, len(nonNull))
+		chunks = append(chunks, In(column, nonNull[:n]...))
+		nonNull = nonNull[n:]
+	}
+
+	if hasNull {
+		chunks = append(chunks, Null(column))
+	}
+
+	return Or(chunks...)
+}
+
+// InStringers returns an 'IN' condition on a column, binding the string representation
+// of each value. This suits enumerations that implement fmt.Stringer.
+// Nil values are treated as for In.
+func InStringers(column string, values ...fmt.Stringer) Expression {
+	args := make([]any, len(values))
+	for i, v := range values {
+		if v != nil {
+			args[i] = v.String()
+		}
+	}
+	return In(column, args...)
+}
+
+// InStrings returns an 'IN' condition on a column, binding each value as a string.
+// This suits enumerations whose underlying type is string.
+func InStrings[S ~string](column string, values ...S) Expression {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = string(v)
+	}
+	return In(column, args...)
+}
+
 //-------------------------------------------------------------------------------------------------
 
 const (
@@ -194,7 +462,7 @@ const (
 )
 
 // Not negates an expression.
-func Not(exp Expression) Expression {
+func Not(exp Node) Expression {
 	if exp == nil {
 		return NoOp()
 	}
@@ -202,13 +470,13 @@ func Not(exp Expression) Expression {
 }
 
 // And combines two conditions into a clause that requires they are both true.
-func (exp not) And(other Expression) Expression {
-	return Clause{wheres: []Expression{exp}, conjunction: and}.And(other)
+func (exp not) And(other Node) Expression {
+	return Clause{wheres: []Node{exp}, conjunction: and}.And(other)
 }
 
 // Or combines two conditions into a clause that requires either is true.
-func (exp not) Or(other Expression) Expression {
-	return Clause{wheres: []Expression{exp}, conjunction: or}.Or(other)
+func (exp not) Or(other Node) Expression {
+	return Clause{wheres: []Node{exp}, conjunction: or}.Or(other)
 }
 
 //-------------------------------------------------------------------------------------------------
@@ -223,20 +491,34 @@ func NoOp() Expression {
 //-------------------------------------------------------------------------------------------------
 
 // And combines two conditions into a clause that requires they are both true.
-func (exp Condition) And(other Expression) Expression {
-	return Clause{wheres: []Expression{exp}, conjunction: and}.And(other)
+func (exp wrapper) And(other Node) Expression {
+	return Clause{wheres: []Node{exp.node}, conjunction: and}.And(other)
 }
 
 // Or combines two conditions into a clause that requires either is true.
-func (exp Condition) Or(other Expression) Expression {
-	return Clause{wheres: []Expression{exp}, conjunction: or}.Or(other)
+func (exp wrapper) Or(other Node) Expression {
+	return Clause{wheres: []Node{exp.node}, conjunction: or}.Or(other)
 }
 
 //-------------------------------------------------------------------------------------------------
 
-// And combines two clauses into a clause that requires they are both true.
+// And combines two conditions into a clause that requires they are both true.
+func (exp Condition) And(other Node) Expression {
+	return Clause{wheres: []Node{exp}, conjunction: and}.And(other)
+}
+
+// Or combines two conditions into a clause that requires either is true.
+func (exp Condition) Or(other Node) Expression {
+	return Clause{wheres: []Node{exp}, conjunction: or}.Or(other)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// conjoin combines two clauses using a conjunction. Clauses are immutable: the result never
+// shares spare capacity in the slices of the operands, so expressions built from the same
+// base clause cannot alter each other, and clauses can be shared between goroutines.
 // SQL implementation note: AND has higher precedence than OR.
-func (exp Clause) conjoin(other Expression, conj string) Expression {
+func (exp Clause) conjoin(other Node, conj string) Expression {
 	cl, isClause := other.(Clause)
 	if isClause {
 		if len(exp.wheres) == 0 {
@@ -244,28 +526,28 @@ func (exp Clause) conjoin(other Expression, conj string) Expression {
 		} else if len(cl.wheres) == 0 {
 			return exp
 		} else if exp.conjunction == conj && cl.conjunction == conj {
-			return Clause{append(exp.wheres, cl.wheres...), conj}
+			return Clause{slices.Concat(exp.wheres, cl.wheres), conj}
 		}
 	} else {
 		// blank case comes from NoOp
 		if exp.conjunction == "" || exp.conjunction == conj {
-			return Clause{append(exp.wheres, other), conj}
+			return Clause{slices.Concat(exp.wheres, []Node{other}), conj}
 		}
 	}
-	return Clause{wheres: []Expression{exp, other}, conjunction: conj}
+	return Clause{wheres: []Node{exp, other}, conjunction: conj}
 }
 
 // And combines two clauses into a clause that requires they are both true.
 // Parentheses will be inserted to preserve the calling order.
 // SQL implementation note: AND has higher precedence than OR.
-func (exp Clause) And(other Expression) Expression {
+func (exp Clause) And(other Node) Expression {
 	return exp.conjoin(other, and)
 }
 
 // Or combines two clauses into a clause that requires either is true.
 // Parentheses will be inserted to preserve the calling order.
 // SQL implementation note: AND has higher precedence than OR.
-func (exp Clause) Or(other Expression) Expression {
+func (exp Clause) Or(other Node) Expression {
 	return exp.conjoin(other, or)
 }
 
@@ -273,17 +555,17 @@ func (exp Clause) Or(other Expression) Expression {
 
 // And combines some expressions into a clause that requires they are all true.
 // Any nil items are silently dropped.
-func And(exp ...Expression) Expression {
+func And(exp ...Node) Expression {
 	return newClause(and, exp...)
 }
 
 // Or combines some expressions into a clause that requires that any is true.
 // Any nil items are silently dropped.
-func Or(exp ...Expression) Expression {
+func Or(exp ...Node) Expression {
 	return newClause(or, exp...)
 }
 
-func newClause(conj string, exp ...Expression) Expression {
+func newClause(conj string, exp ...Node) Expression {
 	var clause = Clause{nil, conj}
 	for _, e := range exp {
 		if e != nil {
@@ -294,7 +576,7 @@ func newClause(conj string, exp ...Expression) Expression {
 		}
 	}
 	if len(clause.wheres) == 1 {
-		return clause.wheres[0] // simplify the result
+		return Wrap(clause.wheres[0]) // simplify the result
 	}
 	return clause
 }
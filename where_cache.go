@@ -0,0 +1,255 @@
+package where
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// Cache memoizes formatted clauses, keyed by the shape of the expression and the format
+// settings. The shape is the structure of the expression without its argument values,
+// so services that rebuild structurally identical filters for every request only pay
+// the formatting cost once per shape; thereafter, only the arguments are gathered.
+//
+// Expressions containing custom nodes, and those formatted with dialect.Inline, are
+// formatted as usual but are not cached. Custom quoters are distinguished by their type
+// and value.
+//
+// A Cache is safe for concurrent use. The zero value is not usable; use NewCache.
+type Cache struct {
+	maxEntries int
+	mu         sync.RWMutex
+	entries    map[string]cacheEntry
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+	bypasses   atomic.Uint64
+}
+
+type cacheEntry struct {
+	sql   string
+	names []string // for dialect.Named
+}
+
+// CacheStats reports the effectiveness of a Cache.
+type CacheStats struct {
+	// Hits counts the clauses whose SQL was found in the cache.
+	Hits uint64
+
+	// Misses counts the clauses that were formatted and then added to the cache.
+	Misses uint64
+
+	// Bypasses counts the clauses that could not be cached.
+	Bypasses uint64
+
+	// Entries is the number of shapes currently cached.
+	Entries int
+}
+
+// HitRate gives the proportion of lookups that were hits, from 0 to 1.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses + s.Bypasses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// NewCache returns a cache holding at most maxEntries shapes; when it is full, it is
+// emptied before adding more. If maxEntries is zero or negative, there is no limit.
+func NewCache(maxEntries int) *Cache {
+	return &Cache{maxEntries: maxEntries, entries: make(map[string]cacheEntry)}
+}
+
+// Stats reports the numbers of hits and misses so far, and the number of entries.
+func (cc *Cache) Stats() CacheStats {
+	cc.mu.RLock()
+	n := len(cc.entries)
+	cc.mu.RUnlock()
+	return CacheStats{Hits: cc.hits.Load(), Misses: cc.misses.Load(), Bypasses: cc.bypasses.Load(), Entries: n}
+}
+
+// Where constructs the SQL clause beginning "WHERE ...", as for Where, using the cache.
+func (cc *Cache) Where(wh Node, option ...dialect.FormatOption) (string, []any) {
+	return cc.format(whereConjunction, wh, option)
+}
+
+// Having constructs the SQL clause beginning "HAVING ...", as for Having, using the cache.
+func (cc *Cache) Having(wh Node, option ...dialect.FormatOption) (string, []any) {
+	return cc.format(havingConjunction, wh, option)
+}
+
+func (cc *Cache) format(conjunction string, wh Node, option []dialect.FormatOption) (string, []any) {
+	c := newConfig(dialect.FormatConfig{}, option)
+
+	buf := &strings.Builder{}
+	if wh == nil || c.Placeholder == dialect.Inline || !c.writeShape(buf, wh) {
+		cc.bypasses.Add(1)
+		return format(conjunction, wh, option...)
+	}
+	buf.WriteString(conjunction)
+	c.writeSettings(buf)
+	key := buf.String()
+
+	cc.mu.RLock()
+	entry, found := cc.entries[key]
+	cc.mu.RUnlock()
+
+	if !found {
+		cc.misses.Add(1)
+		query, args := format(conjunction, wh, option...)
+		entry.sql = query
+		if c.Placeholder == dialect.Named {
+			entry.names = make([]string, len(args))
+			for i, a := range args {
+				entry.names[i] = a.(sql.NamedArg).Name
+			}
+		}
+		cc.store(key, entry)
+		return query, args
+	}
+
+	cc.hits.Add(1)
+	if entry.sql == "" {
+		return "", nil
+	}
+
+	args := bindArgs(c.collectArgs(wh, nil), c.Dialect)
+	if entry.names != nil {
+		for i, v := range args {
+			args[i] = sql.Named(entry.names[i], v)
+		}
+	}
+	return entry.sql, args
+}
+
+func (cc *Cache) store(key string, entry cacheEntry) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.maxEntries > 0 && len(cc.entries) >= cc.maxEntries {
+		clear(cc.entries)
+	}
+	cc.entries[key] = entry
+}
+
+// writeShape writes a description of the structure of an expression, i.e. everything
+// that affects the SQL but not the argument values. It returns false if the expression
+// contains nodes whose shape cannot be determined.
+func (c config) writeShape(buf *strings.Builder, node Node) bool {
+	switch n := node.(type) {
+	case wrapper:
+		return c.writeShape(buf, n.node)
+
+	case labelled:
+		return c.writeShape(buf, n.node)
+
+	case Condition:
+		c.writeConditionShape(buf, "", n.Column, n.Predicate, n.Args)
+		return true
+
+	case aggregateCondition:
+		buf.WriteString("F(")
+		if n.aggregate.filter != nil && !c.writeShape(buf, n.aggregate.filter) {
+			return false
+		}
+		buf.WriteByte(')')
+		c.writeConditionShape(buf, n.aggregate.function, n.aggregate.column, n.predicate, n.args)
+		return true
+
+	case not:
+		buf.WriteString("N(")
+		ok := c.writeShape(buf, n.expression)
+		buf.WriteByte(')')
+		return ok
+
+	case Clause:
+		buf.WriteString(n.conjunction)
+		buf.WriteByte('(')
+		for _, w := range n.wheres {
+			if !c.writeShape(buf, w) {
+				return false
+			}
+		}
+		buf.WriteByte(')')
+		return true
+	}
+
+	return false
+}
+
+// writeConditionShape writes the shape of a condition. Where adapt rewrites a condition
+// according to its argument values, the shape must distinguish those values too; only
+// empty strings need this, for dialects that treat them as null. Other rewrites must not
+// depend on the values, or cached SQL would not match its arguments.
+func (c config) writeConditionShape(buf *strings.Builder, function, column, predicate string, args []any) {
+	buf.WriteString("C(")
+	buf.WriteString(function)
+	buf.WriteByte(0)
+	buf.WriteString(column)
+	buf.WriteByte(0)
+	buf.WriteString(predicate)
+	for _, a := range args {
+		buf.WriteByte(0)
+		switch x := a.(type) {
+		case TypedArg:
+			buf.WriteString(x.Type) // affects casts
+		case string:
+			if x == "" && c.Dialect.EmptyStringIsNull() {
+				buf.WriteByte('e') // see adapt
+			}
+		}
+	}
+	buf.WriteByte(')')
+}
+
+// writeSettings writes the format settings that affect the SQL.
+func (c config) writeSettings(buf *strings.Builder) {
+	fmt.Fprintf(buf, "\x00%d\x00%T%v\x00%d\x00%d\x00%s\x00%d\x00%d\x00%d\x00%t\x00%v\x00%s",
+		c.Dialect, c.Quoter, c.Quoter, c.Placeholder, c.PlaceholderOffset, c.TableAlias,
+		c.KeywordCase, c.Spacing, c.InValues, c.InArray, c.Keywords, c.Comment)
+}
+
+// collectArgs gathers the arguments of an expression in the same order as formatting does,
+// i.e. after adapting each condition to the dialect and removing any type hints. It only
+// handles expressions for which writeShape succeeds.
+func (c config) collectArgs(node Node, args []any) []any {
+	switch n := node.(type) {
+	case wrapper:
+		return c.collectArgs(n.node, args)
+
+	case labelled:
+		return c.collectArgs(n.node, args)
+
+	case Condition:
+		return appendValues(args, c.adapt(n).Args)
+
+	case aggregateCondition:
+		if n.aggregate.filter != nil {
+			args = c.collectArgs(n.aggregate.filter, args)
+		}
+		return appendValues(args, n.args)
+
+	case not:
+		return c.collectArgs(n.expression, args)
+
+	case Clause:
+		for _, w := range n.wheres {
+			args = c.collectArgs(w, args)
+		}
+	}
+	return args
+}
+
+// appendValues appends the argument values, without any type hints.
+func appendValues(args, values []any) []any {
+	for _, v := range values {
+		if t, ok := v.(TypedArg); ok {
+			v = t.Value
+		}
+		args = append(args, v)
+	}
+	return args
+}
@@ -0,0 +1,107 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/predicate"
+)
+
+func TestCache(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	filter := func(name string, age int) where.Expression {
+		return where.And(
+			where.Eq("name", name),
+			where.Or(where.Gt("age", age), where.Null("age")),
+			where.Not(where.In("status", "x", "y")),
+			where.Label("ids", where.EqID("id", [16]byte{byte(age)})),
+			where.Eq("ts", where.Typed("2024", "timestamptz")),
+			where.AggregateFilter("COUNT", "*", where.Eq("late", name)).Compare(predicate.GreaterThan, age),
+		)
+	}
+
+	options := [][]dialect.FormatOption{
+		{dialect.Postgres, dialect.Dollar, dialect.ANSIQuotes},
+		{dialect.Mysql, dialect.Backticks},
+		{oracle, dialect.Named},
+		{oracle, dialect.Named, where.WithPlaceholderOffset(3)},
+		{dialect.SqlServer, dialect.AtP, where.WithKeywordCase(dialect.LowerCase)},
+	}
+
+	cache := where.NewCache(0)
+
+	for _, opts := range options {
+		for _, name := range []string{"Fred", "", "John"} {
+			for age := 1; age < 3; age++ {
+				expSQL, expArgs := where.Where(filter(name, age), opts...)
+				s, args := cache.Where(filter(name, age), opts...)
+				g.Expect(s).To(Equal(expSQL))
+				g.Expect(args).To(Equal(expArgs))
+			}
+		}
+	}
+
+	// Oracle has different SQL when the name is empty
+	stats := cache.Stats()
+	g.Expect(stats.Entries).To(Equal(7))
+	g.Expect(stats.Misses).To(Equal(uint64(7)))
+	g.Expect(stats.Hits).To(Equal(uint64(23)))
+	g.Expect(stats.Bypasses).To(BeZero())
+	g.Expect(stats.HitRate()).To(BeNumerically("~", 23.0/30, 0.001))
+}
+
+func TestCache_bypass(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cache := where.NewCache(1)
+
+	s, args := cache.Having(where.And(nameIsFred, tagsContain{"a", "b"}), dialect.Dollar)
+	g.Expect(s).To(Equal(` HAVING name=$1 AND (tags @> ARRAY[$2,$3])`))
+	g.Expect(args).To(Equal([]any{"Fred", "a", "b"}))
+
+	s, args = cache.Where(nameIsFred, dialect.Inline)
+	g.Expect(s).To(Equal(` WHERE name='Fred'`))
+	g.Expect(args).To(BeNil())
+
+	s, args = cache.Where(nil)
+	g.Expect(s).To(BeEmpty())
+	g.Expect(args).To(BeNil())
+
+	s, _ = cache.Where(nameIsFred)
+	g.Expect(s).To(Equal(` WHERE name=?`))
+	s, _ = cache.Where(where.NoOp())
+	g.Expect(s).To(BeEmpty())
+	s, args = cache.Where(where.NoOp())
+	g.Expect(s).To(BeEmpty())
+	g.Expect(args).To(BeNil())
+
+	g.Expect(cache.Stats()).To(Equal(where.CacheStats{Hits: 1, Misses: 2, Bypasses: 3, Entries: 1}))
+}
+
+func TestCache_valuesOfDifferentKinds(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cache := where.NewCache(0)
+
+	values := [][2]any{{2, 1}, {"x", "y"}, {1.5, int64(3)}, {"", nil}}
+
+	for _, d := range []dialect.Dialect{dialect.Postgres, dialect.Mysql, dialect.SqlServer, oracle} {
+		for _, v := range values {
+			wh := where.And(
+				where.BetweenSymmetric("a", v[0], v[1]),
+				where.EqNullSafe("b", v[0]),
+				where.Eq("c", v[1]),
+				where.ILike("d", "x%"),
+				where.In("e", v[0], v[1]),
+			)
+			opts := []dialect.FormatOption{d, where.WithInArray()}
+			expSQL, expArgs := where.Where(wh, opts...)
+			s, args := cache.Where(wh, opts...)
+			g.Expect(s).To(Equal(expSQL), "%s %v", d, v)
+			g.Expect(args).To(Equal(expArgs), "%s %v", d, v)
+		}
+	}
+}
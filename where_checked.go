@@ -0,0 +1,67 @@
+package where
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// ErrTooManyParameters is returned when a formatted expression has more arguments than
+// the dialect allows in a single statement (see dialect.Dialect.MaxBindParams).
+var ErrTooManyParameters = errors.New("where: too many parameters")
+
+// ErrInvalidRowCount is returned when the limit or offset of a query constraint is
+// negative or too large for the dialect (see dialect.Dialect.MaxRowCount).
+var ErrInvalidRowCount = errors.New("where: invalid row count")
+
+// CheckParameterLimit returns an error wrapping ErrTooManyParameters if n exceeds the
+// maximum number of bind parameters for the dialect. WhereE etc make this check, which
+// reports the problem clearly rather than leaving the database driver to fail at execution
+// time. Long IN lists are the usual cause; see InChunked for SQL-Server.
+func CheckParameterLimit(n int, d dialect.Dialect) error {
+	if limit := d.MaxBindParams(); limit > 0 && n > limit {
+		return fmt.Errorf("%w: %d exceeds the %s limit of %d", ErrTooManyParameters, n, d, limit)
+	}
+	return nil
+}
+
+// FormatChecked formats the SQL expressions and arguments, as for FormatArgs, but first
+// checks the limit and offset. Negative values are rejected, as are values that exceed
+// the maximum for the dialect. Without this check, negative values are ignored.
+//
+// The error wraps ErrInvalidRowCount.
+func (qc *Constraint) FormatChecked(d dialect.Dialect, option ...dialect.FormatOption) (string, []any, error) {
+	if qc == nil {
+		return "", nil, nil
+	}
+
+	c := newConfig(dialect.FormatConfig{Dialect: d}, option)
+	if err := qc.checkRowCounts(c.Dialect); err != nil {
+		return "", nil, err
+	}
+	s, args := qc.format(c)
+	return s, args, nil
+}
+
+func (qc *Constraint) checkRowCounts(d dialect.Dialect) error {
+	maximum := d.MaxRowCount()
+
+	for _, n := range []struct {
+		name  string
+		value int64
+	}{{"limit", qc.limit}, {"offset", qc.offset}} {
+		if n.value < 0 {
+			return fmt.Errorf("%w: %s %d is negative", ErrInvalidRowCount, n.name, n.value)
+		}
+		if n.value > maximum {
+			return fmt.Errorf("%w: %s %d exceeds the %s maximum", ErrInvalidRowCount, n.name, n.value, d)
+		}
+	}
+
+	// ROWS m TO n uses the sum of the offset and the limit
+	if d.LimitStyle() == dialect.Rows && qc.limit > 0 && qc.offset > maximum-qc.limit {
+		return fmt.Errorf("%w: offset %d plus limit %d exceeds the %s maximum", ErrInvalidRowCount, qc.offset, qc.limit, d)
+	}
+	return nil
+}
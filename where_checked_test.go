@@ -0,0 +1,63 @@
+package where_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestWhereE_parameterLimit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	values := make([]any, 2101)
+	for i := range values {
+		values[i] = i
+	}
+
+	sql, args, err := where.WhereE(where.In("id", values...), dialect.Postgres, dialect.Dollar)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sql).To(HavePrefix(` WHERE id IN ($1,$2,`))
+	g.Expect(args).To(HaveLen(2101))
+
+	_, _, err = where.HavingE(where.In("id", values...), dialect.SqlServer, dialect.AtP)
+	g.Expect(err).To(MatchError(where.ErrTooManyParameters))
+	g.Expect(err).To(MatchError(`where: too many parameters: 2101 exceeds the SqlServer limit of 2100`))
+
+	// inlined values are not parameters
+	_, args, err = where.WhereE(where.In("id", values...), dialect.SqlServer, dialect.Inline)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(BeEmpty())
+
+	g.Expect(where.CheckParameterLimit(100000, 0)).To(Succeed())
+}
+
+func TestConstraint_FormatChecked(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	s, _, err := where.Limit64(1 << 40).Offset64(1<<40 + 1).FormatChecked(dialect.Postgres)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(Equal(` LIMIT 1099511627776 OFFSET 1099511627777`))
+
+	_, _, err = where.Limit(-1).FormatChecked(dialect.Sqlite)
+	g.Expect(err).To(MatchError(where.ErrInvalidRowCount))
+	g.Expect(err).To(MatchError(`where: invalid row count: limit -1 is negative`))
+
+	_, _, err = where.Offset64(1 << 40).FormatChecked(dialect.Informix)
+	g.Expect(err).To(MatchError(`where: invalid row count: offset 1099511627776 exceeds the Informix maximum`))
+
+	_, _, err = where.Limit(10).Offset64(math.MaxInt64 - 5).FormatChecked(dialect.Firebird)
+	g.Expect(err).To(MatchError(where.ErrInvalidRowCount))
+	g.Expect(err).To(MatchError(`where: invalid row count: offset 9223372036854775802 plus limit 10 exceeds the Firebird maximum`))
+
+	// the limit alone may be more than half the maximum
+	s, _, err = where.Limit64(math.MaxInt64 - 5).FormatChecked(dialect.Firebird)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(Equal(` ROWS 1 TO 9223372036854775802`))
+
+	s, _, err = (*where.Constraint)(nil).FormatChecked(dialect.Sqlite)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(BeEmpty())
+}
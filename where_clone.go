@@ -0,0 +1,161 @@
+package where
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// ErrArgCount is returned when the number of arguments does not match an expression.
+var ErrArgCount = errors.New("where: wrong number of arguments")
+
+// Clone returns a deep copy of an expression tree. The copy shares no clauses or argument
+// slices with the original, so either can be altered without affecting the other. The
+// argument values themselves are not copied. Custom nodes are copied via WithChildren if
+// they implement Composite; otherwise they are shared.
+func Clone(wh Node) Expression {
+	if wh == nil {
+		return nil
+	}
+	cloned, _ := rebind(wh, func(args []any) ([]any, error) {
+		return append([]any(nil), args...), nil
+	})
+	return Wrap(cloned)
+}
+
+// WithArgs returns a copy of an expression tree with its argument values replaced, keeping
+// its structure. This allows a filter to be used as a template, e.g.
+//
+//	template := where.Eq("name", "").And(where.Gt("age", 0))
+//	wh, err := where.WithArgs(template, []any{"Fred", 18})
+//
+// The values are taken in order; they replace the arguments of the conditions as they were
+// constructed, which is also the order given by Args (except for conditions that are
+// rendered with repeated values for some dialects). Type hints (see Typed) are kept.
+//
+// The error wraps ErrArgCount if the number of values differs from the number of
+// arguments. An error is also returned if a custom node has arguments, unless it
+// implements Composite, because these cannot be replaced.
+func WithArgs(wh Node, values []any) (Expression, error) {
+	if wh == nil {
+		if len(values) > 0 {
+			return nil, fmt.Errorf("%w: expected 0 but got %d", ErrArgCount, len(values))
+		}
+		return nil, nil
+	}
+
+	if err := checkRebindable(wh); err != nil {
+		return nil, err
+	}
+
+	used := 0
+	rebound, err := rebind(wh, func(args []any) ([]any, error) {
+		if used+len(args) > len(values) {
+			return nil, fmt.Errorf("%w: got only %d", ErrArgCount, len(values))
+		}
+		replaced := make([]any, len(args))
+		for i, old := range args {
+			replaced[i] = values[used+i]
+			if t, isTyped := old.(TypedArg); isTyped {
+				if _, alreadyTyped := replaced[i].(TypedArg); !alreadyTyped {
+					replaced[i] = TypedArg{Value: replaced[i], Type: t.Type}
+				}
+			}
+		}
+		used += len(args)
+		return replaced, nil
+	})
+
+	switch {
+	case err != nil:
+		return nil, err
+	case used != len(values):
+		return nil, fmt.Errorf("%w: expected %d but got %d", ErrArgCount, used, len(values))
+	}
+	return Wrap(rebound), nil
+}
+
+// rebind rebuilds an expression tree, using fn to replace the arguments of each node in
+// the order they are formatted.
+func rebind(node Node, fn func([]any) ([]any, error)) (Node, error) {
+	switch n := node.(type) {
+	case wrapper:
+		return rebind(n.node, fn)
+
+	case Condition:
+		if n.Args != nil {
+			args, err := fn(n.Args)
+			if err != nil {
+				return nil, err
+			}
+			n.Args = args
+		}
+		return n, nil
+
+	case aggregateCondition:
+		if n.aggregate.filter != nil {
+			filter, err := rebind(n.aggregate.filter, fn)
+			if err != nil {
+				return nil, err
+			}
+			n.aggregate.filter = filter
+		}
+		if n.args != nil {
+			args, err := fn(n.args)
+			if err != nil {
+				return nil, err
+			}
+			n.args = args
+		}
+		return n, nil
+
+	case Clause:
+		if n.wheres == nil {
+			return n, nil
+		}
+		wheres := make([]Node, len(n.wheres))
+		for i, w := range n.wheres {
+			var err error
+			if wheres[i], err = rebind(w, fn); err != nil {
+				return nil, err
+			}
+		}
+		return Clause{wheres: wheres, conjunction: n.conjunction}, nil
+
+	case not:
+		inner, err := rebind(n.expression, fn)
+		if err != nil {
+			return nil, err
+		}
+		return not{expression: inner}, nil
+
+	case Composite:
+		kids := n.Children()
+		replaced := make([]Node, len(kids))
+		for i, k := range kids {
+			var err error
+			if replaced[i], err = rebind(k, fn); err != nil {
+				return nil, err
+			}
+		}
+		return n.WithChildren(replaced), nil
+	}
+
+	return node, nil
+}
+
+// checkRebindable finds any custom node whose arguments cannot be replaced.
+func checkRebindable(wh Node) (err error) {
+	Walk(wh, func(n Node) bool {
+		switch n.(type) {
+		case Condition, aggregateCondition, Clause, not, Composite:
+			return true
+		}
+		if _, args := n.Format(dialect.Query); len(args) > 0 && err == nil {
+			err = fmt.Errorf("where: cannot replace the arguments of %T", n)
+		}
+		return false
+	})
+	return err
+}
@@ -0,0 +1,59 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestClone(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := where.Condition{Column: "age", Predicate: " IN (?,?)", Args: []any{1, 2}}
+	wh := where.And(nameIsFred, where.Not(c), where.Wrap(pair{a: ageGt5Int, b: tagsContain{1, 2}}))
+
+	clone := where.Clone(wh)
+	g.Expect(clone.String()).To(Equal(wh.String()))
+
+	c.Args[0] = 9 // alters the original, but not the clone
+	g.Expect(wh.String()).To(ContainSubstring(`age IN (9,2)`))
+	g.Expect(clone.String()).To(ContainSubstring(`age IN (1,2)`))
+
+	g.Expect(where.Clone(nil)).To(BeNil())
+}
+
+func TestWithArgs(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	template := where.And(where.Eq("name", ""), where.Or(where.Gt("age", 0), where.Eq("tags", where.Typed(nil, "text[]"))),
+		where.Label("l", where.Between("score", 0, 0)))
+
+	wh, err := where.WithArgs(template, []any{"Fred", 18, []string{"a"}, 1, 10})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	sql, args := wh.Format(dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(`name=$1 AND (age>$2 OR tags=$3::text[]) AND score BETWEEN $4 AND $5`))
+	g.Expect(args).To(Equal([]any{"Fred", 18, []string{"a"}, 1, 10}))
+
+	// the template is unchanged
+	g.Expect(where.Args(template)).To(Equal([]any{"", 0, nil, 0, 0}))
+
+	_, err = where.WithArgs(template, []any{"Fred", 18})
+	g.Expect(err).To(MatchError(where.ErrArgCount))
+
+	_, err = where.WithArgs(template, []any{"Fred", 18, nil, 1, 10, 11})
+	g.Expect(err).To(MatchError("where: wrong number of arguments: expected 5 but got 6"))
+
+	_, err = where.WithArgs(where.Wrap(tagsContain{1, 2}), []any{3, 4})
+	g.Expect(err).To(MatchError("where: cannot replace the arguments of where_test.tagsContain"))
+
+	wh, err = where.WithArgs(where.Wrap(pair{a: ageGt5Int, b: where.Null("x")}), []any{7})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(wh.String()).To(Equal(`age>7 OR x IS NULL`))
+
+	wh, err = where.WithArgs(nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(wh).To(BeNil())
+}
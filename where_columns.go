@@ -0,0 +1,49 @@
+package where
+
+import (
+	"strings"
+
+	"github.com/rickb777/where/v2/quote"
+)
+
+// Columns returns the distinct column names used by the conditions in an expression tree,
+// in the order they first appear. Names are given as written, without quotes or table
+// aliases added by formatting. For expressions (see Expr), each column name within the
+// expression is included. The columns of aggregates, and of their filters, are included too.
+//
+// Conditions without a column, such as those from Predicate and Exists, contribute
+// nothing; neither do custom nodes, except via their children if they implement Composite.
+func Columns(wh Node) []string {
+	var columns []string
+	seen := make(map[string]bool)
+
+	add := func(column string) string {
+		if column != "" && column != "*" && !seen[column] {
+			seen[column] = true
+			columns = append(columns, column)
+		}
+		return column
+	}
+
+	Walk(wh, func(n Node) bool {
+		switch c := n.(type) {
+		case Condition:
+			if strings.HasPrefix(c.Column, exprPrefix) {
+				quote.MapIdentifiers(c.Column[len(exprPrefix):], add)
+			} else {
+				add(plainColumn(c.Column))
+			}
+
+		case aggregateCondition:
+			add(plainColumn(c.aggregate.column))
+			if c.aggregate.filter != nil {
+				for _, column := range Columns(c.aggregate.filter) {
+					add(column)
+				}
+			}
+		}
+		return true
+	})
+
+	return columns
+}
@@ -0,0 +1,34 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+)
+
+func TestColumns(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(
+		nameIsFred,
+		where.Or(ageGt5Int, where.Not(where.Null("p.nickname"))),
+		where.Expr("COALESCE(p.nickname, name)", "=?", "Fred"),
+		where.Eq(where.Raw("created::date"), "2024-01-02"),
+		where.Exists("SELECT 1 FROM offers"),
+		where.Wrap(pair{a: where.Eq("city", "Leeds"), b: tagsContain{1, 2}}),
+		where.Not(ageGt5Int),
+	)
+
+	g.Expect(where.Columns(wh)).To(Equal([]string{"name", "age", "p.nickname", "created::date", "city"}))
+	g.Expect(where.Columns(where.NoOp())).To(BeEmpty())
+	g.Expect(where.Columns(nil)).To(BeEmpty())
+}
+
+func TestColumns_aggregates(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.CountGt("*", 1).And(where.SumGt("amount", 10)).
+		And(where.AggregateFilter("AVG", "price", where.Eq("status", "late")).Compare(">?", 2))
+	g.Expect(where.Columns(wh)).To(Equal([]string{"amount", "price", "status"}))
+}
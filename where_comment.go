@@ -0,0 +1,102 @@
+package where
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+type tagsKey struct{}
+
+// ContextWithTags returns a copy of the parent context carrying tags for SQL comments,
+// in the style of sqlcommenter. The tags are given as key/value pairs, e.g.
+//
+//	ctx = where.ContextWithTags(ctx, "route", "/api/users", "traceparent", tp)
+//
+// These are merged with any tags already carried by the parent context. A trailing
+// key without a value is ignored.
+func ContextWithTags(parent context.Context, keyValues ...string) context.Context {
+	existing := TagsFromContext(parent)
+	tags := make(map[string]string, len(existing)+len(keyValues)/2)
+	for k, v := range existing {
+		tags[k] = v
+	}
+	for i := 1; i < len(keyValues); i += 2 {
+		tags[keyValues[i-1]] = keyValues[i]
+	}
+	return context.WithValue(parent, tagsKey{}, tags)
+}
+
+// TagsFromContext gets the SQL comment tags carried by a context, if any.
+// The returned map must not be modified.
+func TagsFromContext(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(tagsKey{}).(map[string]string)
+	return tags
+}
+
+// Comment formats the tags carried by a context as a SQL comment, in the style of
+// sqlcommenter, e.g.
+//
+//	/*route='%2Fapi%2Fusers',traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'*/
+//
+// The tags are sorted by key and both keys and values are URL-encoded, so they
+// cannot terminate the comment. The result is blank if there are no tags.
+func Comment(ctx context.Context) string {
+	tags := TagsFromContext(ctx)
+	if len(tags) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf := &strings.Builder{}
+	buf.WriteString("/*")
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(commentEscape(k))
+		buf.WriteString("='")
+		buf.WriteString(commentEscape(tags[k]))
+		buf.WriteByte('\'')
+	}
+	buf.WriteString("*/")
+	return buf.String()
+}
+
+func commentEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// AppendComment appends the SQL comment for the tags carried by a context to a statement
+// (see Comment). The statement is returned unchanged if there are no tags.
+func AppendComment(ctx context.Context, statement string) string {
+	if comment := Comment(ctx); comment != "" {
+		return statement + " " + comment
+	}
+	return statement
+}
+
+// WithComment returns a format option that appends the SQL comment for the tags carried
+// by a context to formatted clauses, i.e. by Where, Having and QueryConstraint.Format.
+func WithComment(ctx context.Context) dialect.FormatOption {
+	comment := Comment(ctx)
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.Comment = comment
+	})
+}
+
+// comment gives the comment to be appended, if any.
+func (c config) comment() string {
+	if c.Comment == "" {
+		return ""
+	}
+	return " " + c.Comment
+}
@@ -0,0 +1,42 @@
+package where_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestComment(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(where.Comment(context.Background())).To(BeEmpty())
+
+	ctx := where.ContextWithTags(context.Background(), "route", "/api/users*/", "action")
+	ctx = where.ContextWithTags(ctx, "traceparent", "00-0af7-01", "db driver", "pgx")
+
+	g.Expect(where.Comment(ctx)).To(Equal(`/*db%20driver='pgx',route='%2Fapi%2Fusers%2A%2F',traceparent='00-0af7-01'*/`))
+	g.Expect(where.AppendComment(ctx, "SELECT 1")).To(Equal(`SELECT 1 /*db%20driver='pgx',route='%2Fapi%2Fusers%2A%2F',traceparent='00-0af7-01'*/`))
+	g.Expect(where.AppendComment(context.Background(), "SELECT 1")).To(Equal(`SELECT 1`))
+}
+
+func TestWithComment(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := where.ContextWithTags(context.Background(), "route", "home")
+
+	sql, args := where.Where(nameIsFred, where.WithComment(ctx), dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE name=$1 /*route='home'*/`))
+	g.Expect(args).To(Equal([]any{"Fred"}))
+
+	sql, _ = where.Where(where.And(tagsContain{1, 2}, nameIsFred), where.WithComment(ctx))
+	g.Expect(sql).To(Equal(` WHERE (tags @> ARRAY[?,?]) AND name=? /*route='home'*/`))
+
+	sql, _ = where.Where(where.NoOp(), where.WithComment(ctx))
+	g.Expect(sql).To(BeEmpty())
+
+	g.Expect(where.Limit(5).Format(dialect.Sqlite, where.WithComment(ctx))).To(Equal(` LIMIT 5 /*route='home'*/`))
+	g.Expect(where.Limit(0).Format(dialect.Sqlite, where.WithComment(ctx))).To(BeEmpty())
+}
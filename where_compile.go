@@ -0,0 +1,260 @@
+package where
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// ErrValuesChangeSQL is returned by Prepared.Bind when the new values would give different
+// SQL from the values that were compiled, e.g. an empty string for a dialect that treats
+// it as null (see dialect.Dialect.EmptyStringIsNull).
+var ErrValuesChangeSQL = errors.New("where: the values change the SQL")
+
+// Prepared is an expression that has been formatted in advance (see Compile). It holds
+// the final SQL and the positions of the values within its arguments, so that the same
+// expression can be executed repeatedly with different values without being formatted again.
+//
+// A Prepared is immutable and is safe for concurrent use.
+type Prepared struct {
+	sql     string
+	where   string
+	args    []any
+	numArgs int      // the number of values taken by Bind
+	shapes  []string // the shape of each value; see valueShape
+	slots   []slot   // how Bind makes each argument; nil if it must format again
+	err     error    // why the values cannot be replaced, if so
+	node    Node     // the compiled expression, for formatting again
+	option  []dialect.FormatOption
+	c       config
+}
+
+// argSlot marks the position of a value whilst compiling, so that the arguments can be
+// traced back to the values from which they are made.
+type argSlot int
+
+// slot describes how Bind makes one argument from the values.
+type slot struct {
+	index int    // the value used, or -1 for an array or a constant
+	array []int  // the values gathered into an array (see WithInArray)
+	value any    // a constant that does not depend on the values
+	name  string // for dialect.Named
+}
+
+// Compile formats an expression once, for repeated use on hot paths. The options are as
+// for Format, except that dialect.Inline is treated as dialect.Query because the values
+// are bound separately (see Prepared.Bind).
+//
+// As well as the SQL, Compile works out how each argument is made from the values, allowing
+// for the conditions that are adapted to the dialect. This is done by formatting the
+// expression a second time with markers in place of the values.
+func Compile(wh Node, option ...dialect.FormatOption) Prepared {
+	c := newConfig(dialect.FormatConfig{}, option)
+	if c.Placeholder == dialect.Inline {
+		option = append(option[:len(option):len(option)], WithPlaceholder(dialect.Query))
+		c = newConfig(dialect.FormatConfig{}, option)
+	}
+
+	p := Prepared{node: wh, option: option, c: c}
+	if wh != nil {
+		p.sql, p.args = formatNodeWith(wh, dialect.FormatConfig{}, option)
+		p.compileSlots(wh)
+	}
+	if p.sql != "" {
+		p.where = c.keyword(whereConjunction) + p.sql + c.comment()
+	}
+	return p
+}
+
+// compileSlots formats the expression with a marker in place of each value, then finds
+// where each marker ends up. Values that alter the SQL, i.e. empty strings for dialects
+// that treat them as null, are kept instead. If the markers alter the SQL, as can happen
+// with custom nodes, the slots are left nil and Bind formats the expression again.
+func (p *Prepared) compileSlots(wh Node) {
+	var values []any
+	_, _ = rebind(wh, func(args []any) ([]any, error) {
+		values = append(values, args...)
+		return args, nil
+	})
+
+	p.numArgs = len(values)
+	p.shapes = make([]string, len(values))
+	markers := make([]any, len(values))
+	for i, v := range values {
+		p.shapes[i] = p.c.valueShape(v)
+		if p.shapes[i] == emptyStringShape {
+			markers[i] = v
+		} else {
+			markers[i] = argSlot(i)
+		}
+	}
+
+	marked, err := WithArgs(wh, markers)
+	if err != nil {
+		p.err = err
+		return
+	}
+
+	sql, args := formatNodeWith(marked, dialect.FormatConfig{}, p.option)
+	if sql != p.sql {
+		return
+	}
+
+	p.slots = make([]slot, len(args))
+	for i, a := range args {
+		p.slots[i] = newSlot(a)
+	}
+}
+
+func newSlot(arg any) slot {
+	s := slot{index: -1}
+	if named, isNamed := arg.(sql.NamedArg); isNamed {
+		s.name = named.Name
+		arg = named.Value
+	}
+	switch x := arg.(type) {
+	case argSlot:
+		s.index = int(x)
+	case []argSlot:
+		s.array = make([]int, len(x))
+		for i, index := range x {
+			s.array[i] = int(index)
+		}
+	default:
+		s.value = arg
+	}
+	return s
+}
+
+// emptyStringShape is the shape of an empty string for dialects that treat it as null.
+const emptyStringShape = "e"
+
+// valueShape gives the properties of a value that affect the SQL, in the same way as
+// writeConditionShape: the type of a type hint and, for dialects that treat empty
+// strings as null, whether it is an empty string.
+func (c config) valueShape(v any) string {
+	switch x := v.(type) {
+	case TypedArg:
+		return "t" + x.Type
+	case string:
+		if x == "" && c.Dialect.EmptyStringIsNull() {
+			return emptyStringShape
+		}
+	}
+	return ""
+}
+
+// SQL gets the formatted expression. It is blank if the expression was empty or nil.
+func (p Prepared) SQL() string {
+	return p.sql
+}
+
+// Where gets the SQL clause beginning "WHERE ...", or a blank string if the expression
+// was empty or nil.
+func (p Prepared) Where() string {
+	return p.where
+}
+
+// NumArgs gets the number of values required by Bind. This can differ from the number
+// of arguments given by Args, which is the number of placeholders.
+func (p Prepared) NumArgs() int {
+	return p.numArgs
+}
+
+// Args gets the arguments of the expression that was compiled, ready for execution.
+func (p Prepared) Args() []any {
+	return append([]any(nil), p.args...)
+}
+
+// Bind gives the arguments for executing the prepared expression with new values. The
+// values replace those of the expression that was compiled, in the order in which it was
+// constructed (see WithArgs). The arguments are in the order of the placeholders, which
+// can differ because some conditions are adapted to the dialect. For example, EqNullSafe
+// repeats its value for some dialects and WithInArray binds an In list as a single array.
+// For dialect.Named, the arguments are given their names.
+//
+// Bind does not format the expression again, except for custom nodes whose SQL depends
+// on their values.
+//
+// The error wraps ErrArgCount if the number of values is not NumArgs, and is
+// ErrValuesChangeSQL if the values would give different SQL. An error is also returned if
+// a custom node has arguments, unless it implements Composite, because these cannot be
+// replaced.
+func (p Prepared) Bind(values ...any) ([]any, error) {
+	if len(values) != p.numArgs {
+		return nil, fmt.Errorf("%w: expected %d but got %d", ErrArgCount, p.numArgs, len(values))
+	}
+	if len(values) == 0 {
+		return p.Args(), nil
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	for i, v := range values {
+		if _, isTyped := v.(TypedArg); !isTyped && strings.HasPrefix(p.shapes[i], "t") {
+			continue // WithArgs keeps the type hint
+		}
+		if p.c.valueShape(v) != p.shapes[i] {
+			return nil, ErrValuesChangeSQL
+		}
+	}
+
+	if p.slots == nil {
+		return p.formatAgain(values)
+	}
+
+	args := make([]any, len(p.slots))
+	for i, s := range p.slots {
+		switch {
+		case s.index >= 0:
+			args[i] = bindValue(values[s.index], p.c.Dialect)
+		case s.array != nil:
+			elements := make([]any, len(s.array))
+			for j, index := range s.array {
+				elements[j] = unwrapTyped(values[index])
+			}
+			args[i] = arrayArg(elements, p.c.Dialect)
+		default:
+			args[i] = s.value
+		}
+		if s.name != "" {
+			args[i] = sql.Named(s.name, args[i])
+		}
+	}
+	return args, nil
+}
+
+// bindValue gives the argument for a value, without any type hint and with identifiers
+// bound as required by the dialect.
+func bindValue(v any, d dialect.Dialect) any {
+	v = unwrapTyped(v)
+	if id, isID := v.(idArg); isID {
+		return id.bind(d.IDBinding())
+	}
+	return v
+}
+
+func unwrapTyped(v any) any {
+	if t, isTyped := v.(TypedArg); isTyped {
+		return t.Value
+	}
+	return v
+}
+
+// formatAgain replaces the values and formats the expression, for when the slots are not known.
+func (p Prepared) formatAgain(values []any) ([]any, error) {
+	wh, err := WithArgs(p.node, values)
+	if err != nil {
+		return nil, err
+	}
+
+	s, args := formatNodeWith(wh, dialect.FormatConfig{}, p.option)
+	if s != p.sql {
+		return nil, ErrValuesChangeSQL
+	}
+	return args, nil
+}
@@ -0,0 +1,111 @@
+package where_test
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestCompile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Eq("name", "Fred").And(where.Between("age", 12, 18))
+
+	p := where.Compile(wh, dialect.Dollar)
+	g.Expect(p.SQL()).To(Equal(`name=$1 AND age BETWEEN $2 AND $3`))
+	g.Expect(p.Where()).To(Equal(` WHERE name=$1 AND age BETWEEN $2 AND $3`))
+	g.Expect(p.NumArgs()).To(Equal(3))
+	g.Expect(p.Args()).To(Equal([]any{"Fred", 12, 18}))
+
+	args, err := p.Bind("John", 20, 30)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]any{"John", 20, 30}))
+
+	_, err = p.Bind("John")
+	g.Expect(err).To(MatchError(where.ErrArgCount))
+
+	p = where.Compile(wh, dialect.Named)
+	g.Expect(p.SQL()).To(Equal(`name=:name_1 AND age BETWEEN :age_1 AND :age_2`))
+	args, err = p.Bind("John", 20, 30)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]any{sql.Named("name_1", "John"), sql.Named("age_1", 20), sql.Named("age_2", 30)}))
+
+	p = where.Compile(wh, dialect.Inline)
+	g.Expect(p.SQL()).To(Equal(`name=? AND age BETWEEN ? AND ?`))
+
+	p = where.Compile(where.NoOp())
+	g.Expect(p.Where()).To(BeEmpty())
+	args, err = p.Bind()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(BeNil())
+}
+
+func TestCompile_adaptedConditions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p := where.Compile(where.EqNullSafe("a", 1).And(where.Eq("b", 2)), dialect.SqlServer)
+	g.Expect(p.NumArgs()).To(Equal(2))
+	g.Expect(p.Args()).To(HaveLen(3))
+	args, err := p.Bind(5, 6)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]any{5, 5, 6}))
+
+	p = where.Compile(where.BetweenSymmetric("a", 1, 2), dialect.Mysql)
+	args, err = p.Bind(9, 3)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]any{9, 3, 3, 9}))
+
+	p = where.Compile(where.In("id", 1, 2, 3), dialect.Postgres, dialect.Dollar, where.WithInArray())
+	g.Expect(p.SQL()).To(Equal(`id=ANY($1)`))
+	g.Expect(p.NumArgs()).To(Equal(3))
+	args, err = p.Bind(4, 5, 6)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]any{[]int{4, 5, 6}}))
+
+	p = where.Compile(where.Eq("a", "x"), oracle)
+	args, err = p.Bind("y")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]any{"y"}))
+	_, err = p.Bind("")
+	g.Expect(err).To(MatchError(where.ErrValuesChangeSQL))
+
+	p = where.Compile(where.Eq("a", "").And(where.Eq("b", 1)), oracle)
+	g.Expect(p.NumArgs()).To(Equal(2))
+	g.Expect(p.Args()).To(Equal([]any{1}))
+	_, err = p.Bind("y", 2)
+	g.Expect(err).To(MatchError(where.ErrValuesChangeSQL))
+
+	p = where.Compile(where.Wrap(tagsContain{"a", "b"}), dialect.Postgres)
+	g.Expect(p.NumArgs()).To(Equal(0))
+	args, err = p.Bind()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal(p.Args()))
+}
+
+func TestCompile_slots(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(
+		where.Eq("ts", where.Typed("2024-01-01", "timestamptz")),
+		where.In("id", 1, 2),
+		where.EqNullSafe("a", 3),
+	)
+
+	p := where.Compile(wh, dialect.Postgres, dialect.Named, where.WithInArray())
+	g.Expect(p.SQL()).To(Equal(`ts=:ts_1::timestamptz AND id=ANY(:id_1) AND a IS NOT DISTINCT FROM :a_1`))
+	args, err := p.Bind("2025-01-01", 7, 8, 9)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]any{sql.Named("ts_1", "2025-01-01"), sql.Named("id_1", []int{7, 8}), sql.Named("a_1", 9)}))
+
+	// a different type hint would change the SQL
+	_, err = p.Bind(where.Typed("x", "date"), 7, 8, 9)
+	g.Expect(err).To(MatchError(where.ErrValuesChangeSQL))
+
+	p = where.Compile(where.EqID("id", "a"), dialect.Postgres)
+	args, err = p.Bind("b")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]any{"b"}))
+}
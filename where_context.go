@@ -0,0 +1,63 @@
+package where
+
+import (
+	"context"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// WhereContext constructs the SQL clause beginning "WHERE ...", as for Where. Any format
+// configuration carried by the context (see dialect.NewContext) provides the settings
+// not specified by the options.
+func WhereContext(ctx context.Context, wh Node, option ...dialect.FormatOption) (string, []any) {
+	return formatWith(whereConjunction, wh, configFromContext(ctx), option)
+}
+
+// HavingContext constructs the SQL clause beginning "HAVING ...", as for Having. Any format
+// configuration carried by the context (see dialect.NewContext) provides the settings
+// not specified by the options.
+func HavingContext(ctx context.Context, wh Node, option ...dialect.FormatOption) (string, []any) {
+	return formatWith(havingConjunction, wh, configFromContext(ctx), option)
+}
+
+// FormatContext formats an expression, as for its Format method. Any format configuration
+// carried by the context (see dialect.NewContext) provides the settings not specified
+// by the options.
+func FormatContext(ctx context.Context, wh Node, option ...dialect.FormatOption) (string, []any) {
+	if wh == nil {
+		return "", nil
+	}
+	return formatNodeWith(wh, configFromContext(ctx), option)
+}
+
+// FormatContext formats the SQL expressions, as for Format. The dialect and quoter are
+// taken from the format configuration carried by the context (see dialect.NewContext),
+// if any, except where the options specify otherwise. Like Format, it inlines the
+// search text of any OrderByRank; use FormatArgsContext to have it passed as an argument.
+func (qc *Constraint) FormatContext(ctx context.Context, option ...dialect.FormatOption) string {
+	s, _ := qc.FormatArgsContext(ctx, inlined(option)...)
+	return s
+}
+
+// FormatArgsContext formats the SQL expressions, as for FormatContext, also returning the
+// arguments needed by OrderByRank, if any (see FormatArgs).
+func (qc *Constraint) FormatArgsContext(ctx context.Context, option ...dialect.FormatOption) (string, []any) {
+	if qc == nil {
+		return "", nil
+	}
+
+	return qc.format(newConfig(configFromContext(ctx), option))
+}
+
+func configFromContext(ctx context.Context) dialect.FormatConfig {
+	config, _ := dialect.FromContext(ctx)
+	return config
+}
+
+// formatNodeWith formats any node, using a base configuration.
+func formatNodeWith(wh Node, base dialect.FormatConfig, option []dialect.FormatOption) (string, []any) {
+	if f, ok := wh.(formatter); ok {
+		return formatTop(f, base, option)
+	}
+	return wh.Format(newConfig(base, option).FormatConfig)
+}
@@ -0,0 +1,55 @@
+package where_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestWhereContext(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := dialect.NewContext(context.Background(), dialect.ConfigFor(dialect.Postgres))
+	wh := where.And(nameIsFred, where.EqID("id", uuidBytes))
+
+	sql, args := where.WhereContext(ctx, wh)
+	g.Expect(sql).To(Equal(` WHERE "name"=$1 AND "id"=$2`))
+	g.Expect(args).To(Equal([]any{"Fred", uuidString}))
+
+	// explicit options take precedence
+	sql, args = where.HavingContext(ctx, wh, dialect.Query, dialect.NoQuotes, dialect.Mysql.Option())
+	g.Expect(sql).To(Equal(` HAVING name=? AND id=?`))
+	g.Expect(args).To(Equal([]any{"Fred", uuidBytes[:]}))
+
+	sql, args = where.FormatContext(ctx, tagsContain{"a", "b"})
+	g.Expect(sql).To(Equal(`"tags" @> ARRAY[$1,$2]`))
+	g.Expect(args).To(Equal([]any{"a", "b"}))
+
+	// no configuration in the context
+	sql, args = where.WhereContext(context.Background(), wh)
+	g.Expect(sql).To(Equal(` WHERE name=? AND id=?`))
+	g.Expect(args).To(Equal([]any{"Fred", uuidString}))
+}
+
+func TestQueryConstraint_FormatContext(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := dialect.NewContext(context.Background(), dialect.ConfigFor(dialect.SqlServer))
+	qc := where.OrderBy("foo").Limit(10).Offset(20)
+
+	g.Expect(qc.FormatContext(ctx)).To(Equal(` ORDER BY [foo] OFFSET 20`))
+	g.Expect(qc.FormatContext(ctx, dialect.NoQuotes)).To(Equal(` ORDER BY foo OFFSET 20`))
+
+	var nilQC *where.Constraint
+	g.Expect(nilQC.FormatContext(ctx)).To(BeEmpty())
+
+	pg := dialect.NewContext(context.Background(), dialect.ConfigFor(dialect.Postgres))
+	rank := where.OrderByRank("doc", "fat cat")
+	s, args := rank.FormatArgsContext(pg)
+	g.Expect(s).To(Equal(` ORDER BY ts_rank("doc", plainto_tsquery($1)) DESC`))
+	g.Expect(args).To(Equal([]any{"fat cat"}))
+	g.Expect(rank.FormatContext(pg)).To(Equal(` ORDER BY ts_rank("doc", plainto_tsquery('fat cat')) DESC`))
+}
@@ -0,0 +1,96 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dedupe removes repeated conditions from an expression tree. This is common when filters
+// from several sources are merged, e.g. when a scope condition is added twice. Removing
+// the repetitions reduces both the size of the SQL and the number of arguments.
+//
+// Within each 'AND' or 'OR' clause, nested clauses with the same conjunction are flattened,
+// then any child that is identical to an earlier one (i.e. with the same column, predicate
+// and arguments) is removed. The logical meaning of the expression is unchanged.
+//
+// Custom nodes are compared using their String method; their children are also deduped
+// if they implement Composite.
+func Dedupe(wh Node) Expression {
+	if wh == nil {
+		return nil
+	}
+	return Wrap(dedupe(wh))
+}
+
+func dedupe(node Node) Node {
+	switch n := node.(type) {
+	case wrapper:
+		return dedupe(n.node)
+
+	case Clause:
+		seen := make(map[string]bool, len(n.wheres))
+		wheres := make([]Node, 0, len(n.wheres))
+		for _, child := range flatten(n.wheres, n.conjunction) {
+			child = dedupe(child)
+			key := dedupeKey(child)
+			if !seen[key] {
+				seen[key] = true
+				wheres = append(wheres, child)
+			}
+		}
+		if len(wheres) == 1 {
+			return wheres[0]
+		}
+		return Clause{wheres: wheres, conjunction: n.conjunction}
+
+	case not:
+		return not{expression: dedupe(n.expression)}
+
+	case Composite:
+		kids := n.Children()
+		replaced := make([]Node, len(kids))
+		for i, k := range kids {
+			replaced[i] = dedupe(k)
+		}
+		return n.WithChildren(replaced)
+	}
+
+	return node
+}
+
+// flatten expands nested clauses that have the same conjunction.
+func flatten(wheres []Node, conjunction string) []Node {
+	var flat []Node
+	for _, w := range wheres {
+		if x, ok := w.(wrapper); ok {
+			w = x.node
+		}
+		if cl, ok := w.(Clause); ok && (cl.conjunction == conjunction || len(cl.wheres) == 0) {
+			flat = append(flat, flatten(cl.wheres, conjunction)...)
+		} else {
+			flat = append(flat, w)
+		}
+	}
+	return flat
+}
+
+// dedupeKey identifies nodes that are identical.
+func dedupeKey(node Node) string {
+	switch n := node.(type) {
+	case Condition:
+		key := n.Column + "\x00" + n.Predicate
+		for _, a := range n.Args {
+			key += fmt.Sprintf("\x00%T:%#v", a, a)
+		}
+		return key
+	case Clause:
+		keys := make([]string, len(n.wheres))
+		for i, w := range n.wheres {
+			keys[i] = dedupeKey(w)
+		}
+		return n.conjunction + "(" + strings.Join(keys, "\x01") + ")"
+	case not:
+		return "NOT(" + dedupeKey(n.expression) + ")"
+	}
+	return fmt.Sprintf("%T\x00%s", node, node.String())
+}
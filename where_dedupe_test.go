@@ -0,0 +1,27 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+)
+
+func TestDedupe(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scope := where.Eq("tenant", 1)
+	wh := where.And(scope, where.And(nameIsFred, scope), where.Or(ageGt5Int, ageGt5Int), where.Not(where.Or(nameIsJohn, nameIsJohn)), nameIsFred)
+
+	sql, args := where.Where(where.Dedupe(wh))
+	g.Expect(sql).To(Equal(` WHERE tenant=? AND name=? AND age>? AND (NOT name=?)`))
+	g.Expect(args).To(Equal([]any{1, "Fred", 5, "John"}))
+
+	// values of different types are not the same
+	wh = where.Or(where.Eq("a", 1), where.Eq("a", "1"), where.Eq("a", int64(1)), where.Wrap(tagsContain{1, 2}), tagsContain{1, 2})
+	g.Expect(where.Dedupe(wh).String()).To(Equal(`a=1 OR a='1' OR a=1 OR (tags @> ARRAY[1,2])`))
+
+	g.Expect(where.Dedupe(nameIsFred)).To(Equal(nameIsFred))
+	g.Expect(where.Dedupe(nil)).To(BeNil())
+	g.Expect(where.Dedupe(where.NoOp()).String()).To(Equal(``))
+}
@@ -0,0 +1,44 @@
+package where
+
+import (
+	"sync/atomic"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+var defaults atomic.Pointer[dialect.FormatConfig]
+
+// SetDefaults sets the package-wide default format configuration. This is safe for
+// concurrent use, unlike the older global variables quote.DefaultQuoter and
+// dialect.DefaultDialect, which it supersedes: these are only consulted for settings
+// that are not specified here.
+//
+// The defaults apply to every formatting function, with lower precedence than any
+// configuration carried by a context and any format options supplied.
+//
+// Only the non-zero fields of a configuration take effect, so a configuration carried
+// by a context cannot revert a default to a zero value, such as dialect.Query
+// placeholders, dialect.UpperCase keywords, dialect.Compact spacing or a zero
+// placeholder offset. Format options such as dialect.Query and
+// WithKeywordCase(dialect.UpperCase) always take effect; alternatively, WithoutDefaults
+// discards the defaults altogether.
+func SetDefaults(config dialect.FormatConfig) {
+	defaults.Store(&config)
+}
+
+// Defaults gets the package-wide default format configuration (see SetDefaults).
+func Defaults() dialect.FormatConfig {
+	if d := defaults.Load(); d != nil {
+		return *d
+	}
+	return dialect.FormatConfig{}
+}
+
+// WithoutDefaults returns a format option that discards the settings made before it,
+// i.e. the defaults (see SetDefaults), any configuration carried by a context and any
+// earlier options. The options that follow it then start from the zero configuration.
+func WithoutDefaults() dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		*config = dialect.FormatConfig{}
+	})
+}
@@ -0,0 +1,47 @@
+package where_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/quote"
+)
+
+func TestSetDefaults(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	where.SetDefaults(dialect.ConfigFor(dialect.Postgres))
+	defer where.SetDefaults(dialect.FormatConfig{})
+
+	g.Expect(where.Defaults().Dialect).To(Equal(dialect.Postgres))
+
+	sql, args := where.Where(nameIsFred.And(ageGt5Int))
+	g.Expect(sql).To(Equal(` WHERE "name"=$1 AND "age">$2`))
+	g.Expect(args).To(Equal([]any{"Fred", 5}))
+
+	// context configuration takes precedence over the defaults
+	ctx := dialect.NewContext(context.Background(), dialect.FormatConfig{Quoter: quote.Backticks})
+	sql, _ = where.WhereContext(ctx, nameIsFred)
+	g.Expect(sql).To(Equal(" WHERE `name`=$1"))
+
+	// options take precedence over the defaults
+	sql, _ = where.Where(nameIsFred, dialect.Query, dialect.NoQuotes)
+	g.Expect(sql).To(Equal(` WHERE name=?`))
+
+	// zero values in a context configuration do not override the defaults
+	ctx = dialect.NewContext(context.Background(), dialect.FormatConfig{Placeholder: dialect.Query})
+	sql, _ = where.WhereContext(ctx, nameIsFred)
+	g.Expect(sql).To(Equal(` WHERE "name"=$1`))
+
+	sql, _ = where.WhereContext(ctx, nameIsFred, where.WithoutDefaults())
+	g.Expect(sql).To(Equal(` WHERE name=?`))
+
+	sql, _ = where.Where(nameIsFred, where.WithoutDefaults(), dialect.Backticks)
+	g.Expect(sql).To(Equal(" WHERE `name`=?"))
+
+	// String is not affected
+	g.Expect(nameIsFred.String()).To(Equal(`name='Fred'`))
+}
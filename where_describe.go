@@ -0,0 +1,134 @@
+package where
+
+import (
+	"strings"
+
+	"github.com/rickb777/where/v2/predicate"
+)
+
+// Describe renders an expression in English-like prose, e.g. "name is 'Fred' and age is
+// greater than 10". This is intended for showing the active filters in user interfaces
+// and audit trails; it is derived from the same expression tree as the SQL.
+//
+// The common predicates, such as those from Eq, Gt, Between, Null and In, are described
+// in words. Other conditions, and custom nodes, are described using their String method.
+// The result is blank if the expression is empty or nil.
+func Describe(wh Node) string {
+	if wh == nil {
+		return ""
+	}
+	return describe(wh)
+}
+
+func describe(node Node) string {
+	switch n := node.(type) {
+	case wrapper:
+		return describe(n.node)
+
+	case labelled:
+		return describe(n.node)
+
+	case Condition:
+		return describeCondition(plainColumn(n.Column), n.Predicate, n.Args, n)
+
+	case aggregateCondition:
+		if n.aggregate.filter != nil {
+			break
+		}
+		subject := "the " + strings.ToLower(n.aggregate.function)
+		if n.aggregate.column != "*" {
+			subject += " of " + plainColumn(n.aggregate.column)
+		}
+		return describeCondition(subject, n.predicate, n.args, n)
+
+	case Clause:
+		parts := make([]string, 0, len(n.wheres))
+		for _, w := range n.wheres {
+			s := describe(w)
+			if s == "" {
+				continue
+			}
+			if cl, isClause := unlabel(w).(Clause); isClause && cl.conjunction != n.conjunction {
+				s = "(" + s + ")"
+			}
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, strings.ToLower(n.conjunction))
+
+	case not:
+		s := describe(n.expression)
+		if s == "" {
+			return ""
+		}
+		return "not (" + s + ")"
+	}
+
+	return node.String()
+}
+
+// phrases gives the descriptions of the common predicates. Each '?' is replaced by the
+// corresponding argument value.
+var phrases = map[string]string{
+	predicate.IsNull:               " is null",
+	predicate.IsNotNull:            " is not null",
+	predicate.IsTrue:               " is true",
+	predicate.IsFalse:              " is false",
+	predicate.EqualTo:              " is ?",
+	predicate.NotEqualTo:           " is not ?",
+	predicate.NotDistinctFrom:      " is ?, treating nulls as equal",
+	predicate.GreaterThan:          " is greater than ?",
+	predicate.GreaterThanOrEqualTo: " is at least ?",
+	predicate.LessThan:             " is less than ?",
+	predicate.LessThanOrEqualTo:    " is at most ?",
+	predicate.Between:              " is between ? and ?",
+	predicate.NotBetween:           " is not between ? and ?",
+	predicate.BetweenSymmetric:     " is between ? and ?, in either order",
+	predicate.Like:                 " is like ?",
+	predicate.NotLike:              " is not like ?",
+	predicate.Regexp:               " matches ?",
+	predicate.ILike:                " is like ?, ignoring case",
+}
+
+func describeCondition(subject, pred string, args []any, node Node) string {
+	if phrase, ok := phrases[pred]; ok && strings.Count(phrase, "?") == len(args) {
+		buf := &strings.Builder{}
+		buf.WriteString(subject)
+		for _, r := range phrase {
+			if r == '?' {
+				buf.WriteString(literalValue(unwrapArg(args[0])))
+				args = args[1:]
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+		return buf.String()
+	}
+
+	if isInList(pred, len(args)) {
+		return subject + " is one of " + describeValues(args)
+	}
+
+	if isNotInList(pred, len(args)) {
+		return subject + " is not one of " + describeValues(args)
+	}
+
+	return node.String()
+}
+
+func describeValues(args []any) string {
+	values := make([]string, len(args))
+	for i, a := range args {
+		values[i] = literalValue(unwrapArg(a))
+	}
+	return strings.Join(values, ", ")
+}
+
+// isInList tests whether a predicate is an 'IN' list of n placeholders, as made by In.
+func isInList(predicate string, n int) bool {
+	return n > 0 && predicate == " IN ("+strings.Repeat(",?", n)[1:]+")"
+}
+
+// isNotInList tests whether a predicate is a 'NOT IN' list of n placeholders, as made by NotIn.
+func isNotInList(predicate string, n int) bool {
+	return n > 0 && predicate == " NOT IN ("+strings.Repeat(",?", n)[1:]+")"
+}
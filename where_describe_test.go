@@ -0,0 +1,40 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+)
+
+func TestDescribe(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		wh  where.Node
+		exp string
+	}{
+		{wh: nil, exp: ""},
+		{wh: where.NoOp(), exp: ""},
+		{wh: nameIsFred.And(where.Gt("age", 10)), exp: `name is 'Fred' and age is greater than 10`},
+		{wh: where.Or(where.Null("name"), where.Between("age", 12, 18)), exp: `name is null or age is between 12 and 18`},
+		{wh: where.And(where.NotEq("a", 1), where.Or(where.GtEq("b", 2), where.LtEq("c", 3))), exp: `a is not 1 and (b is at least 2 or c is at most 3)`},
+		{wh: where.Not(where.In("status", "open", "held")), exp: `not (status is one of 'open', 'held')`},
+		{wh: where.NotIn("status", "open", "held"), exp: `status is not one of 'open', 'held'`},
+		{wh: where.ILike("name", "fr%"), exp: `name is like 'fr%', ignoring case`},
+		{wh: where.NotLike("name", "fr%"), exp: `name is not like 'fr%'`},
+		{wh: where.EqNullSafe("a", 1), exp: `a is 1, treating nulls as equal`},
+		{wh: where.Regexp("name", "^F"), exp: `name matches '^F'`},
+		{wh: where.NotBetween("age", 12, 18), exp: `age is not between 12 and 18`},
+		{wh: where.BetweenSymmetric("age", 18, 12), exp: `age is between 18 and 12, in either order`},
+		{wh: where.Label("young", where.Lt("age", 5)), exp: `age is less than 5`},
+		{wh: where.CountGt("*", 3).And(where.SumLt("amount", 100)), exp: `the count is greater than 3 and the sum of amount is less than 100`},
+		{wh: where.Like("name", "F%").And(where.NotNull("x")), exp: `name is like 'F%' and x is not null`},
+		{wh: where.Literal("age", " > 45"), exp: `age > 45`},
+		{wh: tagsContain{"a", "b"}, exp: `tags @> ARRAY['a','b']`},
+	}
+
+	for _, c := range cases {
+		g.Expect(where.Describe(c.wh)).To(Equal(c.exp))
+	}
+}
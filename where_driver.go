@@ -0,0 +1,46 @@
+package where
+
+import (
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// WhereNamedValues constructs the SQL clause beginning "WHERE ...", as for Where, except that
+// the arguments are returned as driver.NamedValue values (see NamedValues). This suits
+// driver wrappers and interceptors that work below database/sql.
+func WhereNamedValues(wh Node, option ...dialect.FormatOption) (string, []driver.NamedValue) {
+	sql, args := Where(wh, option...)
+	return sql, NamedValues(args)
+}
+
+// HavingNamedValues constructs the SQL clause beginning "HAVING ...", as for Having, except
+// that the arguments are returned as driver.NamedValue values (see NamedValues).
+func HavingNamedValues(wh Node, option ...dialect.FormatOption) (string, []driver.NamedValue) {
+	sql, args := Having(wh, option...)
+	return sql, NamedValues(args)
+}
+
+// NamedValues converts formatted arguments to driver.NamedValue values. The ordinals start
+// from 1. Arguments that are sql.NamedArg values, as given by dialect.Named, are unwrapped
+// and their names are used; otherwise the names are blank, as for positional arguments.
+//
+// The values are not converted to driver.Value types; this is left to the driver.
+func NamedValues(args []any) []driver.NamedValue {
+	if len(args) == 0 {
+		return nil
+	}
+
+	values := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		values[i].Ordinal = i + 1
+		if named, ok := arg.(sql.NamedArg); ok {
+			values[i].Name = named.Name
+			values[i].Value = named.Value
+		} else {
+			values[i].Value = arg
+		}
+	}
+	return values
+}
@@ -0,0 +1,36 @@
+package where_test
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestWhereNamedValues(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Eq("name", "Fred").And(where.Between("age", 12, 18))
+
+	s, args := where.WhereNamedValues(wh, dialect.Dollar)
+	g.Expect(s).To(Equal(` WHERE name=$1 AND age BETWEEN $2 AND $3`))
+	g.Expect(args).To(Equal([]driver.NamedValue{
+		{Ordinal: 1, Value: "Fred"},
+		{Ordinal: 2, Value: 12},
+		{Ordinal: 3, Value: 18},
+	}))
+
+	s, args = where.HavingNamedValues(wh, dialect.Named)
+	g.Expect(s).To(Equal(` HAVING name=:name_1 AND age BETWEEN :age_1 AND :age_2`))
+	g.Expect(args).To(Equal([]driver.NamedValue{
+		{Name: "name_1", Ordinal: 1, Value: "Fred"},
+		{Name: "age_1", Ordinal: 2, Value: 12},
+		{Name: "age_2", Ordinal: 3, Value: 18},
+	}))
+
+	s, args = where.WhereNamedValues(where.NoOp())
+	g.Expect(s).To(BeEmpty())
+	g.Expect(args).To(BeNil())
+}
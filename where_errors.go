@@ -0,0 +1,156 @@
+package where
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// ErrInvalidOptions is returned by FormatE etc when the format options conflict or have
+// invalid values.
+var ErrInvalidOptions = errors.New("where: invalid format options")
+
+// ErrUnsupported is returned by FormatE etc when an expression uses a feature that the
+// dialect does not support (see VerifyDialectSupport).
+var ErrUnsupported = errors.New("where: unsupported by dialect")
+
+// Checker is an additional check made by WhereE, HavingE and FormatE before an expression
+// is formatted. Checkers are passed among the format options; they do not alter the format
+// settings and they are ignored by Where, Having etc. Limits, ScopedBuilder and
+// WithArgConverter provide checkers.
+type Checker interface {
+	dialect.FormatOption
+	Check(wh Node) error
+}
+
+// WithArgConverter provides a checker that requires every argument to be acceptable to a
+// converter (see CheckArgTypes).
+func WithArgConverter(converter driver.ValueConverter) Checker {
+	return argConverter{converter: converter}
+}
+
+type argConverter struct {
+	converter driver.ValueConverter
+}
+
+func (ac argConverter) Apply(*dialect.FormatConfig) {}
+
+func (ac argConverter) Check(wh Node) error {
+	return CheckArgTypes(wh, ac.converter)
+}
+
+// WhereE constructs the SQL clause beginning "WHERE ...", as for Where, but reports
+// problems that would otherwise produce wrong SQL silently (see FormatE).
+func WhereE(wh Node, option ...dialect.FormatOption) (string, []any, error) {
+	return formatE(whereConjunction, wh, option)
+}
+
+// HavingE constructs the SQL clause beginning "HAVING ...", as for Having, but reports
+// problems that would otherwise produce wrong SQL silently (see FormatE).
+func HavingE(wh Node, option ...dialect.FormatOption) (string, []any, error) {
+	return formatE(havingConjunction, wh, option)
+}
+
+// FormatE formats an expression, as for its Format method, but first checks for problems
+// that would otherwise produce wrong SQL silently. The error wraps
+//   - ErrInvalidOptions if the format options conflict or have invalid values, e.g. a
+//     placeholder offset with placeholders that are not numbered;
+//   - ErrUnsupported for each feature that the dialect does not support;
+//   - ErrArgCount for each node whose number of placeholders differs from its number of
+//     arguments (see CheckPlaceholders);
+//   - ErrTooManyParameters if there are more arguments than the dialect allows (see
+//     CheckParameterLimit);
+//   - the error from each Checker among the options.
+//
+// For example, with Limits and a ScopedBuilder as checkers:
+//
+//	limits := where.Limits{MaxDepth: 5, MaxConditions: 50}
+//	sql, args, err := where.WhereE(filter, dialect.Postgres, limits, tenants)
+//
+// If the expression is nil, the result is blank.
+func FormatE(wh Node, option ...dialect.FormatOption) (string, []any, error) {
+	return formatE("", wh, option)
+}
+
+func formatE(conjunction string, wh Node, option []dialect.FormatOption) (string, []any, error) {
+	if err := checkFormat(wh, option); err != nil {
+		return "", nil, err
+	}
+
+	var sql string
+	var args []any
+	if conjunction == "" {
+		if wh != nil {
+			sql, args = formatNodeWith(wh, dialect.FormatConfig{}, option)
+		}
+	} else {
+		sql, args = format(conjunction, wh, option...)
+	}
+
+	d := newConfig(dialect.FormatConfig{}, option).Dialect
+	if err := CheckParameterLimit(len(args), d); err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+func checkFormat(wh Node, option []dialect.FormatOption) error {
+	c := newConfig(dialect.FormatConfig{}, option)
+	if err := c.check(); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, issue := range VerifyDialectSupport(wh, c.Dialect) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrUnsupported, issue))
+	}
+
+	if err := CheckPlaceholders(wh); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, o := range option {
+		if ch, ok := o.(Checker); ok {
+			if err := ch.Check(wh); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// check reports conflicting or invalid settings.
+func (c config) check() error {
+	var errs []error
+	invalid := func(format string, a ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidOptions}, a...)...))
+	}
+
+	switch c.Placeholder {
+	case dialect.Query, dialect.Inline, dialect.Named:
+		if c.PlaceholderOffset != 0 {
+			invalid("placeholder offset %d requires numbered placeholders", c.PlaceholderOffset)
+		}
+	case dialect.Dollar, dialect.AtP:
+		if c.PlaceholderOffset < 0 {
+			invalid("negative placeholder offset %d", c.PlaceholderOffset)
+		}
+	default:
+		invalid("%d is not a placeholder style", c.Placeholder)
+	}
+
+	if c.InValues < 0 {
+		invalid("negative InValues %d", c.InValues)
+	}
+
+	if c.Comment != "" && (!strings.HasPrefix(c.Comment, "/*") || !strings.HasSuffix(c.Comment, "*/") ||
+		strings.Contains(c.Comment[2:len(c.Comment)-2], "*/")) {
+		invalid("comment %q is not a single SQL comment", c.Comment)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package where_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestWhereE(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Eq("name", "Fred").And(where.Gt("age", 10))
+
+	sql, args, err := where.WhereE(wh, dialect.Postgres, dialect.Dollar, where.WithPlaceholderOffset(2))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sql).To(Equal(` WHERE name=$3 AND age>$4`))
+	g.Expect(args).To(Equal([]any{"Fred", 10}))
+
+	sql, args, err = where.HavingE(where.CountGt("*", 1))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sql).To(Equal(` HAVING COUNT(*)>?`))
+	g.Expect(args).To(Equal([]any{1}))
+
+	_, _, err = where.WhereE(wh, dialect.Query, where.WithPlaceholderOffset(2))
+	g.Expect(err).To(MatchError(where.ErrInvalidOptions))
+	g.Expect(err.Error()).To(Equal("where: invalid format options: placeholder offset 2 requires numbered placeholders"))
+
+	_, _, err = where.WhereE(wh, dialect.FormatConfig{Placeholder: dialect.Backticks})
+	g.Expect(err).To(MatchError(where.ErrInvalidOptions))
+
+	_, _, err = where.WhereE(wh, dialect.FormatConfig{Comment: "/* a */ OR 1=1 /* b */"})
+	g.Expect(err).To(MatchError(where.ErrInvalidOptions))
+
+	_, _, err = where.WhereE(where.Literal("name", " SIMILAR TO ?", "F%"), dialect.Mysql)
+	g.Expect(err).To(MatchError(where.ErrUnsupported))
+	g.Expect(err.Error()).To(Equal("where: unsupported by dialect: SIMILAR TO is not supported by Mysql in name SIMILAR TO 'F%'"))
+
+	_, _, err = where.WhereE(where.Predicate("a=? AND b=?", 1))
+	g.Expect(err).To(MatchError(where.ErrArgCount))
+	g.Expect(err.Error()).To(Equal("where: wrong number of arguments: 2 placeholders but 1 arguments in a=1 AND b=?"))
+
+	_, _, err = where.WhereE(where.InSlice("id", make([]int, 2200)), dialect.SqlServer)
+	g.Expect(err).To(MatchError(where.ErrTooManyParameters))
+}
+
+func TestFormatE(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sql, args, err := where.FormatE(where.Eq("name", "Fred"), dialect.AtP)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sql).To(Equal(`name=@p1`))
+	g.Expect(args).To(Equal([]any{"Fred"}))
+
+	_, _, err = where.FormatE(where.Literal("a", "=?"))
+	g.Expect(err).To(MatchError(where.ErrArgCount))
+
+	_, _, err = where.FormatE(where.ILike("name", "f%"), dialect.Mysql, dialect.FormatConfig{Placeholder: -1})
+	g.Expect(err).To(MatchError(where.ErrInvalidOptions))
+	g.Expect(strings.Count(err.Error(), "\n")).To(BeZero())
+
+	sql, args, err = where.FormatE(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sql).To(BeEmpty())
+	g.Expect(args).To(BeNil())
+}
+
+func TestWhereE_checkers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limits := where.Limits{MaxConditions: 2}
+	tenants := where.TenantScope("tenant_id", 42)
+	wh := tenants.Scope(where.Eq("tags", []string{"a"}))
+
+	_, _, err := where.WhereE(wh, limits, tenants, where.WithArgConverter(nil))
+	g.Expect(err).To(MatchError(ContainSubstring("argument 0 ([]string) in tags=")))
+	var argErr *where.ArgError
+	g.Expect(errors.As(err, &argErr)).To(BeTrue())
+
+	// every failed check is reported
+	_, _, err = where.WhereE(wh.And(nameIsFred), limits, tenants, where.WithArgConverter(nil))
+	g.Expect(err).To(MatchError(where.ErrTooComplex))
+	g.Expect(err).To(MatchError(ContainSubstring("argument 0 ([]string)")))
+
+	sql, args, err := where.WhereE(tenants.Scope(nameIsFred), dialect.Dollar, limits, tenants, where.WithArgConverter(nil))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sql).To(Equal(` WHERE tenant_id=$1 AND name=$2`))
+	g.Expect(args).To(Equal([]any{42, "Fred"}))
+}
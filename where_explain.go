@@ -0,0 +1,102 @@
+package where
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// Explain asks the database for its query plan for "SELECT * FROM table WHERE ...", formatted
+// for dialect d using its usual quoter and placeholders, followed by the query constraint
+// (which may be nil). This helps to spot filter columns that lack a suitable index, which is
+// easily overlooked when filters are assembled dynamically.
+//
+// The dialect determines the syntax used:
+//   - SQLite: EXPLAIN QUERY PLAN;
+//   - MySQL and PostgreSQL: EXPLAIN;
+//   - SQL-Server: SET SHOWPLAN_TEXT ON, which is switched off again afterwards.
+//
+// The plan is returned as text, one line per row returned by the database, with
+// the columns of each row separated by tabs. The statement itself is not executed.
+func Explain(ctx context.Context, db *sql.DB, table string, wh Node, qc QueryConstraint, d dialect.Dialect) (string, error) {
+	query, args := explainStatement(table, wh, qc, d)
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	switch d {
+	case dialect.Sqlite:
+		query = "EXPLAIN QUERY PLAN " + query
+	case dialect.SqlServer:
+		if _, err = conn.ExecContext(ctx, "SET SHOWPLAN_TEXT ON"); err != nil {
+			return "", err
+		}
+		defer conn.ExecContext(ctx, "SET SHOWPLAN_TEXT OFF")
+	default:
+		query = "EXPLAIN " + query
+	}
+
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	return planText(rows)
+}
+
+// explainStatement builds the statement being explained.
+func explainStatement(table string, wh Node, qc QueryConstraint, d dialect.Dialect) (string, []any) {
+	config := dialect.ConfigFor(d)
+	sql, args := Where(wh, config)
+
+	top, constraint := "", ""
+	if c, ok := qc.(*Constraint); ok {
+		var more []any
+		top = c.FormatTOP(d)
+		constraint, more = c.FormatArgs(d, config, WithPlaceholderOffset(len(args)))
+		args = append(args, more...)
+	} else if qc != nil {
+		top = qc.FormatTOP(d)
+		constraint = qc.Format(d, config)
+	}
+
+	return "SELECT" + top + " * FROM " + config.Quoter.Quote(table) + sql + constraint, args
+}
+
+func planText(rows *sql.Rows) (string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var lines []string
+	for rows.Next() {
+		if err = rows.Scan(pointers...); err != nil {
+			return "", err
+		}
+
+		cells := make([]string, len(values))
+		for i, v := range values {
+			if b, isBytes := v.([]byte); isBytes {
+				v = string(b)
+			}
+			cells[i] = fmt.Sprint(v)
+		}
+		lines = append(lines, strings.Join(cells, "\t"))
+	}
+
+	return strings.Join(lines, "\n"), rows.Err()
+}
@@ -0,0 +1,95 @@
+package where_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// planDriver is a fake database driver. Each query returns a plan consisting of
+// the statements executed so far and the query itself.
+type planDriver struct{ executed []string }
+
+func (d *planDriver) Open(string) (driver.Conn, error) { return planConn{d}, nil }
+
+type planConn struct{ d *planDriver }
+
+func (c planConn) Prepare(query string) (driver.Stmt, error) { return planStmt{c.d, query}, nil }
+func (c planConn) Close() error                              { return nil }
+func (c planConn) Begin() (driver.Tx, error)                 { return nil, errors.New("unsupported") }
+
+type planStmt struct {
+	d     *planDriver
+	query string
+}
+
+func (s planStmt) Close() error  { return nil }
+func (s planStmt) NumInput() int { return -1 }
+
+func (s planStmt) Exec([]driver.Value) (driver.Result, error) {
+	s.d.executed = append(s.d.executed, s.query)
+	return driver.RowsAffected(0), nil
+}
+
+func (s planStmt) Query(args []driver.Value) (driver.Rows, error) {
+	lines := append(s.d.executed, s.query)
+	return &planRows{lines: lines, args: len(args)}, nil
+}
+
+type planRows struct {
+	lines []string
+	args  int
+}
+
+func (r *planRows) Columns() []string { return []string{"id", "detail"} }
+func (r *planRows) Close() error      { return nil }
+func (r *planRows) Next(dest []driver.Value) error {
+	if len(r.lines) == 0 {
+		return io.EOF
+	}
+	dest[0] = int64(r.args)
+	dest[1] = []byte(r.lines[0])
+	r.lines = r.lines[1:]
+	return nil
+}
+
+var planner = &planDriver{}
+
+func init() {
+	sql.Register("where-plan", planner)
+}
+
+func TestExplain(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	db, err := sql.Open("where-plan", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer db.Close()
+
+	ctx := context.Background()
+	wh := where.Eq("name", "Fred").And(where.Gt("age", 10))
+
+	plan, err := where.Explain(ctx, db, "users", wh, nil, dialect.Sqlite)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(plan).To(Equal("2\tEXPLAIN QUERY PLAN SELECT * FROM \"users\" WHERE \"name\"=? AND \"age\">?"))
+
+	plan, err = where.Explain(ctx, db, "users", wh, where.OrderBy("age").Limit(5), dialect.Postgres)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(plan).To(Equal("2\tEXPLAIN SELECT * FROM \"users\" WHERE \"name\"=$1 AND \"age\">$2 ORDER BY \"age\" LIMIT 5"))
+
+	plan, err = where.Explain(ctx, db, "users", wh, where.OrderByRank("doc", "fat cat"), dialect.Postgres)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(plan).To(Equal("3\tEXPLAIN SELECT * FROM \"users\" WHERE \"name\"=$1 AND \"age\">$2 ORDER BY ts_rank(\"doc\", plainto_tsquery($3)) DESC"))
+
+	plan, err = where.Explain(ctx, db, "users", wh, where.Limit(5), dialect.SqlServer)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(plan).To(Equal("2\tSET SHOWPLAN_TEXT ON\n2\tSELECT TOP (5) * FROM [users] WHERE [name]=@p1 AND [age]>@p2"))
+	g.Expect(planner.executed).To(Equal([]string{"SET SHOWPLAN_TEXT ON", "SET SHOWPLAN_TEXT OFF"}))
+}
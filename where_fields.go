@@ -0,0 +1,162 @@
+package where
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// AndStrings returns an 'AND' clause of equality conditions, given as column/value pairs, e.g.
+//
+//	where.AndStrings("name", form.Name, "city", form.City)
+//
+// Pairs with an empty value are skipped, so this suits search forms in which blank fields
+// mean "any". If all the values are empty, the result is a no-op. A trailing column without
+// a value is ignored.
+func AndStrings(columnValues ...string) Expression {
+	var conditions []Node
+	for i := 1; i < len(columnValues); i += 2 {
+		if columnValues[i] != "" {
+			conditions = append(conditions, Eq(columnValues[i-1], columnValues[i]))
+		}
+	}
+	return And(conditions...)
+}
+
+// Fields returns an 'AND' clause of equality conditions, given as column/value pairs, e.g.
+//
+//	where.Fields("name", form.Name, "age", form.Age, "manager_id", form.ManagerID)
+//
+// Pairs with a nil or zero value (such as "", 0, false or a nil pointer) are skipped; use
+// a pointer for a value that may legitimately be zero. If all the values are zero, the
+// result is a no-op. A trailing column without a value is ignored.
+//
+// Fields panics if any column is not a string.
+func Fields(columnValues ...any) Expression {
+	var conditions []Node
+	for i := 1; i < len(columnValues); i += 2 {
+		column, ok := columnValues[i-1].(string)
+		if !ok {
+			panic(fmt.Sprintf("where: Fields column %d is %T, not a string", i/2, columnValues[i-1]))
+		}
+
+		value := columnValues[i]
+		if !isZero(value) {
+			conditions = append(conditions, Eq(column, value))
+		}
+	}
+	return And(conditions...)
+}
+
+// MatchAll returns an 'AND' clause of conditions, one for each column in the map:
+//   - a nil value gives an 'IS NULL' condition;
+//   - a slice or array gives an 'IN' condition (see InSlice), except for []byte;
+//   - any other value gives an equality condition.
+//
+// The conditions are in the order of the column names, so that the SQL is the same
+// every time for the same columns. An empty map gives a no-op.
+func MatchAll(columnValues map[string]any) Expression {
+	return And(matches(columnValues)...)
+}
+
+// MatchAny returns an 'OR' clause of conditions, one for each column in the map, as for MatchAll.
+func MatchAny(columnValues map[string]any) Expression {
+	return Or(matches(columnValues)...)
+}
+
+func matches(columnValues map[string]any) []Node {
+	columns := make([]string, 0, len(columnValues))
+	for column := range columnValues {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	conditions := make([]Node, len(columns))
+	for i, column := range columns {
+		conditions[i] = match(column, columnValues[column])
+	}
+	return conditions
+}
+
+func match(column string, value any) Expression {
+	if value == nil {
+		return Null(column)
+	}
+	if _, isBytes := value.([]byte); !isBytes {
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Array, reflect.Slice:
+			return InSlice(column, value)
+		}
+	}
+	return Eq(column, value)
+}
+
+// EqSkipZero returns an equality condition on a column, or a no-op if the value is nil or
+// the zero value of its type (such as "", 0, false or a nil pointer), as for Fields.
+func EqSkipZero(column string, value any) Expression {
+	return SkipZero(Eq(column, value), value)
+}
+
+// SkipZero returns the expression, or a no-op if the value is nil or the zero value of its
+// type, as for EqSkipZero. The value is typically the one used in the expression, e.g.
+//
+//	where.SkipZero(where.GtEq("age", form.MinAge), form.MinAge)
+func SkipZero(exp Node, value any) Expression {
+	return If(!isZero(value), exp)
+}
+
+func isZero(value any) bool {
+	return value == nil || reflect.ValueOf(value).IsZero()
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// If returns the expression if the condition is true, or a no-op otherwise. This avoids
+// if-statements when filters depend on optional inputs, e.g.
+//
+//	where.And(
+//		where.If(form.Name != "", where.Eq("name", form.Name)),
+//		where.If(form.MinAge > 0, where.GtEq("age", form.MinAge)),
+//	)
+func If(cond bool, exp Node) Expression {
+	return IfElse(cond, exp, nil)
+}
+
+// IfElse returns the first expression if the condition is true, or the second otherwise.
+// A nil expression gives a no-op.
+func IfElse(cond bool, exp, otherwise Node) Expression {
+	if !cond {
+		exp = otherwise
+	}
+	if exp == nil {
+		return NoOp()
+	}
+	return Wrap(exp)
+}
+
+// Case is a conditional expression for Switch.
+type Case struct {
+	cond bool
+	exp  Node
+}
+
+// When returns a case for Switch.
+func When(cond bool, exp Node) Case {
+	return Case{cond: cond, exp: exp}
+}
+
+// Switch returns the expression of the first case whose condition is true, or a no-op if
+// there is none, e.g.
+//
+//	where.Switch(
+//		where.When(form.Status == "open", where.Null("closed_at")),
+//		where.When(form.Status == "closed", where.NotNull("closed_at")),
+//	)
+func Switch(cases ...Case) Expression {
+	for _, c := range cases {
+		if c.cond {
+			return If(true, c.exp)
+		}
+	}
+	return NoOp()
+}
@@ -0,0 +1,91 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+)
+
+func TestAndStrings(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(where.AndStrings("name", "Fred", "city", "", "country", "UK").String()).To(Equal(`name='Fred' AND country='UK'`))
+	g.Expect(where.AndStrings("name", "Fred", "city").String()).To(Equal(`name='Fred'`))
+	g.Expect(where.KindOf(where.AndStrings("name", ""))).To(Equal("noop"))
+}
+
+func TestFields(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	zero := 0
+	var none *int
+	wh := where.Fields("name", "Fred", "age", 0, "city", "", "manager_id", &zero, "dept", none, "x", nil, "active", true)
+	sql, args := where.Where(wh)
+	g.Expect(sql).To(Equal(` WHERE name=? AND manager_id=? AND active=?`))
+	g.Expect(args).To(Equal([]any{"Fred", &zero, true}))
+
+	g.Expect(where.KindOf(where.Fields("age", 0))).To(Equal("noop"))
+	g.Expect(func() { where.Fields(1, "x") }).To(Panic())
+}
+
+func TestIf(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(
+		where.If(true, where.Eq("name", "Fred")),
+		where.If(false, where.Gt("age", 10)),
+		where.IfElse(false, where.Null("a"), where.NotNull("a")),
+	)
+	g.Expect(wh.String()).To(Equal(`name='Fred' AND a IS NOT NULL`))
+
+	g.Expect(where.If(true, nil)).To(Equal(where.NoOp()))
+	g.Expect(where.IfElse(true, where.Null("a"), nil)).To(Equal(where.Null("a")))
+}
+
+func TestSwitch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	status := "closed"
+	wh := where.Switch(
+		where.When(status == "open", where.Null("closed_at")),
+		where.When(status == "closed", where.NotNull("closed_at")),
+		where.When(true, where.Eq("x", 1)),
+	)
+	g.Expect(wh).To(Equal(where.NotNull("closed_at")))
+
+	g.Expect(where.Switch(where.When(false, where.Null("a")))).To(Equal(where.NoOp()))
+	g.Expect(where.Switch()).To(Equal(where.NoOp()))
+}
+
+func TestSkipZero(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var none *int
+	wh := where.And(
+		where.EqSkipZero("name", "Fred"),
+		where.EqSkipZero("city", ""),
+		where.EqSkipZero("manager_id", none),
+		where.SkipZero(where.GtEq("age", 18), 18),
+		where.SkipZero(where.Lt("score", 0), 0),
+	)
+	g.Expect(wh.String()).To(Equal(`name='Fred' AND age>=18`))
+
+	g.Expect(where.EqSkipZero("a", false)).To(Equal(where.NoOp()))
+	g.Expect(where.SkipZero(where.Null("a"), nil)).To(Equal(where.NoOp()))
+}
+
+func TestMatchAll(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	values := map[string]any{
+		"status":  []string{"open", "held"},
+		"name":    "Fred",
+		"deleted": nil,
+		"hash":    []byte{1},
+	}
+
+	g.Expect(where.MatchAll(values).String()).To(Equal(`deleted IS NULL AND hash='[1]' AND name='Fred' AND status IN ('open','held')`))
+	g.Expect(where.MatchAny(map[string]any{"b": 2, "a": 1}).String()).To(Equal(`a=1 OR b=2`))
+	g.Expect(where.MatchAll(nil).String()).To(BeEmpty())
+}
@@ -0,0 +1,73 @@
+package where
+
+import (
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// DeletedColumn is the column used by NotDeleted and OnlyDeleted when no column is
+// specified. This can be altered before first use.
+var DeletedColumn = "deleted_at"
+
+// NotDeleted returns a condition that excludes soft-deleted records, i.e. those whose
+// deletion timestamp is set. By default, the column is DeletedColumn ("deleted_at").
+// If more than one column is given, all of them must be null.
+func NotDeleted(column ...string) Expression {
+	return deletedCondition(Null, column)
+}
+
+// OnlyDeleted returns a condition that selects only soft-deleted records, i.e. those whose
+// deletion timestamp is set. By default, the column is DeletedColumn ("deleted_at").
+// If more than one column is given, all of them must be non-null.
+func OnlyDeleted(column ...string) Expression {
+	return deletedCondition(NotNull, column)
+}
+
+func deletedCondition(fn func(string) Expression, column []string) Expression {
+	if len(column) == 0 {
+		return fn(DeletedColumn)
+	}
+
+	exp := make([]Node, len(column))
+	for i, c := range column {
+		exp[i] = fn(c)
+	}
+	return And(exp...)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Filter AND-s a fixed condition into every expression passed through it. This is
+// useful for conditions that apply to every query, such as excluding soft-deleted records.
+// A Filter is immutable and is safe for concurrent use.
+type Filter struct {
+	condition Expression
+}
+
+// NewFilter returns a filter that AND-s all the given conditions into every expression.
+func NewFilter(condition ...Node) Filter {
+	return Filter{condition: And(condition...)}
+}
+
+// SoftDeleteFilter returns a filter that excludes soft-deleted records from every
+// expression (see NotDeleted).
+func SoftDeleteFilter(column ...string) Filter {
+	return NewFilter(NotDeleted(column...))
+}
+
+// Apply AND-s the filter's condition with an expression. The filter's condition is
+// placed last. If the expression is nil, only the filter's condition is returned.
+func (f Filter) Apply(wh Node) Expression {
+	return And(wh, f.condition)
+}
+
+// Where constructs the SQL clause beginning "WHERE ..." after applying the filter.
+// See Where.
+func (f Filter) Where(wh Node, option ...dialect.FormatOption) (string, []any) {
+	return Where(f.Apply(wh), option...)
+}
+
+// Having constructs the SQL clause beginning "HAVING ..." after applying the filter.
+// See Having.
+func (f Filter) Having(wh Node, option ...dialect.FormatOption) (string, []any) {
+	return Having(f.Apply(wh), option...)
+}
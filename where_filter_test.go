@@ -0,0 +1,34 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestNotDeleted(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(where.NotDeleted().String()).To(Equal(`deleted_at IS NULL`))
+	g.Expect(where.NotDeleted("a", "b").String()).To(Equal(`a IS NULL AND b IS NULL`))
+	g.Expect(where.OnlyDeleted().String()).To(Equal(`deleted_at IS NOT NULL`))
+	g.Expect(where.OnlyDeleted("removed").String()).To(Equal(`removed IS NOT NULL`))
+}
+
+func TestSoftDeleteFilter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	f := where.SoftDeleteFilter()
+
+	sql, args := f.Where(nameIsFred.Or(nameIsJohn), dialect.ANSIQuotes, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE ("name"=$1 OR "name"=$2) AND "deleted_at" IS NULL`))
+	g.Expect(args).To(Equal([]any{"Fred", "John"}))
+
+	sql, args = f.Having(nil)
+	g.Expect(sql).To(Equal(` HAVING deleted_at IS NULL`))
+	g.Expect(args).To(BeNil())
+
+	g.Expect(f.Apply(where.NoOp()).String()).To(Equal(`deleted_at IS NULL`))
+}
@@ -0,0 +1,47 @@
+package where
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// Fingerprint identifies the shape of an expression, i.e. its structure, columns,
+// operators and numbers of arguments, but not the argument values. Expressions with the
+// same fingerprint produce the same SQL when formatted with the same options, so the
+// fingerprint can key a cache of prepared statements.
+//
+// The format options are included because they also affect the SQL. Custom nodes are
+// included via their SQL, formatted with '?' placeholders. With dialect.Inline, the values
+// are part of the SQL, so they are included too. The result is a hex string; it is blank
+// if the expression is nil.
+func Fingerprint(wh Node, option ...dialect.FormatOption) string {
+	if wh == nil {
+		return ""
+	}
+
+	c := newConfig(dialect.FormatConfig{}, option)
+
+	buf := &strings.Builder{}
+	if c.Placeholder == dialect.Inline {
+		sql, _ := formatNodeWith(wh, dialect.FormatConfig{}, option)
+		buf.WriteString("I(")
+		buf.WriteString(sql)
+		buf.WriteByte(')')
+	} else if !c.writeShape(buf, wh) {
+		buf.Reset()
+		sql, args := queryForm(wh, option)
+		buf.WriteString("Q(")
+		buf.WriteString(sql)
+		buf.WriteByte(0)
+		buf.WriteString(strconv.Itoa(len(args)))
+		buf.WriteByte(')')
+	}
+	c.writeSettings(buf)
+
+	sum := sha256.Sum256([]byte(buf.String()))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,38 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestFingerprint(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fp := func(name string, ages ...any) string {
+		return where.Fingerprint(where.Eq("name", name).And(where.In("age", ages...)), dialect.Dollar)
+	}
+
+	g.Expect(fp("Fred", 1, 2)).To(HaveLen(32))
+	g.Expect(fp("Fred", 1, 2)).To(Equal(fp("John", 3, 4)))
+	g.Expect(fp("Fred", 1, 2)).NotTo(Equal(fp("Fred", 1, 2, 3)))
+	g.Expect(fp("Fred", 1, 2)).NotTo(Equal(fp("Fred", 1, nil)))
+
+	wh := where.Eq("name", "Fred")
+	g.Expect(where.Fingerprint(wh)).NotTo(Equal(where.Fingerprint(where.NotEq("name", "Fred"))))
+	g.Expect(where.Fingerprint(wh)).NotTo(Equal(where.Fingerprint(where.Eq("city", "Fred"))))
+	g.Expect(where.Fingerprint(wh)).NotTo(Equal(where.Fingerprint(where.Not(wh))))
+	g.Expect(where.Fingerprint(wh, dialect.Dollar)).NotTo(Equal(where.Fingerprint(wh, dialect.AtP)))
+
+	// inlined values are part of the SQL
+	g.Expect(where.Fingerprint(where.Eq("a", 1), dialect.Inline)).NotTo(Equal(where.Fingerprint(where.Eq("a", 2), dialect.Inline)))
+	g.Expect(where.Fingerprint(where.Eq("a", 1), dialect.Inline)).To(Equal(where.Fingerprint(where.Eq("a", 1), dialect.Inline)))
+
+	// custom nodes
+	g.Expect(where.Fingerprint(where.Wrap(tagsContain{1, 2}))).To(Equal(where.Fingerprint(where.Wrap(tagsContain{3, 4}))))
+	g.Expect(where.Fingerprint(where.Wrap(tagsContain{1, 2}))).NotTo(Equal(where.Fingerprint(where.Wrap(tagsContain{1}))))
+
+	g.Expect(where.Fingerprint(nil)).To(BeEmpty())
+}
@@ -1,9 +1,12 @@
 package where
 
 import (
+	"bytes"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/rickb777/where/v2/dialect"
 	"github.com/rickb777/where/v2/quote"
@@ -11,21 +14,21 @@ import (
 
 // Format formats an expression, returning the formatted string and the list of arguments.
 func (exp not) Format(option ...dialect.FormatOption) (string, []any) {
-	placeholderOption := formatOptions(option).Placeholder()
-	quoter := quoterFromOptions(formatOptions(option).Quoter())
-	sql, args := exp.doFormat(quoter)
-	return replacePlaceholders(sql, args, placeholderOption, 1)
+	return formatTop(exp, dialect.FormatConfig{}, option)
 }
 
-func (exp not) doFormat(quoter quote.Quoter) (string, []any) {
-	sql, args := exp.expression.doFormat(quoter)
+func (exp not) doFormat(c config) (string, []any) {
+	sql, args := formatNode(exp.expression, c)
 	if sql == "" {
 		return "", args
 	}
-	if _, isClause := exp.expression.(Clause); isClause {
-		sql = "(" + sql + ")"
+	inner := unlabel(exp.expression)
+	if _, isSimple := inner.(Condition); !isSimple {
+		if _, isNot := inner.(not); !isNot {
+			sql = "(" + sql + ")"
+		}
 	}
-	return "NOT " + sql, args
+	return c.keyword("NOT ") + sql, args
 }
 
 func (exp not) String() string {
@@ -37,18 +40,21 @@ func (exp not) String() string {
 
 // Format formats an expression, returning the formatted string and the list of arguments.
 func (exp Condition) Format(option ...dialect.FormatOption) (string, []any) {
-	placeholderOption := formatOptions(option).Placeholder()
-	quoter := quoterFromOptions(formatOptions(option).Quoter())
-	sql, args := exp.doFormat(quoter)
-	return replacePlaceholders(sql, args, placeholderOption, 1)
+	return formatTop(exp, dialect.FormatConfig{}, option)
 }
 
-func (exp Condition) doFormat(quoter quote.Quoter) (string, []any) {
+func (exp Condition) doFormat(c config) (string, []any) {
+	slotName := plainColumn(exp.Column) // before adapt, which may move the column into the predicate
+	exp = c.adapt(exp)
 	buf := &strings.Builder{}
-	quoter.QuoteW(buf, exp.Column)
-	buf.WriteString(exp.Predicate)
+	c.quoteColumn(buf, exp.Column)
+	predicate, args := c.castTypedArgs(c.spacing(c.keywords(exp.Predicate)), exp.Args)
+	buf.WriteString(predicate)
 	sql := buf.String()
-	return sql, nilIfEmpty(exp.Args)
+	if c.Placeholder == dialect.Named {
+		args = namedSlots(slotName, args)
+	}
+	return sql, nilIfEmpty(args)
 }
 
 func (exp Condition) String() string {
@@ -60,38 +66,51 @@ func (exp Condition) String() string {
 
 // Format formats an expression, returning the formatted string and the list of arguments.
 func (exp Clause) Format(option ...dialect.FormatOption) (string, []any) {
-	placeholderOption := formatOptions(option).Placeholder()
-	quoter := quoterFromOptions(formatOptions(option).Quoter())
-	sql, args := exp.doFormat(quoter)
-	return replacePlaceholders(sql, args, placeholderOption, 1)
+	return formatTop(exp, dialect.FormatConfig{}, option)
 }
 
-func (exp Clause) doFormat(quoter quote.Quoter) (string, []any) {
+func (exp Clause) doFormat(c config) (string, []any) {
 	if len(exp.wheres) == 0 {
 		return "", nil
 	}
 
-	sqls := make([]string, 0, len(exp.wheres))
-	var args []any
+	buf := getBuffer()
+	defer putBuffer(buf)
+	buf.Grow(len(exp.wheres) * 24)
+
+	separator := c.keyword(exp.conjunction)
+	args := make([]any, 0, len(exp.wheres))
 
 	for _, where := range exp.wheres {
-		sql, a2 := where.doFormat(quoter)
-		if len(sql) > 0 {
-			switch w := where.(type) {
-			case Clause:
-				if w.conjunction != exp.conjunction {
-					sql = "(" + sql + ")"
-				}
-			case not:
-				sql = "(" + sql + ")"
-			}
-			sqls = append(sqls, sql)
-			args = append(args, a2...)
+		sql, a2 := formatNode(where, c)
+		if len(sql) == 0 {
+			continue
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteString(separator)
+		}
+
+		parenthesise := true
+		switch w := unlabel(where).(type) {
+		case Clause:
+			parenthesise = w.conjunction != exp.conjunction
+		case Condition, aggregateCondition:
+			parenthesise = false
+		}
+
+		if parenthesise {
+			buf.WriteByte('(')
+			buf.WriteString(sql)
+			buf.WriteByte(')')
+		} else {
+			buf.WriteString(sql)
 		}
+
+		args = append(args, a2...)
 	}
 
-	sql := strings.Join(sqls, exp.conjunction)
-	return sql, args
+	return buf.String(), nilIfEmpty(args)
 }
 
 func (exp Clause) String() string {
@@ -101,8 +120,327 @@ func (exp Clause) String() string {
 
 //-------------------------------------------------------------------------------------------------
 
+// Format formats an expression, returning the formatted string and the list of arguments.
+func (exp wrapper) Format(option ...dialect.FormatOption) (string, []any) {
+	return exp.node.Format(option...)
+}
+
+func (exp wrapper) doFormat(c config) (string, []any) {
+	return formatNode(exp.node, c)
+}
+
+func (exp wrapper) String() string {
+	return exp.node.String()
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// bufferPool holds the buffers used for formatting clauses, which reduces the number of
+// allocations when the same process formats many expressions.
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// maxPooledBuffer limits the size of the buffers kept in bufferPool, so that an unusually
+// large expression does not pin a large buffer in memory.
+const maxPooledBuffer = 64 * 1024
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBuffer {
+		bufferPool.Put(buf)
+	}
+}
+
+// formatter is implemented by the nodes provided by this package.
+type formatter interface {
+	// doFormat formats the (nested) expression as a string containing '?' placeholders.
+	doFormat(c config) (string, []any)
+}
+
+// formatNode formats any node as a string containing '?' placeholders. Foreign nodes
+// are formatted using their Format method.
+func formatNode(node Node, c config) (string, []any) {
+	if f, ok := node.(formatter); ok {
+		return f.doFormat(c)
+	}
+	return node.Format(c.nested()...)
+}
+
+// formatTop formats a whole expression, replacing the placeholders as required.
+func formatTop(exp formatter, base dialect.FormatConfig, option []dialect.FormatOption) (string, []any) {
+	c := newConfig(base, option)
+	sql, args := exp.doFormat(c)
+	return replacePlaceholders(sql, args, c, c.PlaceholderOffset+1)
+}
+
+// config holds the settings used whilst formatting an expression tree. Unlike
+// dialect.FormatConfig, its Quoter and Dialect are always set.
+type config struct {
+	dialect.FormatConfig
+}
+
+// newConfig applies the options in order to the base configuration, which is itself
+// overlaid on the package defaults. Where there are several flags of the same kind,
+// the first takes precedence (see appliedFlags).
+func newConfig(base dialect.FormatConfig, option []dialect.FormatOption) config {
+	c := config{FormatConfig: Defaults()}
+	base.Apply(&c.FormatConfig)
+
+	var applied appliedFlags
+	for _, o := range option {
+		if o != nil && applied.admit(o) {
+			o.Apply(&c.FormatConfig)
+		}
+	}
+
+	if c.Quoter == nil {
+		c.Quoter = quote.DefaultQuoter
+	}
+	if c.Dialect == 0 {
+		c.Dialect = dialect.DefaultDialect
+	}
+	return c
+}
+
+// appliedFlags records the kinds of flag option that have been applied. Only the first
+// placeholder flag, the first quoting flag and the first dialect are applied; the other
+// options, including WithPlaceholder, WithQuoter and WithDialect, are always applied.
+type appliedFlags struct {
+	placeholder, quoter, dialect bool
+}
+
+// admit tests whether an option should be applied, recording it if so.
+func (af *appliedFlags) admit(o dialect.FormatOption) bool {
+	var seen *bool
+	switch x := o.(type) {
+	case dialect.Flag:
+		if x >= dialect.NoQuotes {
+			seen = &af.quoter
+		} else {
+			seen = &af.placeholder
+		}
+	case dialect.Dialect:
+		seen = &af.dialect
+	default:
+		return true
+	}
+	if *seen {
+		return false
+	}
+	*seen = true
+	return true
+}
+
+// nested gives the options for formatting foreign nodes, which must use '?' placeholders.
+func (c config) nested() []dialect.FormatOption {
+	fc := c.FormatConfig
+	fc.Placeholder = dialect.Query
+	fc.PlaceholderOffset = 0
+	fc.Comment = ""
+	return []dialect.FormatOption{WithoutDefaults(), fc}
+}
+
+// These prefixes mark columns that are expressions, such as "LOWER(name)" (see Expr),
+// and columns that must not be quoted (see Raw).
+const (
+	exprPrefix = "\x01"
+	rawPrefix  = "\x02"
+)
+
+// plainColumn removes any expression or raw marker from a column.
+func plainColumn(column string) string {
+	if strings.HasPrefix(column, rawPrefix) {
+		return column[len(rawPrefix):]
+	}
+	return strings.TrimPrefix(column, exprPrefix)
+}
+
+// quoteColumn writes a column name, prefixed with the table alias if it is unqualified
+// and an alias has been configured, then quoted as required. For expressions, this is
+// applied to each column name within them.
+func (c config) quoteColumn(buf *strings.Builder, column string) {
+	if strings.HasPrefix(column, rawPrefix) {
+		buf.WriteString(column[len(rawPrefix):])
+		return
+	}
+	if strings.HasPrefix(column, exprPrefix) {
+		buf.WriteString(quote.MapIdentifiers(column[len(exprPrefix):], c.quotedColumn))
+		return
+	}
+	if c.TableAlias != "" && column != "" && column != "*" && !strings.Contains(column, ".") {
+		column = c.TableAlias + "." + column
+	}
+	c.Quoter.QuoteW(buf, column)
+}
+
+// quotedColumn is as for quoteColumn, returning the result as a string.
+func (c config) quotedColumn(column string) string {
+	buf := &strings.Builder{}
+	c.quoteColumn(buf, column)
+	return buf.String()
+}
+
+// keyword renders a keyword (or a phrase of keywords) in the required letter case,
+// or using its replacement if one has been configured.
+func (c config) keyword(s string) string {
+	if c.Keywords != nil {
+		word := strings.TrimSpace(s)
+		if replacement, ok := c.Keywords[word]; ok {
+			i := strings.Index(s, word)
+			return s[:i] + replacement + s[i+len(word):]
+		}
+	}
+	if c.KeywordCase == dialect.LowerCase {
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// keywords renders the SQL keywords within a predicate in the required letter case.
+// Quoted strings and identifiers are left unchanged.
+func (c config) keywords(predicate string) string {
+	if c.KeywordCase != dialect.LowerCase {
+		return predicate
+	}
+
+	buf := &strings.Builder{}
+	buf.Grow(len(predicate))
+	var quote rune
+	word := -1
+	flush := func(end int) {
+		if word >= 0 {
+			w := predicate[word:end]
+			if sqlKeywords[w] {
+				w = strings.ToLower(w)
+			}
+			buf.WriteString(w)
+			word = -1
+		}
+	}
+
+	for i, r := range predicate {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			buf.WriteRune(r)
+		case 'A' <= r && r <= 'Z' || r == '_' || word >= 0 && ('a' <= r && r <= 'z' || '0' <= r && r <= '9'):
+			if word < 0 {
+				word = i
+			}
+		default:
+			flush(i)
+			if r == '\'' || r == '"' || r == '`' {
+				quote = r
+			} else if r == '[' {
+				quote = ']'
+			}
+			buf.WriteRune(r)
+		}
+	}
+	flush(len(predicate))
+	return buf.String()
+}
+
+// spacing adjusts the whitespace in a predicate according to the Spacing setting.
+// Quoted strings and identifiers are left unchanged.
+func (c config) spacing(predicate string) string {
+	if c.Spacing != dialect.Spaced {
+		return predicate
+	}
+
+	buf := &strings.Builder{}
+	buf.Grow(len(predicate) + 8)
+	var quote byte
+	skipSpaces := false
+
+	for i := 0; i < len(predicate); i++ {
+		ch := predicate[i]
+
+		if quote != 0 {
+			buf.WriteByte(ch)
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		if skipSpaces && ch == ' ' {
+			continue
+		}
+		skipSpaces = false
+
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == ',':
+			buf.WriteString(", ")
+			skipSpaces = true
+			continue
+		case strings.IndexByte(operatorChars, ch) >= 0:
+			j := i
+			for j < len(predicate) && strings.IndexByte(operatorChars, predicate[j]) >= 0 {
+				j++
+			}
+			op := predicate[i:j]
+			before, after := byte(' '), byte(' ')
+			if i > 0 {
+				before = predicate[i-1]
+			}
+			if j < len(predicate) {
+				after = predicate[j]
+			}
+			if comparisonOperators[op] && strings.IndexByte(otherOperatorChars, before) < 0 && strings.IndexByte(otherOperatorChars, after) < 0 {
+				trimmed := strings.TrimRight(buf.String(), " ")
+				buf.Reset()
+				buf.WriteString(trimmed)
+				buf.WriteString(" " + op + " ")
+				skipSpaces = true
+				i = j - 1
+				continue
+			}
+			buf.WriteString(op)
+			i = j - 1
+			continue
+		}
+		buf.WriteByte(ch)
+	}
+	return buf.String()
+}
+
+const (
+	operatorChars      = "=<>!"
+	otherOperatorChars = "@&|~:#-+*/^%"
+)
+
+var comparisonOperators = map[string]bool{
+	"=": true, "<>": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true, "<=>": true,
+}
+
+var sqlKeywords = map[string]bool{
+	"ALL": true, "AND": true, "ANY": true, "BETWEEN": true, "DISTINCT": true, "ESCAPE": true,
+	"EXISTS": true, "FALSE": true, "FROM": true, "ILIKE": true, "IN": true, "IS": true,
+	"LIKE": true, "LOWER": true, "NOT": true, "NULL": true, "OR": true, "REGEXP": true, "SELECT": true,
+	"SIMILAR": true, "SOME": true, "SYMMETRIC": true, "TO": true, "TRUE": true,
+	"VALUES": true, "WHERE": true,
+}
+
+//-------------------------------------------------------------------------------------------------
+
 func prefixFromOption(option dialect.FormatOption) (prefix string) {
-	switch option {
+	if option == nil {
+		return ""
+	}
+
+	var c dialect.FormatConfig
+	option.Apply(&c)
+
+	switch c.Placeholder {
 	case dialect.Dollar:
 		prefix = "$"
 	case dialect.AtP:
@@ -111,30 +449,78 @@ func prefixFromOption(option dialect.FormatOption) (prefix string) {
 	return prefix
 }
 
-func quoterFromOptions(option dialect.FormatOption) (quoter quote.Quoter) {
-	quoter = quote.DefaultQuoter
-	switch option {
-	case dialect.NoQuotes:
-		quoter = quote.None
-	case dialect.ANSIQuotes:
-		quoter = quote.ANSI
-	case dialect.Backticks:
-		quoter = quote.Backticks
-	case dialect.SquareBrackets:
-		quoter = quote.SquareBrackets
+func replacePlaceholders(sql string, args []any, c config, from int) (string, []any) {
+	if c.Placeholder == dialect.Query {
+		return sql, bindArgs(args, c.Dialect)
+	}
+
+	buf := &strings.Builder{}
+	buf.Grow(len(sql) + len(sql)/2) // heuristic
+	args = writePlaceholders(&sqlWriter{w: buf}, sql, args, c, from)
+	return buf.String(), args
+}
+
+// writePlaceholders writes the SQL, replacing every '?' placeholder as the configuration
+// requires, and returns the arguments to bind.
+func writePlaceholders(w *sqlWriter, sql string, args []any, c config, from int) []any {
+	switch c.Placeholder {
+	case dialect.Inline:
+		return writeInline(w, sql, args, c.Dialect)
+	case dialect.Named:
+		return writeNamed(w, sql, args, c.Dialect)
 	}
-	return quoter
+
+	writeNumbered(w, sql, prefixFromOption(c.Placeholder), from)
+	return bindArgs(args, c.Dialect)
 }
 
-func replacePlaceholders(sql string, args []any, opt dialect.FormatOption, from int) (string, []any) {
-	if opt == dialect.Inline {
-		return InlinePlaceholders(sql, args)
+// writeNumbered writes the SQL, replacing every '?' placeholder with the prefix and a
+// number counting up from 'from'. The SQL is unchanged if the prefix is blank.
+func writeNumbered(w *sqlWriter, sql, prefix string, from int) {
+	for n := from; prefix != ""; n++ {
+		i := placeholderIndex(sql)
+		if i < 0 {
+			break
+		}
+		w.WriteString(sql[:i])
+		w.WriteString(prefix)
+		w.WriteString(strconv.Itoa(n))
+		sql = sql[i+1:]
 	}
+	w.WriteString(sql)
+}
 
-	return ReplacePlaceholders(sql, opt, from), nilIfEmpty(args)
+// placeholderIndex gives the index of the first '?' placeholder in the SQL, or -1 if there
+// is none. A '?' within a quoted string or identifier is not a placeholder.
+func placeholderIndex(sql string) int {
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		switch ch := sql[i]; {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '?':
+			return i
+		}
+	}
+	return -1
+}
+
+// countPlaceholders counts the '?' placeholders in the SQL (see placeholderIndex).
+func countPlaceholders(sql string) int {
+	n := 0
+	for i := placeholderIndex(sql); i >= 0; i = placeholderIndex(sql) {
+		n++
+		sql = sql[i+1:]
+	}
+	return n
 }
 
 // ReplacePlaceholders replaces all "?" placeholders with numbered placeholders, using the given dialect option.
+// A '?' within a quoted string or identifier is not a placeholder, so it is left alone.
 //   - For PostgreSQL these will be "$1" and upward placeholders so the dalect.Dollar option should be supplied.
 //   - For SQL-Server there will be "@p1" and upward placeholders so the dialect.AtP should be supplied.
 //
@@ -145,60 +531,49 @@ func ReplacePlaceholders(sql string, opt dialect.FormatOption, from ...int) stri
 		return sql
 	}
 
-	n := 0
-	for _, r := range sql {
-		if r == '?' {
-			n++
-		}
-	}
-
 	count := 1
 	if len(from) > 0 {
 		count = from[0]
 	}
 
 	buf := &strings.Builder{}
-	buf.Grow(len(sql) + n*(len(prefix)+2))
-
-	for _, r := range sql {
-		if r == '?' {
-			buf.WriteString(prefix)
-			buf.WriteString(strconv.Itoa(count))
-			count++
-		} else {
-			buf.WriteRune(r)
-		}
-	}
-
+	buf.Grow(len(sql) + len(sql)/2) // heuristic
+	writeNumbered(&sqlWriter{w: buf}, sql, prefix, count)
 	return buf.String()
 }
 
 // InlinePlaceholders replaces every '?' placeholder with the corresponding argument value.
-// Number and boolean arguments are inserted verbatim. Everything else is inserted
+// Number and boolean arguments are inserted verbatim. Slices and arrays, other than byte
+// slices, are inserted as Postgres arrays, e.g. "ARRAY[1,2]". Everything else is inserted
 // in string syntax, i.e. enclosed in single quote marks.
 //
 // The modified string is returned, along with any remaining arguments.
 func InlinePlaceholders(query string, args []any) (string, []any) {
-	n := 0
-	for _, r := range query {
-		if r == '?' {
-			n++
-		}
-	}
+	return inlinePlaceholders(query, args, 0)
+}
 
+func inlinePlaceholders(query string, args []any, d dialect.Dialect) (string, []any) {
 	buf := &strings.Builder{}
 	buf.Grow(len(query) + len(query)/2) // heuristic
+	args = writeInline(&sqlWriter{w: buf}, query, args, d)
+	return buf.String(), args
+}
 
-	for _, r := range query {
-		if r == '?' && len(args) > 0 {
-			buf.WriteString(literalValue(args[0]))
-			args = args[1:]
-		} else {
-			buf.WriteRune(r)
+// writeInline writes the query with its placeholders replaced by the argument values,
+// returning any remaining arguments.
+func writeInline(w *sqlWriter, query string, args []any, d dialect.Dialect) []any {
+	for len(args) > 0 {
+		i := placeholderIndex(query)
+		if i < 0 {
+			break
 		}
+		w.WriteString(query[:i])
+		w.WriteString(dialectLiteral(args[0], d))
+		args = args[1:]
+		query = query[i+1:]
 	}
-
-	return buf.String(), nilIfEmpty(args)
+	w.WriteString(query)
+	return nilIfEmpty(args)
 }
 
 func literalValue(v any) string {
@@ -231,36 +606,58 @@ func literalValue(v any) string {
 		return strconv.FormatFloat(x, 'f', -1, 64)
 	}
 
+	if s, ok := numericLiteral(v); ok {
+		return s
+	}
+	if s, ok := valuerLiteral(v); ok {
+		return s
+	}
+	if s, ok := arrayLiteral(v); ok {
+		return s
+	}
+
 	s := fmt.Sprintf(`%v`, v)
 	s = strings.ReplaceAll(s, "'", "''")
 	return "'" + s + "'"
 }
 
-func nilIfEmpty(args []any) []any {
-	if len(args) > 0 {
-		return args
+// arrayLiteral renders a slice or array, other than a byte slice, as a Postgres array,
+// e.g. "ARRAY[1,2]". An empty one is rendered as '{}' so that its type can be inferred.
+func arrayLiteral(v any) (string, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return "", false
+		}
+	default:
+		return "", false
 	}
-	return nil
-}
 
-//-------------------------------------------------------------------------------------------------
-
-type formatOptions []dialect.FormatOption
+	if rv.Len() == 0 {
+		return "'{}'", true
+	}
 
-func (opts formatOptions) Placeholder() dialect.FormatOption {
-	for _, o := range opts {
-		if o <= dialect.Inline {
-			return o
+	buf := &strings.Builder{}
+	buf.WriteString("ARRAY[")
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		e := rv.Index(i).Interface()
+		if e == nil {
+			buf.WriteString("NULL")
+		} else {
+			buf.WriteString(literalValue(e))
 		}
 	}
-	return 0
+	buf.WriteByte(']')
+	return buf.String(), true
 }
 
-func (opts formatOptions) Quoter() dialect.FormatOption {
-	for _, o := range opts {
-		if o >= dialect.NoQuotes {
-			return o
-		}
+func nilIfEmpty(args []any) []any {
+	if len(args) > 0 {
+		return args
 	}
-	return 0
+	return nil
 }
@@ -0,0 +1,79 @@
+package where
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// TypedArg is an argument value with a type hint, such as "uuid", "jsonb" or "timestamptz".
+// For Postgres, the placeholder is cast to the type, e.g. "?::uuid"; this avoids errors
+// such as "operator does not exist" when the driver cannot infer the parameter type.
+// Inlined values are cast similarly. For other dialects, the hint is ignored.
+//
+// Use Typed to construct these.
+type TypedArg struct {
+	Value any
+	Type  string
+}
+
+var validTypeHint = regexp.MustCompile(`^[\pL_][\pL\pN_ ]*(\[])?(\([0-9, ]*\))?(\[])?$`)
+
+// Typed attaches a type hint to an argument value, e.g.
+//
+//	where.Eq("id", where.Typed(id, "uuid"))
+//
+// The hint must be a simple SQL type name, optionally with a size and array
+// brackets, e.g. "varchar(20)" or "int[]"; otherwise Typed panics.
+func Typed(value any, sqlType string) TypedArg {
+	if !validTypeHint.MatchString(sqlType) {
+		panic("where: invalid type hint " + sqlType)
+	}
+	return TypedArg{Value: value, Type: sqlType}
+}
+
+func hasTypedArg(args []any) bool {
+	for _, arg := range args {
+		if _, ok := arg.(TypedArg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// castTypedArgs unwraps any typed arguments, adding casts to the corresponding
+// placeholders as required by the dialect.
+func (c config) castTypedArgs(predicate string, args []any) (string, []any) {
+	if !hasTypedArg(args) {
+		return predicate, args
+	}
+
+	values := make([]any, len(args))
+	buf := &strings.Builder{}
+	buf.Grow(len(predicate) + 10*len(args))
+
+	i := 0
+	for ; i < len(args); i++ {
+		j := placeholderIndex(predicate)
+		if j < 0 {
+			break
+		}
+		buf.WriteString(predicate[:j+1])
+		predicate = predicate[j+1:]
+
+		if t, ok := args[i].(TypedArg); ok {
+			values[i] = t.Value
+			if c.Dialect == dialect.Postgres {
+				buf.WriteString("::")
+				buf.WriteString(t.Type)
+			}
+		} else {
+			values[i] = args[i]
+		}
+	}
+	buf.WriteString(predicate)
+
+	copy(values[i:], args[i:])
+	return buf.String(), values
+}
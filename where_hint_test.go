@@ -0,0 +1,33 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestTyped(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(
+		where.Eq("id", where.Typed(uuidString, "uuid")),
+		where.Between("at", where.Typed("2020-01-01", "timestamptz"), "2021-01-01"),
+	)
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE id=$1::uuid AND at BETWEEN $2::timestamptz AND $3`))
+	g.Expect(args).To(Equal([]any{uuidString, "2020-01-01", "2021-01-01"}))
+
+	sql, _ = where.Where(wh, dialect.Postgres, dialect.Inline)
+	g.Expect(sql).To(Equal(` WHERE id='` + uuidString + `'::uuid AND at BETWEEN '2020-01-01'::timestamptz AND '2021-01-01'`))
+
+	sql, args = where.Where(wh, dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE id=? AND at BETWEEN ? AND ?`))
+	g.Expect(args).To(Equal([]any{uuidString, "2020-01-01", "2021-01-01"}))
+
+	g.Expect(where.Typed(1, "int[]").Type).To(Equal("int[]"))
+	g.Expect(where.Typed("x", "character varying(20)").Type).To(Equal("character varying(20)"))
+	g.Expect(func() { where.Typed(1, "int; DROP TABLE x") }).To(Panic())
+}
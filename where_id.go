@@ -0,0 +1,145 @@
+package where
+
+import (
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/predicate"
+)
+
+// EqID returns an equality condition on an identifier column, such as a UUID.
+// The id may be a [16]byte, a []byte, a string, a fmt.Stringer (as provided by
+// typical UUID types) or an encoding.BinaryMarshaler.
+//
+// The value is bound in the representation expected by the dialect (see
+// dialect.Dialect.IDBinding), which is selected using a dialect option when
+// formatting, e.g.
+//
+//	where.Where(where.EqID("id", id), dialect.Mysql.Option())
+func EqID(column string, id any) Expression {
+	return Literal(column, predicate.EqualTo, idArg{value: id})
+}
+
+// NotEqID returns a not equal condition on an identifier column, such as a UUID.
+// The id is handled as for EqID.
+func NotEqID(column string, id any) Expression {
+	return Literal(column, predicate.NotEqualTo, idArg{value: id})
+}
+
+// InID returns an 'IN' condition on an identifier column, such as a UUID.
+// Each id is handled as for EqID; nil values are treated as for In.
+func InID(column string, ids ...any) Expression {
+	values := make([]any, len(ids))
+	for i, id := range ids {
+		if id != nil {
+			values[i] = idArg{value: id}
+		}
+	}
+	return In(column, values...)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// idArg holds an identifier value until the dialect is known.
+type idArg struct {
+	value any
+}
+
+// String gives the canonical string form. This is also used when inlining values.
+func (a idArg) String() string {
+	if s, ok := a.asString(); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", a.value)
+}
+
+// bind converts the identifier to the representation required. If this is not possible,
+// the original value is returned unchanged; the database driver will then report it.
+func (a idArg) bind(binding dialect.IDBinding) any {
+	var v any
+	var ok bool
+	switch binding {
+	case dialect.IDAsBytes:
+		v, ok = a.asBytes()
+	default:
+		v, ok = a.asString()
+	}
+	if !ok {
+		return a.value
+	}
+	return v
+}
+
+func (a idArg) asString() (string, bool) {
+	switch x := a.value.(type) {
+	case string:
+		return x, true
+	case [16]byte:
+		return formatUUID(x[:]), true
+	case []byte:
+		return formatUUID(x), true
+	case fmt.Stringer:
+		return x.String(), true
+	case encoding.BinaryMarshaler:
+		b, err := x.MarshalBinary()
+		if err != nil {
+			return "", false
+		}
+		return formatUUID(b), true
+	}
+	return "", false
+}
+
+func (a idArg) asBytes() ([]byte, bool) {
+	switch x := a.value.(type) {
+	case [16]byte:
+		return x[:], true
+	case []byte:
+		return x, true
+	case encoding.BinaryMarshaler:
+		b, err := x.MarshalBinary()
+		return b, err == nil
+	case string:
+		return parseUUID(x)
+	case fmt.Stringer:
+		return parseUUID(x.String())
+	}
+	return nil, false
+}
+
+// formatUUID renders 16 bytes in the canonical 8-4-4-4-12 form. Other lengths are
+// rendered as plain hexadecimal.
+func formatUUID(b []byte) string {
+	s := hex.EncodeToString(b)
+	if len(b) != 16 {
+		return s
+	}
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// parseUUID accepts hexadecimal strings, ignoring any dashes and braces.
+func parseUUID(s string) ([]byte, bool) {
+	s = strings.NewReplacer("-", "", "{", "", "}", "").Replace(s)
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+func bindArgs(args []any, d dialect.Dialect) []any {
+	var bound []any
+	for i, arg := range args {
+		if id, ok := arg.(idArg); ok {
+			if bound == nil {
+				bound = make([]any, len(args))
+				copy(bound, args)
+			}
+			bound[i] = id.bind(d.IDBinding())
+		}
+	}
+	if bound != nil {
+		return bound
+	}
+	return nilIfEmpty(args)
+}
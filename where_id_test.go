@@ -0,0 +1,49 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+type testUUID [16]byte
+
+func (u testUUID) String() string {
+	return "00112233-4455-6677-8899-aabbccddeeff"
+}
+
+var uuidBytes = [16]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+
+const uuidString = "00112233-4455-6677-8899-aabbccddeeff"
+
+func TestEqID(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, id := range []any{uuidBytes, uuidBytes[:], uuidString, testUUID(uuidBytes)} {
+		sql, args := where.Where(where.EqID("id", id), dialect.Dollar, dialect.Postgres.Option())
+		g.Expect(sql).To(Equal(" WHERE id=$1"))
+		g.Expect(args).To(Equal([]any{uuidString}))
+
+		sql, args = where.Where(where.NotEqID("id", id), dialect.Mysql.Option())
+		g.Expect(sql).To(Equal(" WHERE id<>?"))
+		g.Expect(args).To(Equal([]any{uuidBytes[:]}))
+
+		s := where.EqID("id", id).String()
+		g.Expect(s).To(Equal("id='" + uuidString + "'"))
+	}
+}
+
+func TestInID(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sql, args := where.Where(where.InID("id", uuidBytes, nil), dialect.Mysql.Option())
+	g.Expect(sql).To(Equal(" WHERE id IN (?) OR id IS NULL"))
+	g.Expect(args).To(Equal([]any{uuidBytes[:]}))
+
+	// the default dialect is Sqlite
+	sql, args = where.Where(where.InID("id", uuidBytes))
+	g.Expect(sql).To(Equal(" WHERE id IN (?)"))
+	g.Expect(args).To(Equal([]any{uuidString}))
+}
@@ -0,0 +1,88 @@
+package where
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// ErrNotEligible is returned by IndexWhere for expressions that cannot be rendered without
+// bind parameters.
+var ErrNotEligible = errors.New("where: expression is not eligible for an index predicate")
+
+var subquery = regexp.MustCompile(`\bSELECT\b`)
+
+// IndexWhere constructs the SQL clause beginning "WHERE ..." for contexts that do not accept
+// bind parameters, such as the predicates of partial indexes and conflict targets, e.g.
+//
+//	CREATE INDEX orders_open ON orders (customer_id) WHERE status='open'
+//	INSERT ... ON CONFLICT (customer_id) WHERE status='open' DO NOTHING
+//
+// The values are inlined (see dialect.Inline), whatever the options specify. The expression
+// is checked first; the error wraps ErrNotEligible if it contains
+//   - a value that is not a string, number (including DecimalValue and the math/big
+//     types), boolean or identifier (see EqID), because these cannot be inlined reliably;
+//   - an aggregate function (see Aggregate), or
+//   - a sub-query.
+//
+// If the expression is empty or nil, the returned string will be blank.
+func IndexWhere(wh Node, option ...dialect.FormatOption) (string, error) {
+	if err := checkEligible(wh); err != nil {
+		return "", err
+	}
+
+	option = append(option[:len(option):len(option)], WithPlaceholder(dialect.Inline))
+	sql, args := Where(wh, option...)
+	if len(args) > 0 {
+		return "", fmt.Errorf("%w: %d values could not be inlined", ErrNotEligible, len(args))
+	}
+	return sql, nil
+}
+
+func checkEligible(wh Node) error {
+	var errs []error
+	check := func(n Node, sql string, args []any) {
+		if subquery.MatchString(strings.ToUpper(stripQuoted(sql))) {
+			errs = append(errs, fmt.Errorf("%w: sub-query in %s", ErrNotEligible, n))
+		}
+		for i, arg := range args {
+			if !inlinable(unwrapArg(arg)) {
+				errs = append(errs, fmt.Errorf("%w: argument %d (%T) in %s", ErrNotEligible, i, arg, n))
+			}
+		}
+	}
+
+	Walk(wh, func(n Node) bool {
+		switch x := n.(type) {
+		case Condition:
+			check(n, x.Predicate, x.Args)
+		case aggregateCondition:
+			errs = append(errs, fmt.Errorf("%w: aggregate in %s", ErrNotEligible, n))
+			return false
+		case Clause, not, Composite:
+			return true
+		default:
+			sql, args := n.Format(dialect.Query)
+			check(n, sql, args)
+		}
+		return true
+	})
+
+	return errors.Join(errs...)
+}
+
+// inlinable tests whether a value can be inlined reliably, i.e. in the same way by every database.
+func inlinable(v any) bool {
+	switch v.(type) {
+	case string, bool,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return true
+	}
+	_, ok := numericLiteral(v)
+	return ok
+}
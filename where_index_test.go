@@ -0,0 +1,47 @@
+package where_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestIndexWhere(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	s, err := where.IndexWhere(where.Eq("status", "open").And(where.Null("deleted_at")), dialect.Dollar, dialect.ANSIQuotes)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(Equal(` WHERE "status"='open' AND "deleted_at" IS NULL`))
+
+	s, err = where.IndexWhere(where.Eq("active", true), dialect.SqlServer)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(Equal(` WHERE active=1`))
+
+	s, err = where.IndexWhere(where.EqID("id", [16]byte{1}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(Equal(` WHERE id='01000000-0000-0000-0000-000000000000'`))
+
+	s, err = where.IndexWhere(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(BeEmpty())
+}
+
+func TestIndexWhere_ineligible(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, err := where.IndexWhere(where.Lt("created_at", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	g.Expect(err).To(MatchError(where.ErrNotEligible))
+	g.Expect(err).To(MatchError(ContainSubstring("argument 0 (time.Time)")))
+
+	_, err = where.IndexWhere(where.Predicate("EXISTS (SELECT 1 FROM offers)").And(where.Eq("x", "select")))
+	g.Expect(err).To(MatchError(ContainSubstring("sub-query in EXISTS (SELECT 1 FROM offers)")))
+
+	_, err = where.IndexWhere(where.CountGt("*", 1))
+	g.Expect(err).To(MatchError(ContainSubstring("aggregate in COUNT(*)>1")))
+
+	_, err = where.IndexWhere(where.Not(tagsContain{"a", []int{1}}))
+	g.Expect(err).To(MatchError(ContainSubstring("argument 1 ([]int)")))
+}
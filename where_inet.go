@@ -0,0 +1,26 @@
+package where
+
+import "net/netip"
+
+// InetContainedBy returns a condition that an inet or cidr column is contained within a
+// network, i.e. "column << ?" for Postgres. The network is bound as its string form,
+// e.g. "10.0.0.0/8", which suits all Postgres drivers.
+//
+// Because the network is a parsed netip.Prefix, it is always a well-formed literal and
+// so can safely be inlined (see dialect.Inline), e.g. to give "ip << '10.0.0.0/8'".
+func InetContainedBy(column string, network netip.Prefix) Expression {
+	return Literal(column, " << ?", network.String())
+}
+
+// InetContainedByOrEqual is as for InetContainedBy, also matching the network itself,
+// i.e. "column <<= ?".
+func InetContainedByOrEqual(column string, network netip.Prefix) Expression {
+	return Literal(column, " <<= ?", network.String())
+}
+
+// InetContains returns a condition that a cidr or inet column is a network containing an
+// address, i.e. "column >> ?" for Postgres. As for InetContainedBy, the address is bound
+// as its string form and can safely be inlined.
+func InetContains(column string, addr netip.Addr) Expression {
+	return Literal(column, " >> ?", addr.String())
+}
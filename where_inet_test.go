@@ -0,0 +1,30 @@
+package where_test
+
+import (
+	"net/netip"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestInet(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.InetContainedBy("ip", netip.MustParsePrefix("10.0.0.0/8")).
+		Or(where.InetContains("network", netip.MustParseAddr("192.168.1.7")))
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE ip << $1 OR network >> $2`))
+	g.Expect(args).To(Equal([]any{"10.0.0.0/8", "192.168.1.7"}))
+
+	sql, args = where.Where(wh, dialect.Postgres, dialect.Inline)
+	g.Expect(sql).To(Equal(` WHERE ip << '10.0.0.0/8' OR network >> '192.168.1.7'`))
+	g.Expect(args).To(BeNil())
+
+	g.Expect(where.VerifyDialectSupport(wh, dialect.Postgres)).To(BeEmpty())
+	g.Expect(where.VerifyDialectSupport(wh, dialect.Mysql)).To(HaveLen(2))
+
+	g.Expect(where.InetContainedByOrEqual("ip", netip.MustParsePrefix("fd00::/8")).String()).To(Equal(`ip <<= 'fd00::/8'`))
+}
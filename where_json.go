@@ -0,0 +1,449 @@
+package where
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// jsonNode is the JSON representation of every kind of node. It is a tagged union: the
+// kind is that given by KindOf, or "aggregate" for conditions on filtered aggregates.
+type jsonNode struct {
+	Kind      string            `json:"kind"`
+	Label     string            `json:"label,omitempty"`
+	Function  string            `json:"function,omitempty"`
+	Column    string            `json:"column,omitempty"`
+	Expr      bool              `json:"expr,omitempty"`
+	Raw       bool              `json:"raw,omitempty"`
+	Predicate string            `json:"predicate,omitempty"`
+	Args      []json.RawMessage `json:"args,omitempty"`
+	Filter    *jsonNode         `json:"filter,omitempty"`
+	Terms     []*jsonNode       `json:"terms,omitempty"`
+	Operand   *jsonNode         `json:"operand,omitempty"`
+	Node      json.RawMessage   `json:"node,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Expressions are represented as a tree of JSON
+// objects, each having a "kind" (see KindOf), so that they can be stored or sent to
+// another service and then formatted for any dialect (see UnmarshalExpression).
+//
+// Argument values are JSON values, except that times, byte slices, typed values (see
+// Typed) and identifiers (see EqID) are represented as objects with a type and a value,
+// e.g. {"type":"time","value":"..."}, to preserve their types. Any other value that is
+// marshalled as an object, such as a map, is tagged in the same way, with the type "json",
+// so that it cannot be mistaken for one of these. Values of other types, such as decimals,
+// are marshalled using encoding/json and may lose their types. Custom nodes must be registered (see
+// RegisterNode); they are marshalled using encoding/json.
+func (exp Condition) MarshalJSON() ([]byte, error) { return marshalNode(exp) }
+
+// MarshalJSON implements json.Marshaler (see Condition.MarshalJSON).
+func (exp Clause) MarshalJSON() ([]byte, error) { return marshalNode(exp) }
+
+// MarshalJSON implements json.Marshaler (see Condition.MarshalJSON).
+func (exp not) MarshalJSON() ([]byte, error) { return marshalNode(exp) }
+
+// MarshalJSON implements json.Marshaler (see Condition.MarshalJSON).
+func (exp wrapper) MarshalJSON() ([]byte, error) { return marshalNode(exp) }
+
+// MarshalJSON implements json.Marshaler (see Condition.MarshalJSON).
+func (exp labelled) MarshalJSON() ([]byte, error) { return marshalNode(exp) }
+
+// MarshalJSON implements json.Marshaler (see Condition.MarshalJSON).
+func (exp aggregateCondition) MarshalJSON() ([]byte, error) { return marshalNode(exp) }
+
+// UnmarshalJSON implements json.Unmarshaler. The JSON must represent a condition.
+func (exp *Condition) UnmarshalJSON(data []byte) error {
+	node, err := UnmarshalExpression(data)
+	if err != nil {
+		return err
+	}
+	c, ok := unlabel(unwrap(node)).(Condition)
+	if !ok {
+		return fmt.Errorf("where: cannot unmarshal %s into a Condition", KindOf(node))
+	}
+	*exp = c
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. If the JSON represents anything other than
+// a clause, it becomes the only term of an 'AND' clause.
+func (exp *Clause) UnmarshalJSON(data []byte) error {
+	node, err := UnmarshalExpression(data)
+	if err != nil {
+		return err
+	}
+	c, ok := unwrap(node).(Clause)
+	if !ok {
+		c = Clause{wheres: []Node{unwrap(node)}, conjunction: and}
+	}
+	*exp = c
+	return nil
+}
+
+// UnmarshalExpression reconstructs an expression from its JSON representation (see
+// Condition.MarshalJSON). Custom nodes must have been registered (see RegisterNode).
+//
+// Be careful not to allow injection attacks: the JSON contains the columns, predicates
+// and aggregate functions, which become part of the SQL without being checked, as for
+// Literal. So only accept JSON from a trusted source, such as your own database or
+// services; to filter on behalf of a client, use Parse or package wherehttp instead.
+//
+// Argument values are decoded as for encoding/json, except as described for
+// Condition.MarshalJSON, so some types are lost: integers become int64 and other numbers
+// become float64. Slices whose elements all decode to the same type have that element
+// type, e.g. []int becomes []int64; otherwise they are []any.
+func UnmarshalExpression(data []byte) (Expression, error) {
+	var j jsonNode
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("where: %w", err)
+	}
+	node, err := fromJSON(&j)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(node), nil
+}
+
+func unwrap(node Node) Node {
+	if w, isWrapper := node.(wrapper); isWrapper {
+		return w.node
+	}
+	return node
+}
+
+//-------------------------------------------------------------------------------------------------
+
+func marshalNode(node Node) ([]byte, error) {
+	j, err := toJSON(node)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(j)
+}
+
+func toJSON(node Node) (*jsonNode, error) {
+	switch n := node.(type) {
+	case wrapper:
+		return toJSON(n.node)
+
+	case labelled:
+		j, err := toJSON(n.node)
+		if err != nil {
+			return nil, err
+		}
+		j.Label = n.label
+		return j, nil
+
+	case Condition:
+		j := &jsonNode{Kind: "condition", Column: plainColumn(n.Column), Predicate: n.Predicate}
+		j.Expr = strings.HasPrefix(n.Column, exprPrefix)
+		j.Raw = strings.HasPrefix(n.Column, rawPrefix)
+		var err error
+		j.Args, err = encodeArgs(n.Args)
+		return j, err
+
+	case aggregateCondition:
+		j := &jsonNode{Kind: "aggregate", Function: n.aggregate.function, Column: n.aggregate.column, Predicate: n.predicate}
+		var err error
+		if n.aggregate.filter != nil {
+			if j.Filter, err = toJSON(n.aggregate.filter); err != nil {
+				return nil, err
+			}
+		}
+		j.Args, err = encodeArgs(n.args)
+		return j, err
+
+	case Clause:
+		j := &jsonNode{Kind: KindOf(n)}
+		for _, w := range n.wheres {
+			t, err := toJSON(w)
+			if err != nil {
+				return nil, err
+			}
+			j.Terms = append(j.Terms, t)
+		}
+		return j, nil
+
+	case not:
+		operand, err := toJSON(n.expression)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonNode{Kind: "not", Operand: operand}, nil
+	}
+
+	kind := KindOf(node)
+	if kind == "" {
+		return nil, fmt.Errorf("where: cannot marshal unregistered node type %T", node)
+	}
+	data, err := json.Marshal(node)
+	if err != nil {
+		return nil, fmt.Errorf("where: %w", err)
+	}
+	return &jsonNode{Kind: kind, Node: data}, nil
+}
+
+func fromJSON(j *jsonNode) (node Node, err error) {
+	if j == nil {
+		return nil, errors.New("where: missing node in JSON")
+	}
+
+	switch j.Kind {
+	case "condition":
+		if j.Function != "" {
+			return nil, errors.New("where: a condition cannot have a function; use an aggregate")
+		}
+		c := Condition{Column: j.Column, Predicate: j.Predicate}
+		switch {
+		case j.Expr:
+			c.Column = exprPrefix + c.Column
+		case j.Raw:
+			c.Column = rawPrefix + c.Column
+		}
+		c.Args, err = decodeArgs(j.Args)
+		node = c
+
+	case "aggregate":
+		a := aggregateCondition{aggregate: FilteredAggregate{function: j.Function, column: j.Column}, predicate: j.Predicate}
+		if j.Filter != nil {
+			if a.aggregate.filter, err = fromJSON(j.Filter); err != nil {
+				return nil, err
+			}
+		}
+		a.args, err = decodeArgs(j.Args)
+		node = a
+
+	case "and", "or", "noop":
+		c := Clause{conjunction: and}
+		if j.Kind == "or" {
+			c.conjunction = or
+		}
+		for _, t := range j.Terms {
+			w, err := fromJSON(t)
+			if err != nil {
+				return nil, err
+			}
+			c.wheres = append(c.wheres, w)
+		}
+		node = c
+
+	case "not":
+		operand, err := fromJSON(j.Operand)
+		if err != nil {
+			return nil, err
+		}
+		node = not{expression: operand}
+
+	default:
+		registry.RLock()
+		t, exists := registry.byName[j.Kind]
+		registry.RUnlock()
+		if !exists {
+			return nil, fmt.Errorf("where: unknown node kind %q in JSON", j.Kind)
+		}
+		v := reflect.New(t)
+		if err = json.Unmarshal(j.Node, v.Interface()); err != nil {
+			return nil, fmt.Errorf("where: %s: %w", j.Kind, err)
+		}
+		node = v.Elem().Interface().(Node)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if j.Label != "" {
+		node = labelled{node: node, label: j.Label}
+	}
+	return node, nil
+}
+
+//-------------------------------------------------------------------------------------------------
+
+func encodeArgs(args []any) ([]json.RawMessage, error) {
+	if args == nil {
+		return nil, nil
+	}
+	encoded := make([]json.RawMessage, len(args))
+	for i, a := range args {
+		var err error
+		if encoded[i], err = encodeArg(a); err != nil {
+			return nil, err
+		}
+	}
+	return encoded, nil
+}
+
+// These are the types of argument that are represented as JSON objects. Every JSON object
+// in the arguments has a "type" and a "value", so that values of different types, such as
+// a time and a map with a "time" key, cannot be confused.
+const (
+	argTime  = "time"  // value is an RFC-3339 string
+	argBytes = "bytes" // value is a base-64 string
+	argTyped = "typed" // value is an argument; "sqlType" is the type hint (see Typed)
+	argID    = "id"    // value is an argument (see EqID)
+	argJSON  = "json"  // value is any other JSON object, such as a map or a struct
+)
+
+// jsonArg is the JSON representation of the arguments that are tagged with their type.
+type jsonArg struct {
+	Type    string `json:"type"`
+	SQLType string `json:"sqlType,omitempty"`
+	Value   any    `json:"value"`
+}
+
+func encodeArg(arg any) (json.RawMessage, error) {
+	var v any
+	switch a := arg.(type) {
+	case time.Time:
+		v = jsonArg{Type: argTime, Value: a.Format(time.RFC3339Nano)}
+	case []byte:
+		v = jsonArg{Type: argBytes, Value: base64.StdEncoding.EncodeToString(a)}
+	case TypedArg:
+		value, err := encodeArg(a.Value)
+		if err != nil {
+			return nil, err
+		}
+		v = jsonArg{Type: argTyped, SQLType: a.Type, Value: value}
+	case idArg:
+		value, err := encodeArg(a.value)
+		if err != nil {
+			return nil, err
+		}
+		v = jsonArg{Type: argID, Value: value}
+	default:
+		if rv := reflect.ValueOf(arg); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			values := make([]any, rv.Len())
+			for i := range values {
+				values[i] = rv.Index(i).Interface()
+			}
+			elements, err := encodeArgs(values)
+			if err != nil {
+				return nil, err
+			}
+			v = elements
+		} else {
+			v = arg
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("where: argument %T: %w", arg, err)
+	}
+	if _, isTagged := v.(jsonArg); !isTagged && len(data) > 0 && data[0] == '{' {
+		return json.Marshal(jsonArg{Type: argJSON, Value: json.RawMessage(data)})
+	}
+	return data, nil
+}
+
+func decodeArgs(encoded []json.RawMessage) ([]any, error) {
+	if encoded == nil {
+		return nil, nil
+	}
+	args := make([]any, len(encoded))
+	for i, e := range encoded {
+		d := json.NewDecoder(bytes.NewReader(e))
+		d.UseNumber()
+		var v any
+		if err := d.Decode(&v); err != nil {
+			return nil, fmt.Errorf("where: %w", err)
+		}
+		var err error
+		if args[i], err = decodeArg(v); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// decodeArg converts a decoded JSON value into an argument. Integers become int64 and
+// other numbers become float64. Arrays become typed slices where possible.
+func decodeArg(v any) (any, error) {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i, nil
+		}
+		return x.Float64()
+
+	case []any:
+		for i, e := range x {
+			var err error
+			if x[i], err = decodeArg(e); err != nil {
+				return nil, err
+			}
+		}
+		return typedSlice(x), nil
+
+	case map[string]any:
+		return decodeTaggedArg(x)
+	}
+	return v, nil
+}
+
+// decodeTaggedArg converts a JSON object into an argument according to its type.
+func decodeTaggedArg(x map[string]any) (any, error) {
+	kind, _ := x["type"].(string)
+	switch kind {
+	case argTime:
+		s, _ := x["value"].(string)
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("where: %w", err)
+		}
+		return t, nil
+
+	case argBytes:
+		s, _ := x["value"].(string)
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("where: %w", err)
+		}
+		return b, nil
+
+	case argTyped:
+		value, err := decodeArg(x["value"])
+		if err != nil {
+			return nil, err
+		}
+		t, _ := x["sqlType"].(string)
+		return TypedArg{Value: value, Type: t}, nil
+
+	case argID:
+		value, err := decodeArg(x["value"])
+		if err != nil {
+			return nil, err
+		}
+		return idArg{value: value}, nil
+
+	case argJSON:
+		return plainJSON(x["value"]), nil
+	}
+	return nil, fmt.Errorf("where: unknown argument type %q in JSON", kind)
+}
+
+// plainJSON converts the numbers within a decoded JSON value, as for decodeArg.
+func plainJSON(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		f, _ := x.Float64()
+		return f
+	case []any:
+		for i, e := range x {
+			x[i] = plainJSON(e)
+		}
+	case map[string]any:
+		for k, e := range x {
+			x[k] = plainJSON(e)
+		}
+	}
+	return v
+}
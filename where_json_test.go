@@ -0,0 +1,175 @@
+package where_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// near is a registered custom node that can be marshalled.
+type near struct {
+	Lat, Lng float64
+}
+
+func (n near) Format(option ...dialect.FormatOption) (string, []any) {
+	return where.Predicate("ST_DWithin(location, ST_MakePoint(?,?), 1000)", n.Lng, n.Lat).Format(option...)
+}
+
+func (n near) String() string {
+	sql, _ := n.Format(dialect.Inline)
+	return sql
+}
+
+func init() {
+	where.RegisterNode("near", near{})
+}
+
+func TestJSON_roundTrip(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	t0 := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	wh := where.And(
+		nameIsFred,
+		where.Or(where.Between("age", 12, 18.5), where.Null("age")),
+		where.Not(where.In("tags", "a", "b")),
+		where.Gt("created", t0),
+		where.Eq("data", []byte{1, 2}),
+		where.Eq("ids", where.Typed([]int{1, 2}, "int[]")),
+		where.EqID("id", "abc"),
+		where.Label("expr", where.Expr("LOWER(name)", "=?", "fred")),
+		where.Eq(where.Raw("created::date"), "2024-01-02"),
+		where.AggregateFilter("COUNT", "*", where.Eq("status", "late")).Compare(">?", 3),
+		where.Wrap(near{Lat: 51.5, Lng: -0.1}),
+	)
+
+	data, err := json.Marshal(wh)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	back, err := where.UnmarshalExpression(data)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for _, d := range []dialect.Dialect{dialect.Postgres, dialect.Mysql, dialect.SqlServer} {
+		sql1, args1 := where.Where(wh, d, d.Placeholder())
+		sql2, args2 := where.Where(back, d, d.Placeholder())
+		g.Expect(sql2).To(Equal(sql1))
+		g.Expect(args2).To(HaveLen(len(args1)))
+	}
+
+	_, args := where.Where(back, dialect.Postgres)
+	g.Expect(args).To(Equal([]any{
+		"Fred", int64(12), 18.5, "a", "b", t0, []byte{1, 2}, []int64{1, 2}, "abc", "fred", "2024-01-02",
+		"late", int64(3), -0.1, 51.5,
+	}))
+	g.Expect(where.Labels(back)).To(ContainElement("expr"))
+}
+
+func TestJSON_format(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	data, err := json.Marshal(where.Or(nameIsFred, where.Not(where.Null("age"))))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(Equal(`{"kind":"or","terms":[` +
+		`{"kind":"condition","column":"name","predicate":"=?","args":["Fred"]},` +
+		`{"kind":"not","operand":{"kind":"condition","column":"age","predicate":" IS NULL"}}]}`))
+
+	data, err = json.Marshal(where.NoOp())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(Equal(`{"kind":"noop"}`))
+}
+
+func TestJSON_slices(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.EqAnyOf("id", []int{1, 2}).And(where.EqAnyOf("name", []string{"a"})).And(where.Literal("x", "=?", []any{1, "a"}))
+	data, err := json.Marshal(wh)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	back, err := where.UnmarshalExpression(data)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(where.Args(back)).To(Equal([]any{[]int64{1, 2}, []string{"a"}, []any{int64(1), "a"}}))
+}
+
+func TestJSON_unmarshalInto(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var c where.Condition
+	g.Expect(json.Unmarshal([]byte(`{"kind":"condition","column":"age","predicate":">?","args":[5]}`), &c)).To(Succeed())
+	g.Expect(c).To(Equal(where.Condition{Column: "age", Predicate: ">?", Args: []any{int64(5)}}))
+
+	err := json.Unmarshal([]byte(`{"kind":"noop"}`), &c)
+	g.Expect(err).To(MatchError("where: cannot unmarshal noop into a Condition"))
+
+	var cl where.Clause
+	g.Expect(json.Unmarshal([]byte(`{"kind":"condition","column":"age","predicate":">?","args":[5]}`), &cl)).To(Succeed())
+	g.Expect(cl.String()).To(Equal(`age>5`))
+
+	filter := struct {
+		Name   string       `json:"name"`
+		Filter where.Clause `json:"filter"`
+	}{}
+	g.Expect(json.Unmarshal([]byte(`{"name":"x","filter":{"kind":"and","terms":[
+		{"kind":"condition","column":"a","predicate":"=?","args":[1]},
+		{"kind":"condition","column":"b","predicate":"=?","args":[2]}]}}`), &filter)).To(Succeed())
+	g.Expect(filter.Filter.String()).To(Equal(`a=1 AND b=2`))
+}
+
+func TestJSON_mapArguments(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// maps whose keys resemble the tagged types are not mistaken for them
+	maps := []map[string]any{
+		{"type": "x", "typed": 1},
+		{"time": "2024-01-02T03:04:05Z"},
+		{"bytes": "AQI="},
+		{"id": "abc"},
+		{"type": "time", "value": "2024-01-02T03:04:05Z"},
+	}
+
+	for _, m := range maps {
+		data, err := json.Marshal(where.Eq("attrs", m))
+		g.Expect(err).NotTo(HaveOccurred())
+
+		back, err := where.UnmarshalExpression(data)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		_, args := where.Where(back)
+		g.Expect(args).To(HaveLen(1))
+		g.Expect(args[0]).To(BeAssignableToTypeOf(map[string]any{}))
+		g.Expect(args[0]).To(HaveLen(len(m)))
+	}
+
+	data, err := json.Marshal(where.Eq("created", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring(`"args":[{"type":"time","value":"2024-01-02T03:04:05Z"}]`))
+
+	data, err = json.Marshal(where.Eq("attrs", map[string]any{"n": 1}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring(`"args":[{"type":"json","value":{"n":1}}]`))
+
+	back, err := where.UnmarshalExpression(data)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(where.Args(back)).To(Equal([]any{map[string]any{"n": int64(1)}}))
+
+	_, err = where.UnmarshalExpression([]byte(`{"kind":"condition","column":"a","predicate":"=?","args":[{"time":"x"}]}`))
+	g.Expect(err).To(MatchError(`where: unknown argument type "" in JSON`))
+}
+
+func TestJSON_errors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, err := json.Marshal(where.Wrap(pair{a: nameIsFred, b: nameIsJohn}).And(where.Wrap(tagsContain{1, 2})))
+	g.Expect(err).To(MatchError(ContainSubstring("where: cannot marshal unregistered node type where_test.tagsContain")))
+
+	_, err = where.UnmarshalExpression([]byte(`{"kind":"bogus"}`))
+	g.Expect(err).To(MatchError(`where: unknown node kind "bogus" in JSON`))
+
+	_, err = where.UnmarshalExpression([]byte(`{"kind":"not"}`))
+	g.Expect(err).To(MatchError(`where: missing node in JSON`))
+
+	_, err = where.UnmarshalExpression([]byte(`[`))
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,93 @@
+package where
+
+import (
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// Label attaches a label to an expression, such as the name of the business rule that it
+// implements. The label does not alter the SQL; it survives composition with And, Or and
+// Not, so that it can be retrieved later using LabelOf or Labels, e.g. for audit logs that
+// record which filters were applied to a query.
+//
+//	wh := where.Label("adults", where.GtEq("age", 18)).And(other)
+//	where.Labels(wh) // gives ["adults"]
+func Label(label string, exp Node) Expression {
+	if exp == nil {
+		return NoOp()
+	}
+	return labelled{node: exp, label: label}
+}
+
+// LabelOf gets the label attached to a node by Label, if any. This is typically
+// used within a function passed to Walk.
+func LabelOf(node Node) (string, bool) {
+	if w, isWrapper := node.(wrapper); isWrapper {
+		node = w.node
+	}
+	l, ok := node.(labelled)
+	return l.label, ok
+}
+
+// Labels gets all the labels within an expression tree, in depth-first order.
+func Labels(wh Node) []string {
+	var labels []string
+	Walk(wh, func(n Node) bool {
+		if label, ok := LabelOf(n); ok {
+			labels = append(labels, label)
+		}
+		return true
+	})
+	return labels
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// labelled is an expression with a label attached.
+type labelled struct {
+	node  Node
+	label string
+}
+
+// And combines two conditions into a clause that requires they are both true.
+func (exp labelled) And(other Node) Expression {
+	return Clause{wheres: []Node{exp}, conjunction: and}.And(other)
+}
+
+// Or combines two conditions into a clause that requires either is true.
+func (exp labelled) Or(other Node) Expression {
+	return Clause{wheres: []Node{exp}, conjunction: or}.Or(other)
+}
+
+// Format formats an expression, returning the formatted string and the list of arguments.
+func (exp labelled) Format(option ...dialect.FormatOption) (string, []any) {
+	return formatTop(exp, dialect.FormatConfig{}, option)
+}
+
+func (exp labelled) doFormat(c config) (string, []any) {
+	return formatNode(exp.node, c)
+}
+
+func (exp labelled) String() string {
+	return exp.node.String()
+}
+
+// Children returns the labelled node.
+func (exp labelled) Children() []Node {
+	return []Node{exp.node}
+}
+
+// WithChildren returns a copy with the labelled node replaced, keeping the label.
+func (exp labelled) WithChildren(children []Node) Node {
+	return labelled{node: children[0], label: exp.label}
+}
+
+// unlabel gets the node beneath any labels.
+func unlabel(node Node) Node {
+	for {
+		l, ok := node.(labelled)
+		if !ok {
+			return node
+		}
+		node = l.node
+	}
+}
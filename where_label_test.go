@@ -0,0 +1,37 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestLabel(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	adults := where.Label("adults", where.GtEq("age", 18))
+	named := where.Label("named", where.Or(nameIsFred, nameIsJohn))
+
+	wh := where.And(adults, where.Not(named)).Or(where.Label("tagged", tagsContain{"a", "b"}))
+
+	sql, args := where.Where(wh, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE (age>=$1 AND (NOT (name=$2 OR name=$3))) OR (tags @> ARRAY[$4,$5])`))
+	g.Expect(args).To(Equal([]any{18, "Fred", "John", "a", "b"}))
+	g.Expect(wh.String()).To(Equal(`(age>=18 AND (NOT (name='Fred' OR name='John'))) OR (tags @> ARRAY['a','b'])`))
+
+	g.Expect(where.Labels(wh)).To(Equal([]string{"adults", "named", "tagged"}))
+	g.Expect(where.Labels(where.Dedupe(wh))).To(Equal([]string{"adults", "named", "tagged"}))
+	g.Expect(where.Labels(nameIsFred)).To(BeNil())
+
+	label, ok := where.LabelOf(adults)
+	g.Expect(label).To(Equal("adults"))
+	g.Expect(ok).To(BeTrue())
+
+	_, ok = where.LabelOf(nameIsFred)
+	g.Expect(ok).To(BeFalse())
+
+	g.Expect(where.KindOf(named)).To(Equal("or"))
+	g.Expect(where.Label("x", nil)).To(Equal(where.NoOp()))
+}
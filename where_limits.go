@@ -0,0 +1,107 @@
+package where
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// ErrTooComplex is returned when an expression exceeds the Limits.
+var ErrTooComplex = errors.New("where: expression is too complex")
+
+// Limits guards against pathological expressions, such as filters built by users or
+// tenants that are deeply nested or that have very many conditions. Zero fields impose
+// no limit. A Limits value is typically declared once and passed as a Checker to WhereE
+// etc, which check each expression before it is formatted, e.g.
+//
+//	limits := where.Limits{MaxDepth: 5, MaxConditions: 50, MaxArgs: 500}
+//	sql, args, err := where.WhereE(filter, dialect.Postgres, limits)
+type Limits struct {
+	// MaxDepth limits the nesting of clauses and 'NOT' expressions; a single condition
+	// has depth 1 and "a AND (b OR c)" has depth 3.
+	MaxDepth int
+
+	// MaxConditions limits the number of conditions, including custom nodes that do
+	// not contain other nodes.
+	MaxConditions int
+
+	// MaxArgs limits the total number of arguments.
+	MaxArgs int
+}
+
+// Apply has no effect; Limits is a Checker, not a format setting.
+func (l Limits) Apply(*dialect.FormatConfig) {}
+
+// Check returns an error wrapping ErrTooComplex if the expression exceeds any of the limits.
+// The traversal stops as soon as a limit is exceeded, so even very large expressions are
+// rejected cheaply.
+func (l Limits) Check(wh Node) error {
+	if wh == nil {
+		return nil
+	}
+
+	m := &measure{limits: l}
+	m.visit(wh, 1)
+	return m.err
+}
+
+// measure accumulates the size of an expression, stopping at the first excess.
+type measure struct {
+	limits     Limits
+	conditions int
+	args       int
+	err        error
+}
+
+func (m *measure) visit(node Node, depth int) {
+	if m.err != nil {
+		return
+	}
+	if limit := m.limits.MaxDepth; limit > 0 && depth > limit {
+		m.err = fmt.Errorf("%w: depth exceeds %d", ErrTooComplex, limit)
+		return
+	}
+
+	switch n := node.(type) {
+	case wrapper:
+		m.visit(n.node, depth)
+		return
+	case labelled:
+		m.visit(n.node, depth)
+		return
+	case Condition:
+		m.count(len(n.Args))
+		return
+	case aggregateCondition:
+		if n.aggregate.filter != nil {
+			m.visit(n.aggregate.filter, depth+1)
+		}
+		m.count(len(n.args))
+		return
+	}
+
+	kids := children(node)
+	if kids == nil {
+		if _, isClause := node.(Clause); !isClause {
+			_, args := node.Format(dialect.Query)
+			m.count(len(args))
+		}
+		return
+	}
+	for _, k := range kids {
+		m.visit(k, depth+1)
+	}
+}
+
+// count adds a condition with some arguments.
+func (m *measure) count(args int) {
+	m.conditions++
+	m.args += args
+	switch {
+	case m.limits.MaxConditions > 0 && m.conditions > m.limits.MaxConditions:
+		m.err = fmt.Errorf("%w: more than %d conditions", ErrTooComplex, m.limits.MaxConditions)
+	case m.limits.MaxArgs > 0 && m.args > m.limits.MaxArgs:
+		m.err = fmt.Errorf("%w: more than %d arguments", ErrTooComplex, m.limits.MaxArgs)
+	}
+}
@@ -0,0 +1,57 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestLimits(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(nameIsFred, where.Or(ageGt5Int, where.In("x", 1, 2, 3)))
+
+	g.Expect(where.Limits{}.Check(wh)).To(Succeed())
+	g.Expect(where.Limits{MaxDepth: 3, MaxConditions: 3, MaxArgs: 5}.Check(wh)).To(Succeed())
+	g.Expect(where.Limits{MaxDepth: 1}.Check(nameIsFred)).To(Succeed())
+	g.Expect(where.Limits{MaxDepth: 1}.Check(nil)).To(Succeed())
+
+	err := where.Limits{MaxDepth: 2}.Check(wh)
+	g.Expect(err).To(MatchError(where.ErrTooComplex))
+	g.Expect(err.Error()).To(Equal("where: expression is too complex: depth exceeds 2"))
+
+	err = where.Limits{MaxConditions: 2}.Check(wh)
+	g.Expect(err).To(MatchError("where: expression is too complex: more than 2 conditions"))
+
+	err = where.Limits{MaxArgs: 4}.Check(wh)
+	g.Expect(err).To(MatchError("where: expression is too complex: more than 4 arguments"))
+
+	// NOT, labels and custom nodes
+	g.Expect(where.Limits{MaxDepth: 2}.Check(where.Not(where.Label("l", nameIsFred)))).To(Succeed())
+	g.Expect(where.Limits{MaxDepth: 2}.Check(where.Not(where.Not(nameIsFred)))).To(MatchError(where.ErrTooComplex))
+	g.Expect(where.Limits{MaxArgs: 2}.Check(where.Wrap(tagsContain{1, 2, 3}))).To(MatchError(where.ErrTooComplex))
+	g.Expect(where.Limits{MaxConditions: 1}.Check(where.Wrap(pair{a: nameIsFred, b: ageGt5Int}))).To(MatchError(where.ErrTooComplex))
+
+	// a pathological tree
+	deep := where.Node(nameIsFred)
+	for i := 0; i < 10000; i++ {
+		deep = where.Not(deep)
+	}
+	g.Expect(where.Limits{MaxDepth: 10}.Check(deep)).To(MatchError(where.ErrTooComplex))
+}
+
+func TestLimits_checker(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limits := where.Limits{MaxConditions: 2}
+
+	sql, args, err := where.WhereE(where.And(nameIsFred, ageGt5Int), dialect.Dollar, limits)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sql).To(Equal(` WHERE name=$1 AND age>$2`))
+	g.Expect(args).To(Equal([]any{"Fred", 5}))
+
+	_, _, err = where.HavingE(where.And(nameIsFred, ageGt5Int, nameIsJohn), limits)
+	g.Expect(err).To(MatchError(where.ErrTooComplex))
+}
@@ -0,0 +1,76 @@
+package where
+
+// Map rebuilds an expression tree, passing each condition to fn and using the expression
+// it returns in its place. This allows conditions to be rewritten, e.g. to translate the
+// field names used by an API into database column names, or to be removed, e.g. when they
+// are not allowed. fn can return the condition unchanged; if it returns nil or NoOp, the
+// condition is removed.
+//
+// Clauses that become empty are removed too, as are 'NOT' expressions whose operands have
+// been removed; if nothing remains, the result is NoOp. The children of custom nodes are
+// mapped if they implement Composite; any that are removed are replaced by NoOp.
+func Map(wh Node, fn func(Condition) Expression) Expression {
+	if wh == nil {
+		return nil
+	}
+	mapped := mapConditions(wh, fn)
+	if mapped == nil {
+		return NoOp()
+	}
+	return Wrap(mapped)
+}
+
+// mapConditions returns the mapped node, or nil if it has been removed.
+func mapConditions(node Node, fn func(Condition) Expression) Node {
+	switch n := node.(type) {
+	case wrapper:
+		return mapConditions(n.node, fn)
+
+	case Condition:
+		replacement := fn(n)
+		if replacement == nil || isNoOp(replacement) {
+			return nil
+		}
+		return replacement
+
+	case Clause:
+		wheres := make([]Node, 0, len(n.wheres))
+		for _, w := range n.wheres {
+			if m := mapConditions(w, fn); m != nil {
+				wheres = append(wheres, m)
+			}
+		}
+		if len(wheres) == 0 {
+			return nil
+		}
+		return Clause{wheres: wheres, conjunction: n.conjunction}
+
+	case not:
+		inner := mapConditions(n.expression, fn)
+		if inner == nil {
+			return nil
+		}
+		return not{expression: inner}
+
+	case Composite:
+		kids := n.Children()
+		replaced := make([]Node, len(kids))
+		for i, k := range kids {
+			if replaced[i] = mapConditions(k, fn); replaced[i] == nil {
+				replaced[i] = NoOp()
+			}
+		}
+		return n.WithChildren(replaced)
+	}
+
+	return node
+}
+
+// isNoOp tests whether a node is an empty clause.
+func isNoOp(node Node) bool {
+	if w, isWrapper := node.(wrapper); isWrapper {
+		node = w.node
+	}
+	c, ok := node.(Clause)
+	return ok && len(c.wheres) == 0
+}
@@ -0,0 +1,58 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+)
+
+func TestMap_rename(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	columns := map[string]string{"name": "full_name", "age": "age_years"}
+	rename := func(c where.Condition) where.Expression {
+		c.Column = columns[c.Column]
+		return c
+	}
+
+	wh := where.And(nameIsFred, where.Or(ageGt5Int, where.Not(where.Null("age"))))
+	g.Expect(where.Map(wh, rename).String()).To(Equal(`full_name='Fred' AND (age_years>5 OR (NOT age_years IS NULL))`))
+
+	// the original is unchanged
+	g.Expect(wh.String()).To(Equal(`name='Fred' AND (age>5 OR (NOT age IS NULL))`))
+}
+
+func TestMap_remove(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	allowed := func(c where.Condition) where.Expression {
+		if c.Column == "age" {
+			return nil
+		}
+		return c
+	}
+
+	wh := where.And(nameIsFred, where.Or(ageGt5Int, where.Null("age")), where.Not(ageGt5Int))
+	g.Expect(where.Map(wh, allowed).String()).To(Equal(`name='Fred'`))
+
+	wh = where.Or(ageGt5Int, where.Wrap(pair{a: ageGt5Int, b: nameIsJohn}))
+	g.Expect(where.Map(wh, allowed).String()).To(Equal(`(name='John')`))
+
+	g.Expect(where.Map(ageGt5Int, allowed).String()).To(BeEmpty())
+	g.Expect(where.Map(nil, allowed)).To(BeNil())
+}
+
+func TestMap_expand(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	expand := func(c where.Condition) where.Expression {
+		if c.Column == "name" {
+			return where.Or(c, where.Eq("nickname", c.Args[0]))
+		}
+		return c
+	}
+
+	wh := where.And(nameIsFred, ageGt5Int)
+	g.Expect(where.Map(wh, expand).String()).To(Equal(`(name='Fred' OR nickname='Fred') AND age>5`))
+}
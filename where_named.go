@@ -0,0 +1,94 @@
+package where
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// namedSlot holds an argument value along with the column it applies to, whilst
+// formatting with named placeholders.
+type namedSlot struct {
+	column string
+	value  any
+}
+
+func namedSlots(column string, args []any) []any {
+	slots := make([]any, len(args))
+	for i, arg := range args {
+		slots[i] = namedSlot{column: column, value: arg}
+	}
+	return slots
+}
+
+// namedPlaceholders replaces every '?' placeholder with a named bind derived from the
+// column name, e.g. ":name_1". Each name is numbered so that it is unique, even where
+// the same column is used more than once. Arguments that do not correspond to any
+// column are named ":p_1" etc.
+//
+// The modified string is returned, along with the arguments as sql.NamedArg values.
+func namedPlaceholders(query string, args []any, d dialect.Dialect) (string, []any) {
+	buf := &strings.Builder{}
+	buf.Grow(len(query) + 8*len(args))
+	args = writeNamed(&sqlWriter{w: buf}, query, args, d)
+	return buf.String(), args
+}
+
+// writeNamed writes the query with named placeholders, as for namedPlaceholders,
+// returning the arguments as sql.NamedArg values.
+func writeNamed(w *sqlWriter, query string, args []any, d dialect.Dialect) []any {
+	names := make([]string, 0, len(args))
+	values := make([]any, 0, len(args))
+	counts := make(map[string]int)
+
+	for len(names) < len(args) {
+		i := placeholderIndex(query)
+		if i < 0 {
+			break
+		}
+
+		base, value := "p", args[len(names)]
+		if slot, ok := value.(namedSlot); ok {
+			value = slot.value
+			if slot.column != "" {
+				base = bindName(slot.column)
+			}
+		}
+
+		counts[base]++
+		name := base + "_" + strconv.Itoa(counts[base])
+		names = append(names, name)
+		values = append(values, value)
+
+		w.WriteString(query[:i])
+		w.WriteString(":")
+		w.WriteString(name)
+		query = query[i+1:]
+	}
+	w.WriteString(query)
+
+	values = bindArgs(values, d)
+	named := make([]any, len(values))
+	for i, v := range values {
+		named[i] = sql.Named(names[i], v)
+	}
+	return nilIfEmpty(named)
+}
+
+// bindName converts a column name into a valid bind name, replacing other characters
+// (such as the '.' in "p.name") with underscores.
+func bindName(column string) string {
+	b := []rune(column)
+	for i, r := range b {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			b[i] = '_'
+		}
+	}
+	if !unicode.IsLetter(b[0]) {
+		return "p" + string(b)
+	}
+	return string(b)
+}
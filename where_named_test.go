@@ -0,0 +1,33 @@
+package where_test
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestNamedPlaceholders(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(
+		where.Eq("p.name", "Fred"),
+		where.Between("age", 12, 18),
+		where.Or(where.Eq("p.name", "John"), where.Predicate("x > ?", 1)),
+		tagsContain{"a", "b"},
+	)
+
+	s, args := where.Where(wh, dialect.Named)
+	g.Expect(s).To(Equal(` WHERE p.name=:p_name_1 AND age BETWEEN :age_1 AND :age_2 AND (p.name=:p_name_2 OR x > :p_1) AND (tags @> ARRAY[:p_2,:p_3])`))
+	g.Expect(args).To(Equal([]any{
+		sql.Named("p_name_1", "Fred"),
+		sql.Named("age_1", 12),
+		sql.Named("age_2", 18),
+		sql.Named("p_name_2", "John"),
+		sql.Named("p_1", 1),
+		sql.Named("p_2", "a"),
+		sql.Named("p_3", "b"),
+	}))
+}
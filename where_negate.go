@@ -0,0 +1,128 @@
+package where
+
+import (
+	"strings"
+
+	"github.com/rickb777/where/v2/predicate"
+)
+
+// PushNot rewrites an expression so that negation applies only to individual conditions,
+// using De Morgan's laws. For example, "NOT (a=1 AND b>2)" becomes "a<>1 OR b<=2". Some
+// query planners optimise the rewritten form much better.
+//
+// Negated conditions are replaced by their opposites where this keeps the same meaning
+// with SQL's three-valued logic, e.g. '=' and '<>', '>' and '<=', 'IS NULL' and 'IS NOT NULL',
+// 'IN' and 'NOT IN', 'LIKE' and 'NOT LIKE', 'BETWEEN' and 'NOT BETWEEN'. Other conditions,
+// and custom nodes, remain within 'NOT'. Double negations are removed.
+func PushNot(wh Node) Expression {
+	if wh == nil {
+		return nil
+	}
+	return Wrap(pushNot(wh, false))
+}
+
+func pushNot(node Node, negate bool) Node {
+	switch n := node.(type) {
+	case wrapper:
+		return pushNot(n.node, negate)
+
+	case not:
+		return pushNot(n.expression, !negate)
+
+	case Clause:
+		if len(n.wheres) == 0 {
+			return n
+		}
+		wheres := make([]Node, len(n.wheres))
+		for i, w := range n.wheres {
+			wheres[i] = pushNot(w, negate)
+		}
+		conjunction := n.conjunction
+		if negate {
+			conjunction = opposite(conjunction)
+		}
+		return Clause{wheres: wheres, conjunction: conjunction}
+
+	case Condition:
+		if negate {
+			if opp, ok := negateCondition(n); ok {
+				return opp
+			}
+		}
+
+	case aggregateCondition:
+		if opp, ok := oppositePredicates[n.predicate]; ok && negate {
+			n.predicate = opp
+			return n
+		}
+
+	case Composite:
+		kids := n.Children()
+		replaced := make([]Node, len(kids))
+		for i, k := range kids {
+			replaced[i] = pushNot(k, false)
+		}
+		node = n.WithChildren(replaced)
+	}
+
+	if negate {
+		return not{expression: node}
+	}
+	return node
+}
+
+func opposite(conjunction string) string {
+	if conjunction == and {
+		return or
+	}
+	return and
+}
+
+// oppositePredicates pairs the predicates whose conditions are each the negation of the other.
+var oppositePredicates = map[string]string{
+	predicate.EqualTo:              predicate.NotEqualTo,
+	predicate.NotEqualTo:           predicate.EqualTo,
+	predicate.GreaterThan:          predicate.LessThanOrEqualTo,
+	predicate.GreaterThanOrEqualTo: predicate.LessThan,
+	predicate.LessThan:             predicate.GreaterThanOrEqualTo,
+	predicate.LessThanOrEqualTo:    predicate.GreaterThan,
+	predicate.IsNull:               predicate.IsNotNull,
+	predicate.IsNotNull:            predicate.IsNull,
+	predicate.IsTrue:               predicate.IsFalse,
+	predicate.IsFalse:              predicate.IsTrue,
+	predicate.Between:              predicate.NotBetween,
+	predicate.NotBetween:           predicate.Between,
+	predicate.Like:                 predicate.NotLike,
+	predicate.NotLike:              predicate.Like,
+}
+
+// negateCondition gives the opposite of a condition, if there is a simple one.
+func negateCondition(c Condition) (Condition, bool) {
+	if c.Column == "" && len(c.Args) == 0 {
+		switch c.Predicate {
+		case alwaysTrue:
+			c.Predicate = alwaysFalse
+			return c, true
+		case alwaysFalse:
+			c.Predicate = alwaysTrue
+			return c, true
+		}
+		return c, false
+	}
+
+	if opp, ok := oppositePredicates[c.Predicate]; ok {
+		c.Predicate = opp
+		return c, true
+	}
+
+	switch {
+	case strings.HasPrefix(c.Predicate, " IN ("):
+		c.Predicate = " NOT" + c.Predicate
+		return c, true
+	case strings.HasPrefix(c.Predicate, " NOT IN ("):
+		c.Predicate = c.Predicate[4:]
+		return c, true
+	}
+
+	return c, false
+}
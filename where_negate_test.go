@@ -0,0 +1,36 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+)
+
+func TestPushNot(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		wh  where.Node
+		exp string
+	}{
+		{wh: where.Not(where.And(where.Eq("a", 1), where.Gt("b", 2))), exp: `a<>1 OR b<=2`},
+		{wh: where.Not(where.Or(where.GtEq("a", 1), where.Lt("b", 2))), exp: `a<1 AND b>=2`},
+		{wh: where.Not(where.Or(where.Null("a"), where.In("b", 1, 2))), exp: `a IS NOT NULL AND b NOT IN (1,2)`},
+		{wh: where.Not(where.And(where.Like("a", "x%"), where.Between("b", 1, 5))), exp: `a NOT LIKE 'x%' OR b NOT BETWEEN 1 AND 5`},
+		{wh: where.Not(where.And(where.NotIn("a", 1, 2), where.IsTrue("b"), where.True())), exp: `a IN (1,2) OR b=FALSE OR FALSE`},
+		{wh: where.Not(where.Not(nameIsFred)), exp: `name='Fred'`},
+		{wh: where.Not(where.Or(nameIsFred, where.Not(ageGt5Int))), exp: `name<>'Fred' AND age>5`},
+		{wh: where.Not(where.Regexp("a", "^x")), exp: `NOT a REGEXP '^x'`},
+		{wh: where.And(nameIsFred, where.Not(where.And(ageGt5Int, where.Or(where.Eq("c", 1), where.Eq("d", 2))))), exp: `name='Fred' AND (age<=5 OR (c<>1 AND d<>2))`},
+		{wh: where.Not(where.Wrap(pair{a: nameIsFred, b: where.Not(where.Not(ageGt5Int))})), exp: `NOT (name='Fred' OR age>5)`},
+		{wh: where.Not(where.CountGt("x", 1)), exp: `COUNT(x)<=1`},
+	}
+
+	for _, c := range cases {
+		g.Expect(where.PushNot(c.wh).String()).To(Equal(c.exp), c.wh.String())
+	}
+
+	g.Expect(where.PushNot(nil)).To(BeNil())
+	g.Expect(where.PushNot(where.NoOp()).String()).To(BeEmpty())
+}
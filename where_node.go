@@ -0,0 +1,205 @@
+package where
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// Composite is implemented by custom nodes that contain other nodes. This allows the
+// functions that traverse or rebuild expression trees, such as Walk, to reach inside them.
+type Composite interface {
+	Node
+
+	// Children returns the nodes contained directly within this node.
+	Children() []Node
+
+	// WithChildren returns a copy of this node with its children replaced. The new
+	// children are in the same order as those returned by Children, although some
+	// may have been altered.
+	WithChildren(children []Node) Node
+}
+
+// Validator is implemented by custom nodes that can check their own correctness.
+// Validate returns nil if the node is valid.
+type Validator interface {
+	Validate() error
+}
+
+// ResolveConfig gives the configuration that results from applying format options to
+// the package defaults (see SetDefaults), with the dialect and quoter always set. Custom
+// nodes can use this in their Format methods to render SQL that depends on the dialect.
+func ResolveConfig(option ...dialect.FormatOption) dialect.FormatConfig {
+	return newConfig(dialect.FormatConfig{}, option).FormatConfig
+}
+
+// QuoteColumn quotes a column name as required by the format options, prefixing it with
+// the table alias if it is unqualified (see WithTableAlias). Custom nodes can use this to
+// render their columns in the same way as Condition does.
+func QuoteColumn(column string, option ...dialect.FormatOption) string {
+	return newConfig(dialect.FormatConfig{}, option).quotedColumn(column)
+}
+
+var registry = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	byName: make(map[string]reflect.Type),
+	byType: make(map[reflect.Type]string),
+}
+
+// RegisterNode records a custom node type under a unique name, which identifies the
+// type wherever expressions are stored or transmitted. The prototype is a value of the
+// node type; its content is ignored. If the type implements Composite or Validator,
+// it takes part in tree traversal and validation respectively.
+//
+// RegisterNode is typically called from an init function. It panics if the name or the
+// type is registered twice.
+func RegisterNode(name string, prototype Node) {
+	if prototype == nil {
+		panic("where: RegisterNode prototype is nil")
+	}
+
+	t := reflect.TypeOf(prototype)
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, exists := registry.byName[name]; exists {
+		panic(fmt.Sprintf("where: RegisterNode called twice for name %q", name))
+	}
+	if _, exists := registry.byType[t]; exists {
+		panic(fmt.Sprintf("where: RegisterNode called twice for type %v", t))
+	}
+
+	registry.byName[name] = t
+	registry.byType[t] = name
+}
+
+// KindOf names the kind of a node: "condition", "and", "or" or "not" for the nodes
+// provided by this package, or the registered name for custom nodes (see RegisterNode).
+// An empty clause (see NoOp) is "noop". Labelled nodes (see Label) have the kind of the
+// node they contain. The result is blank for unregistered nodes.
+func KindOf(node Node) string {
+	switch n := node.(type) {
+	case Condition:
+		return "condition"
+	case Clause:
+		switch {
+		case len(n.wheres) == 0:
+			return "noop"
+		case n.conjunction == or:
+			return "or"
+		}
+		return "and"
+	case not:
+		return "not"
+	case wrapper:
+		return KindOf(n.node)
+	case labelled:
+		return KindOf(n.node)
+	}
+
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.byType[reflect.TypeOf(node)]
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Walk traverses an expression tree in depth-first order, calling fn for each node.
+// If fn returns false, the children of that node are not visited.
+//
+// Nodes adapted by Wrap are visited directly, i.e. without the adapter. The children
+// of custom nodes are visited if they implement Composite.
+func Walk(node Node, fn func(Node) bool) {
+	if node == nil {
+		return
+	}
+
+	if w, isWrapper := node.(wrapper); isWrapper {
+		node = w.node
+	}
+
+	if fn(node) {
+		for _, child := range children(node) {
+			Walk(child, fn)
+		}
+	}
+}
+
+// children gets the nodes contained directly within a node.
+func children(node Node) []Node {
+	switch n := node.(type) {
+	case Clause:
+		return n.wheres
+	case not:
+		return []Node{n.expression}
+	case wrapper:
+		return children(n.node)
+	case Composite:
+		return n.Children()
+	}
+	return nil
+}
+
+// Conjunction gets "AND" or "OR", whichever joins the expressions in the clause.
+func (exp Clause) Conjunction() string {
+	if exp.conjunction == or {
+		return "OR"
+	}
+	return "AND"
+}
+
+// Terms returns the expressions within the clause. The result is a copy, so altering
+// it does not alter the clause.
+func (exp Clause) Terms() []Node {
+	return append([]Node(nil), exp.wheres...)
+}
+
+// Negated gets the operand of a node created by Not, if node is one.
+func Negated(node Node) (operand Node, ok bool) {
+	if w, isWrapper := node.(wrapper); isWrapper {
+		node = w.node
+	}
+	n, ok := node.(not)
+	return n.expression, ok
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Visitor receives the nodes of an expression tree from Visit, according to their kind.
+// The methods that return bool control whether the nodes within are then visited.
+type Visitor interface {
+	// VisitCondition receives each condition.
+	VisitCondition(c Condition)
+
+	// VisitClause receives each 'AND' or 'OR' clause, including empty ones (see NoOp).
+	VisitClause(c Clause) bool
+
+	// VisitNot receives the operand of each node created by Not.
+	VisitNot(operand Node) bool
+
+	// VisitOther receives all other nodes, such as custom nodes.
+	VisitOther(node Node) bool
+}
+
+// Visit traverses an expression tree in depth-first order, as for Walk, passing each
+// node to the corresponding method of the visitor.
+func Visit(node Node, v Visitor) {
+	Walk(node, func(n Node) bool {
+		switch x := n.(type) {
+		case Condition:
+			v.VisitCondition(x)
+			return false
+		case Clause:
+			return v.VisitClause(x)
+		case not:
+			return v.VisitNot(x.expression)
+		}
+		return v.VisitOther(n)
+	})
+}
@@ -0,0 +1,147 @@
+package where_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// tagsContain is a custom node type, as might be provided by another package.
+type tagsContain []any
+
+func (n tagsContain) Format(option ...dialect.FormatOption) (string, []any) {
+	return where.Literal("tags", " @> ARRAY[?,?]", n...).Format(option...)
+}
+
+func (n tagsContain) String() string {
+	sql, _ := n.Format(dialect.NoQuotes, dialect.Inline)
+	return sql
+}
+
+func TestForeignNode(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	custom := tagsContain{"a", "b"}
+
+	sql, args := where.Where(where.And(nameIsFred, custom, ageGt5Int), dialect.ANSIQuotes, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE "name"=$1 AND ("tags" @> ARRAY[$2,$3]) AND "age">$4`))
+	g.Expect(args).To(Equal([]any{"Fred", "a", "b", 5}))
+
+	sql, args = where.Having(where.Not(custom), dialect.AtP)
+	g.Expect(sql).To(Equal(` HAVING NOT (tags @> ARRAY[@p1,@p2])`))
+	g.Expect(args).To(Equal([]any{"a", "b"}))
+
+	sql, args = where.Where(custom, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE tags @> ARRAY[$1,$2]`))
+	g.Expect(args).To(Equal([]any{"a", "b"}))
+
+	// fluent style via the adapter
+	wh := where.Wrap(custom).Or(nameIsJohn)
+	g.Expect(wh.String()).To(Equal(`(tags @> ARRAY['a','b']) OR name='John'`))
+	g.Expect(where.Wrap(nameIsJohn)).To(Equal(nameIsJohn))
+}
+
+// pair is a custom composite node.
+type pair struct {
+	a, b where.Node
+}
+
+func (p pair) Format(option ...dialect.FormatOption) (string, []any) {
+	return where.Or(p.a, p.b).Format(option...)
+}
+
+func (p pair) String() string { return where.Or(p.a, p.b).String() }
+
+func (p pair) Children() []where.Node { return []where.Node{p.a, p.b} }
+
+func (p pair) WithChildren(c []where.Node) where.Node { return pair{a: c[0], b: c[1]} }
+
+func init() {
+	where.RegisterNode("pair", pair{})
+}
+
+func TestWalk(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(nameIsFred, where.Not(where.Wrap(pair{a: ageGt5Int, b: tagsContain{1, 2}})))
+
+	var kinds []string
+	where.Walk(wh, func(n where.Node) bool {
+		kinds = append(kinds, where.KindOf(n))
+		return true
+	})
+	g.Expect(kinds).To(Equal([]string{"and", "condition", "not", "pair", "condition", ""}))
+
+	kinds = nil
+	where.Walk(wh, func(n where.Node) bool {
+		kinds = append(kinds, where.KindOf(n))
+		return where.KindOf(n) != "not"
+	})
+	g.Expect(kinds).To(Equal([]string{"and", "condition", "not"}))
+
+	g.Expect(where.KindOf(where.NoOp())).To(Equal("noop"))
+	g.Expect(where.KindOf(nameIsFred.Or(nameIsJohn))).To(Equal("or"))
+}
+
+// columnCounter counts the conditions on each column, except those that are negated.
+type columnCounter struct {
+	counts  map[string]int
+	clauses []string
+	others  int
+}
+
+func (v *columnCounter) VisitCondition(c where.Condition) { v.counts[c.Column]++ }
+
+func (v *columnCounter) VisitClause(c where.Clause) bool {
+	v.clauses = append(v.clauses, fmt.Sprintf("%s/%d", c.Conjunction(), len(c.Terms())))
+	return true
+}
+
+func (v *columnCounter) VisitNot(where.Node) bool { return false }
+
+func (v *columnCounter) VisitOther(where.Node) bool {
+	v.others++
+	return true
+}
+
+func TestVisit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(nameIsFred, where.Or(ageGt5Int, nameIsJohn), where.Not(where.Eq("age", 1)),
+		where.Wrap(pair{a: ageGt5Int, b: tagsContain{1, 2}}))
+
+	v := &columnCounter{counts: map[string]int{}}
+	where.Visit(wh, v)
+	g.Expect(v.counts).To(Equal(map[string]int{"name": 2, "age": 2}))
+	g.Expect(v.clauses).To(Equal([]string{"AND/4", "OR/2"}))
+	g.Expect(v.others).To(Equal(2))
+
+	operand, ok := where.Negated(where.Not(nameIsFred))
+	g.Expect(ok).To(BeTrue())
+	g.Expect(operand).To(Equal(nameIsFred))
+
+	_, ok = where.Negated(nameIsFred)
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestRegisterNode_twice(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(func() { where.RegisterNode("pair", tagsContain{}) }).To(Panic())
+	g.Expect(func() { where.RegisterNode("other", pair{}) }).To(Panic())
+}
+
+func TestResolveConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fc := where.ResolveConfig(dialect.Postgres, dialect.Dollar)
+	g.Expect(fc.Dialect).To(Equal(dialect.Postgres))
+	g.Expect(fc.Placeholder).To(Equal(dialect.Dollar))
+	g.Expect(fc.Quoter).NotTo(BeNil())
+
+	g.Expect(where.QuoteColumn("age", dialect.ANSIQuotes, where.WithTableAlias("p"))).To(Equal(`"p"."age"`))
+	g.Expect(where.QuoteColumn("q.age", dialect.NoQuotes, where.WithTableAlias("p"))).To(Equal(`q.age`))
+}
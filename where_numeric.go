@@ -0,0 +1,123 @@
+package where
+
+import (
+	"database/sql/driver"
+	"math/big"
+	"strings"
+)
+
+// DecimalValue is implemented by arbitrary-precision decimal types, such as
+// github.com/shopspring/decimal.Decimal, whose value is the coefficient × 10^exponent.
+// When such values are inlined (see dialect.Inline), they are rendered exactly as SQL
+// numeric literals, i.e. with '.' as the decimal separator and no exponent.
+type DecimalValue interface {
+	Coefficient() *big.Int
+	Exponent() int32
+}
+
+// maxRatDigits limits the decimal places of big.Rat values that have no exact decimal form.
+const maxRatDigits = 38
+
+// numericLiteral renders arbitrary-precision numbers as SQL numeric literals. The result
+// is false for other values.
+func numericLiteral(v any) (string, bool) {
+	switch x := v.(type) {
+	case DecimalValue:
+		return decimalLiteral(x.Coefficient(), int(x.Exponent())), true
+	case *big.Int:
+		if x != nil {
+			return x.String(), true
+		}
+	case *big.Float:
+		if x != nil && !x.IsInf() {
+			return x.Text('f', -1), true
+		}
+	case *big.Rat:
+		if x != nil {
+			return ratLiteral(x), true
+		}
+	}
+	return "", false
+}
+
+// decimalLiteral renders coefficient × 10^exponent without using an exponent.
+func decimalLiteral(coefficient *big.Int, exponent int) string {
+	if coefficient == nil {
+		coefficient = new(big.Int)
+	}
+
+	digits := new(big.Int).Abs(coefficient).String()
+	sign := ""
+	if coefficient.Sign() < 0 {
+		sign = "-"
+	}
+
+	if exponent >= 0 {
+		if coefficient.Sign() == 0 {
+			return "0"
+		}
+		return sign + digits + strings.Repeat("0", exponent)
+	}
+
+	places := -exponent
+	if len(digits) <= places {
+		digits = strings.Repeat("0", places-len(digits)+1) + digits
+	}
+	point := len(digits) - places
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// ratLiteral renders a rational number exactly if it has a finite decimal form, or
+// otherwise rounded to maxRatDigits decimal places.
+func ratLiteral(x *big.Rat) string {
+	if x.IsInt() {
+		return x.Num().String()
+	}
+
+	// a finite decimal form exists only if the denominator has no prime factors other than 2 and 5
+	places := maxRatDigits
+	d := new(big.Int).Set(x.Denom())
+	twos, fives := 0, 0
+	for d.Bit(0) == 0 {
+		d.Rsh(d, 1)
+		twos++
+	}
+	five := big.NewInt(5)
+	m := new(big.Int)
+	for {
+		q, r := new(big.Int).QuoRem(d, five, m)
+		if r.Sign() != 0 {
+			break
+		}
+		d = q
+		fives++
+	}
+	if d.Cmp(big.NewInt(1)) == 0 {
+		places = max(twos, fives)
+	}
+
+	s := x.FloatString(places)
+	if places == maxRatDigits {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// valuerLiteral renders a driver.Valuer using the value it gives.
+func valuerLiteral(v any) (string, bool) {
+	valuer, ok := v.(driver.Valuer)
+	if !ok {
+		return "", false
+	}
+	value, err := valuer.Value()
+	if err != nil {
+		return "", false
+	}
+	if value == nil {
+		return "NULL", true
+	}
+	if _, isValuer := value.(driver.Valuer); isValuer {
+		return "", false // avoid loops
+	}
+	return literalValue(value), true
+}
@@ -0,0 +1,61 @@
+package where_test
+
+import (
+	"database/sql/driver"
+	"math/big"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// decimal resembles github.com/shopspring/decimal.Decimal.
+type decimal struct {
+	value *big.Int
+	exp   int32
+}
+
+func (d decimal) Coefficient() *big.Int { return d.value }
+func (d decimal) Exponent() int32       { return d.exp }
+
+// money is a driver.Valuer.
+type money struct{ pence int64 }
+
+func (m money) Value() (driver.Value, error) { return float64(m.pence) / 100, nil }
+
+func TestInlineNumbers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	bigFloat, _ := new(big.Float).SetPrec(200).SetString("12345678901234567890.125")
+
+	cases := []struct {
+		value any
+		exp   string
+	}{
+		{value: decimal{big.NewInt(12345), -2}, exp: `123.45`},
+		{value: decimal{big.NewInt(-5), -3}, exp: `-0.005`},
+		{value: decimal{big.NewInt(7), 3}, exp: `7000`},
+		{value: decimal{big.NewInt(0), 2}, exp: `0`},
+		{value: decimal{huge, -10}, exp: `12345678901234567890.1234567890`},
+		{value: huge, exp: `123456789012345678901234567890`},
+		{value: bigFloat, exp: `12345678901234567890.125`},
+		{value: big.NewFloat(1e21), exp: `1000000000000000000000`},
+		{value: big.NewRat(3, 8), exp: `0.375`},
+		{value: big.NewRat(-10, 5), exp: `-2`},
+		{value: big.NewRat(1, 3), exp: `0.33333333333333333333333333333333333333`},
+		{value: money{1999}, exp: `19.99`},
+		{value: 1e21, exp: `1000000000000000000000`},
+	}
+
+	for _, c := range cases {
+		s, args := where.Where(where.Eq("x", c.value), dialect.Inline)
+		g.Expect(s).To(Equal(` WHERE x=`+c.exp), c.exp)
+		g.Expect(args).To(BeNil())
+	}
+
+	s, err := where.IndexWhere(where.Gt("x", decimal{big.NewInt(15), -1}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(Equal(` WHERE x>1.5`))
+}
@@ -0,0 +1,107 @@
+package where
+
+import (
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/quote"
+)
+
+// optionFunc adapts a function to be a format option.
+type optionFunc func(config *dialect.FormatConfig)
+
+func (fn optionFunc) Apply(config *dialect.FormatConfig) {
+	fn(config)
+}
+
+// WithDialect returns a format option that selects the dialect used for rendering
+// that differs between databases. This is like using the dialect itself as an option,
+// except that it overrides any dialect given earlier, whereas the first of several
+// dialect values takes precedence.
+func WithDialect(d dialect.Dialect) dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.Dialect = d
+	})
+}
+
+// WithQuoter returns a format option that quotes identifiers using the quoter, which
+// need not be one of those provided by the quote package. It overrides any quoting
+// flag given earlier.
+func WithQuoter(q quote.Quoter) dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.Quoter = q
+	})
+}
+
+// WithPlaceholder returns a format option that renders placeholders using
+// dialect.Query, dialect.Dollar, dialect.AtP, dialect.Inline or dialect.Named. It
+// overrides any placeholder flag given earlier.
+func WithPlaceholder(p dialect.Flag) dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.Placeholder = p
+	})
+}
+
+// WithPlaceholderOffset returns a format option that starts the numbered placeholders
+// after the n already used by a base query, e.g.
+//
+//	query := "SELECT * FROM orders WHERE customer_id = $1"
+//	wh, args := where.Where(filter, dialect.Dollar, where.WithPlaceholderOffset(1))
+//	query += strings.Replace(wh, " WHERE ", " AND ", 1) // filter uses $2 onwards
+func WithPlaceholderOffset(n int) dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.PlaceholderOffset = n
+	})
+}
+
+// WithTableAlias returns a format option that prefixes every unqualified column in an
+// expression with a table alias, e.g. "age" becomes "t"."age" when quoted. This allows
+// the same expression to be reused within different joins and sub-queries.
+func WithTableAlias(alias string) dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.TableAlias = alias
+	})
+}
+
+// WithInValues returns a format option that renders IN lists with at least n values as
+// "IN (VALUES (?),(?),...)" for Postgres; see dialect.FormatConfig.InValues.
+func WithInValues(n int) dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.InValues = n
+	})
+}
+
+// WithInArray returns a format option that renders IN lists as "= ANY(?)" with a single
+// array argument for Postgres; see dialect.FormatConfig.InArray.
+func WithInArray() dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.InArray = true
+	})
+}
+
+// WithKeywordCase returns a format option that renders the SQL keywords in the given
+// letter case.
+func WithKeywordCase(kc dialect.KeywordCase) dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.KeywordCase = kc
+	})
+}
+
+// WithKeywords returns a format option that renders the generated SQL keywords using
+// replacements, keyed by the upper-case keyword, e.g.
+//
+//	where.WithKeywords(map[string]string{"AND": "&&", "OR": "||"})
+//
+// Keywords that are not in the map are rendered as usual. See dialect.FormatConfig.Keywords.
+func WithKeywords(replacements map[string]string) dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.Keywords = replacements
+	})
+}
+
+// WithSpacing returns a format option that controls the whitespace around operators
+// and after commas in predicates, e.g. dialect.Spaced gives "name = ?" instead of "name=?".
+// This allows generated SQL to follow a house style.
+func WithSpacing(spacing dialect.Spacing) dialect.FormatOption {
+	return optionFunc(func(config *dialect.FormatConfig) {
+		config.Spacing = spacing
+	})
+}
@@ -0,0 +1,135 @@
+package where_test
+
+import (
+	"io"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/quote"
+)
+
+type angleQuoter struct{}
+
+func (angleQuoter) Quote(identifier string) string { return "<" + identifier + ">" }
+
+func (angleQuoter) QuoteW(w io.StringWriter, identifier string) {
+	_, _ = w.WriteString("<" + identifier + ">")
+}
+
+func TestWithOptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(where.Not(where.In("a", 1, nil)), where.Like("b", "X%"), tagsContain{"IN", "x"})
+
+	sql, args := where.Where(wh,
+		where.WithDialect(dialect.Postgres),
+		where.WithQuoter(angleQuoter{}),
+		where.WithPlaceholder(dialect.Dollar),
+		where.WithKeywordCase(dialect.LowerCase))
+	g.Expect(sql).To(Equal(` where (not (<a> in ($1) or <a> is null)) and <b> like $2 and (<tags> @> ARRAY[$3,$4])`))
+	g.Expect(args).To(Equal([]any{1, "X%", "IN", "x"}))
+
+	// quoted strings are not altered
+	sql, _ = where.Having(where.Literal("c", " IS NOT 'NULL'"), where.WithKeywordCase(dialect.LowerCase))
+	g.Expect(sql).To(Equal(` having c is not 'NULL'`))
+
+	// the first flag of each kind takes precedence
+	sql, _ = where.Where(where.Eq("a", 1), dialect.Dollar, dialect.Query, dialect.ANSIQuotes, dialect.Backticks)
+	g.Expect(sql).To(Equal(` WHERE "a"=$1`))
+
+	// but later options with values override earlier ones
+	sql, _ = where.Where(nameIsFred, dialect.ANSIQuotes, where.WithQuoter(quote.None), dialect.Dollar, where.WithPlaceholder(dialect.AtP))
+	g.Expect(sql).To(Equal(` WHERE name=@p1`))
+
+	// the placeholders alone do not select the dialect
+	sql, _ = where.Where(where.ILike("a", "x"), dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE LOWER(a) LIKE LOWER($1)`))
+
+	sql, _ = where.Where(where.ILike("a", "x"), dialect.ConfigFor(dialect.Postgres))
+	g.Expect(sql).To(Equal(` WHERE "a" ILIKE $1`))
+
+	sql, _ = where.Where(where.ILike("a", "x"), dialect.Sqlite, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE LOWER(a) LIKE LOWER($1)`))
+
+	sql, _ = where.Where(where.ILike("a", "x"), dialect.Sqlite, where.WithDialect(dialect.Postgres), dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE a ILIKE $1`))
+}
+
+func TestQueryConstraint_WithKeywordCase(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	qc := where.OrderBy("foo").Desc().NullsLast().Limit(10).Offset(20)
+	s := qc.Format(dialect.Postgres, where.WithKeywordCase(dialect.LowerCase), where.WithQuoter(angleQuoter{}))
+	g.Expect(s).To(Equal(` order by <foo> desc nulls last limit 10 offset 20`))
+}
+
+func TestWithKeywords(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	keywords := where.WithKeywords(map[string]string{"WHERE": "FILTER", "AND": "&&", "OR": "||", "NOT": "!", "ORDER BY": "SORT BY"})
+	wh := where.And(where.Not(nameIsFred), where.Or(ageGt5Int, where.Null("b")))
+
+	sql, _ := where.Where(wh, keywords, where.WithKeywordCase(dialect.LowerCase))
+	g.Expect(sql).To(Equal(` FILTER (! name=?) && (age>? || b is null)`))
+
+	sql, _ = where.Having(wh, keywords)
+	g.Expect(sql).To(Equal(` HAVING (! name=?) && (age>? || b IS NULL)`))
+
+	s := where.OrderBy("foo").Desc().Limit(5).Format(dialect.Sqlite, keywords)
+	g.Expect(s).To(Equal(` SORT BY foo DESC LIMIT 5`))
+}
+
+func TestWithPlaceholderOffset(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(nameIsFred, where.In("age", 1, 2))
+
+	sql, args := where.Where(wh, dialect.Dollar, where.WithPlaceholderOffset(2))
+	g.Expect(sql).To(Equal(` WHERE name=$3 AND age IN ($4,$5)`))
+	g.Expect(args).To(Equal([]any{"Fred", 1, 2}))
+
+	sql, _ = wh.Format(where.WithPlaceholderOffset(10), dialect.AtP)
+	g.Expect(sql).To(Equal(`name=@p11 AND age IN (@p12,@p13)`))
+
+	// no effect on query placeholders
+	sql, _ = where.Having(wh, where.WithPlaceholderOffset(1))
+	g.Expect(sql).To(Equal(` HAVING name=? AND age IN (?,?)`))
+}
+
+func TestWithSpacing(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(nameIsFred, where.In("age", 1, 2), where.Literal("a", " <> 'x=y,z'"), tagsContain{1, 2},
+		where.Literal("b", "<=>?", 3), where.Literal("c", " BETWEEN ? AND ?", 4, 5), where.Literal("d", " ~ ?", 6))
+
+	sql, _ := where.Where(wh, where.WithSpacing(dialect.Spaced), dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE name = $1 AND age IN ($2, $3) AND a <> 'x=y,z' AND (tags @> ARRAY[$4, $5]) AND b <=> $6 AND c BETWEEN $7 AND $8 AND d ~ $9`))
+
+	sql, _ = where.Where(wh, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE name=$1 AND age IN ($2,$3) AND a <> 'x=y,z' AND (tags @> ARRAY[$4,$5]) AND b<=>$6 AND c BETWEEN $7 AND $8 AND d ~ $9`))
+}
+
+func TestWithTableAlias(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.And(nameIsFred, where.Eq("u.id", 1), where.CountGt("*", 2), where.Predicate("EXISTS (SELECT 1)"), tagsContain{1, 2})
+
+	sql, args := where.Where(wh, where.WithTableAlias("t"), dialect.ANSIQuotes)
+	g.Expect(sql).To(Equal(` WHERE "t"."name"=? AND "u"."id"=? AND COUNT(*)>? AND EXISTS (SELECT 1) AND ("t"."tags" @> ARRAY[?,?])`))
+	g.Expect(args).To(Equal([]any{"Fred", 1, 2, 1, 2}))
+
+	sql, _ = where.AggregateFilter("SUM", "amount", ageGt5Int).Format(where.WithTableAlias("o"), dialect.Mysql)
+	g.Expect(sql).To(Equal(`SUM(CASE WHEN o.age>? THEN o.amount END)`))
+}
+
+func TestFormatConfig_asOption(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// only the non-zero fields of a configuration are applied
+	sql, _ := where.Where(nameIsFred,
+		dialect.ANSIQuotes, where.WithTableAlias("t"), where.WithKeywordCase(dialect.LowerCase),
+		dialect.FormatConfig{Placeholder: dialect.Dollar})
+	g.Expect(sql).To(Equal(` where "t"."name"=$1`))
+}
@@ -0,0 +1,568 @@
+package where
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var comparison = regexp.MustCompile(`^\s*([\pL_][\pL\pN_]*(?:\.[\pL_][\pL\pN_]*)*)\s*(==|=|!=|<>|>=|<=|>|<|~)\s*(.*?)\s*$`)
+
+// decimal matches the numbers that are inferred as float64; unlike strconv.ParseFloat, it
+// does not accept "nan", "inf" or hexadecimal forms, which are taken as strings instead.
+var decimal = regexp.MustCompile(`^[-+]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+)(?:[eE][-+]?[0-9]+)?$`)
+
+var identifier = regexp.MustCompile(`^[\pL_][\pL\pN_]*(?:\.[\pL_][\pL\pN_]*)*$`)
+
+// Parse converts a filter, such as "name = 'Fred' AND (age > 10 OR city IN ('X','Y'))", into
+// an expression. The values are bound as arguments, so they cannot alter the structure of
+// the SQL. This is handy for filters given on command lines, in configuration files or in
+// admin user interfaces.
+//
+// Each comparison has a column on the left, which must be an identifier, optionally with a
+// prefix, e.g. "u.name". The comparisons are
+//   - the operators =, ==, !=, <>, >, >=, <, <= and ~ (for LIKE), followed by a value;
+//   - IN (...) and NOT IN (...), with a list of values separated by commas;
+//   - BETWEEN a AND b and NOT BETWEEN a AND b;
+//   - LIKE, NOT LIKE and ILIKE, followed by a pattern;
+//   - IS NULL and IS NOT NULL.
+//
+// Comparisons are combined using AND, OR, NOT and parentheses, with the usual SQL
+// precedence. Keywords are not case-sensitive.
+//
+// The type of each value is inferred:
+//   - integers and decimal numbers become int64 and float64 respectively;
+//   - true and false become bool;
+//   - null becomes "IS NULL" (or "IS NOT NULL" for != and <>); in a list of values,
+//     it adds "OR column IS NULL" (see In);
+//   - anything else is a string; it may be enclosed in single or double quotes, which
+//     are removed, e.g. to preserve spaces or to treat 10 as a string. Within quotes,
+//     a doubled quote mark stands for one quote mark.
+//
+// A filter that cannot be parsed in this way may instead be a single comparison whose
+// unquoted value contains spaces or punctuation, e.g. "title=War and Peace" or
+// "note=see (draft)". The whole of the rest of the filter is then the value. It must not
+// begin with a quote mark, contain any of the operators, or begin or end with AND, OR or
+// NOT, so that mistakes in compound filters are still reported.
+func Parse(s string) (Expression, error) {
+	exp, err := parseFilter(s)
+	if err == nil {
+		return exp, nil
+	}
+
+	// a single comparison whose value is not quoted
+	m := comparison.FindStringSubmatch(s)
+	if m == nil || !isPlainValue(m[3]) {
+		return nil, err
+	}
+
+	column, op, text := m[1], m[2], m[3]
+	value, isNull := inferValue(text)
+	return compare(s, column, op, value, isNull)
+}
+
+// compare builds the condition for a column, an operator and a value.
+func compare(s, column, op string, value any, isNull bool) (Expression, error) {
+	if isNull {
+		switch op {
+		case "=", "==":
+			return Null(column), nil
+		case "!=", "<>":
+			return NotNull(column), nil
+		}
+		return nil, fmt.Errorf("where: cannot parse %q; null can only be used with =, ==, != or <>", s)
+	}
+
+	switch op {
+	case "=", "==":
+		return Eq(column, value), nil
+	case "!=", "<>":
+		return NotEq(column, value), nil
+	case ">":
+		return Gt(column, value), nil
+	case ">=":
+		return GtEq(column, value), nil
+	case "<":
+		return Lt(column, value), nil
+	case "<=":
+		return LtEq(column, value), nil
+	}
+	return Like(column, fmt.Sprint(value)), nil // ~
+}
+
+// ParseAll parses each filter (see Parse) and combines them in an 'AND' clause.
+// The first error encountered is returned.
+func ParseAll(s ...string) (Expression, error) {
+	conditions := make([]Node, 0, len(s))
+	for _, c := range s {
+		exp, err := Parse(c)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, exp)
+	}
+	return And(conditions...), nil
+}
+
+// inferValue converts the text of an unquoted value to its inferred type.
+func inferValue(text string) (value any, isNull bool) {
+	switch strings.ToLower(text) {
+	case "null":
+		return nil, true
+	case "true":
+		return true, false
+	case "false":
+		return false, false
+	}
+
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return i, false
+	}
+	if decimal.MatchString(text) {
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f, false
+		}
+	}
+	return text, false
+}
+
+// isPlainValue tests whether the text can be the unquoted value of a single comparison
+// (see Parse).
+func isPlainValue(text string) bool {
+	if text == "" {
+		return true
+	}
+	if text[0] == '\'' || text[0] == '"' || strings.ContainsAny(text, "=!<>~") {
+		return false
+	}
+	words := strings.Fields(text)
+	for _, w := range []string{words[0], words[len(words)-1]} {
+		switch strings.ToUpper(w) {
+		case "AND", "OR", "NOT":
+			return false
+		}
+	}
+	return true
+}
+
+//-------------------------------------------------------------------------------------------------
+
+type filterTokenKind int
+
+const (
+	endOfFilter      filterTokenKind = iota
+	wordToken                        // a column, keyword or unquoted value
+	stringToken                      // a quoted value, without its quotes
+	punctToken                       // an operator, parenthesis or comma
+	identToken                       // a quoted identifier, without its quotes (SQL only)
+	placeholderToken                 // a '?' placeholder (SQL only)
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+}
+
+// filterPunctuation lists the operators and delimiters, longest first.
+var filterPunctuation = []string{"==", "!=", "<>", ">=", "<=", "=", ">", "<", "~", "(", ")", ","}
+
+// isWordRune tests whether a rune can be part of a word, i.e. it is not a space, a quote
+// mark or the start of any punctuation.
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`'"()=!<>~,`, r)
+}
+
+// lexFilter splits a filter into tokens. For SQL, only single quotes enclose strings;
+// double quotes, backticks and square brackets enclose identifiers instead.
+func lexFilter(s string, sql bool) ([]filterToken, error) {
+	var tokens []filterToken
+	i := 0
+
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case sql && r == '?':
+			tokens = append(tokens, filterToken{kind: placeholderToken, text: "?"})
+			i++
+
+		case sql && (r == '"' || r == '`' || r == '['):
+			text, end, ok := lexQuoted(s, i)
+			if !ok {
+				return nil, fmt.Errorf("where: cannot parse %q; unterminated identifier", s)
+			}
+			tokens = append(tokens, filterToken{kind: identToken, text: text})
+			i = end
+
+		case r == '\'' || r == '"':
+			text, end, ok := lexQuoted(s, i)
+			if !ok {
+				return nil, fmt.Errorf("where: cannot parse %q; unterminated string", s)
+			}
+			tokens = append(tokens, filterToken{kind: stringToken, text: text})
+			i = end
+
+		case isWordRune(r):
+			start := i
+			for i < len(s) {
+				r, size = utf8.DecodeRuneInString(s[i:])
+				if !isWordRune(r) || sql && r == '?' {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, filterToken{kind: wordToken, text: s[start:i]})
+
+		default:
+			matched := false
+			for _, p := range filterPunctuation {
+				if strings.HasPrefix(s[i:], p) {
+					tokens = append(tokens, filterToken{kind: punctToken, text: p})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("where: cannot parse %q; unexpected %q", s, r)
+			}
+		}
+	}
+
+	return append(tokens, filterToken{kind: endOfFilter}), nil
+}
+
+// lexQuoted reads the quoted string starting at i, returning its text and the index
+// after the closing quote mark. Doubled quote marks within it are escapes.
+func lexQuoted(s string, i int) (string, int, bool) {
+	quote := s[i]
+	if quote == '[' {
+		quote = ']'
+	}
+	buf := &strings.Builder{}
+	for j := i + 1; j < len(s); j++ {
+		if s[j] == quote {
+			if j+1 < len(s) && s[j+1] == quote {
+				buf.WriteByte(quote)
+				j++
+				continue
+			}
+			return buf.String(), j + 1, true
+		}
+		buf.WriteByte(s[j])
+	}
+	return "", 0, false
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// filterParser is a recursive-descent parser for filters. AND has higher precedence than OR,
+// and NOT is higher still.
+type filterParser struct {
+	src    string
+	tokens []filterToken
+	i      int
+	sql    bool  // parsing SQL rather than a filter
+	args   []any // the arguments for the placeholders (SQL only)
+	used   int   // the number of arguments used so far
+}
+
+func parseFilter(s string) (Expression, error) {
+	tokens, err := lexFilter(s, false)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{src: s, tokens: tokens}
+	return p.parse()
+}
+
+func (p *filterParser) parse() (Expression, error) {
+	exp, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != endOfFilter {
+		return nil, p.unexpected()
+	}
+	return exp, nil
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.i]
+}
+
+func (p *filterParser) next() filterToken {
+	t := p.tokens[p.i]
+	if t.kind != endOfFilter {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the punctuation given.
+func (p *filterParser) accept(punct string) bool {
+	if t := p.peek(); t.kind == punctToken && t.text == punct {
+		p.i++
+		return true
+	}
+	return false
+}
+
+// acceptKeyword consumes the next token if it is the keyword given, in any case.
+func (p *filterParser) acceptKeyword(keyword string) bool {
+	if t := p.peek(); t.kind == wordToken && strings.EqualFold(t.text, keyword) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) errorf(format string, a ...any) error {
+	return fmt.Errorf("where: cannot parse %q; "+format, append([]any{p.src}, a...)...)
+}
+
+func (p *filterParser) unexpected() error {
+	t := p.peek()
+	if t.kind == endOfFilter {
+		return p.errorf("unexpected end")
+	}
+	return p.errorf("unexpected %q", t.text)
+}
+
+func (p *filterParser) or() (Expression, error) {
+	return p.conjunction("OR", Or, p.and)
+}
+
+func (p *filterParser) and() (Expression, error) {
+	return p.conjunction("AND", And, p.unary)
+}
+
+// conjunction parses one or more operands separated by the keyword.
+func (p *filterParser) conjunction(keyword string, combine func(...Node) Expression, operand func() (Expression, error)) (Expression, error) {
+	exp, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := []Node{exp}
+	for p.acceptKeyword(keyword) {
+		exp, err = operand()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, exp)
+	}
+
+	if len(terms) == 1 {
+		return exp, nil
+	}
+	return combine(terms...), nil
+}
+
+func (p *filterParser) unary() (Expression, error) {
+	if p.acceptKeyword("NOT") {
+		exp, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(exp), nil
+	}
+
+	if p.accept("(") {
+		exp, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return exp, nil
+	}
+
+	return p.comparison()
+}
+
+// comparison parses a column followed by an operator and its values.
+func (p *filterParser) comparison() (Expression, error) {
+	t := p.next()
+	isColumn := t.kind == identToken ||
+		t.kind == wordToken && identifier.MatchString(t.text) && !filterKeywords[strings.ToUpper(t.text)]
+	if !isColumn {
+		return nil, p.errorf("expected column, operator and value")
+	}
+	column := t.text
+
+	if t := p.peek(); t.kind == punctToken && t.text != "(" && t.text != ")" && t.text != "," {
+		if p.sql && (t.text == "==" || t.text == "~") {
+			return nil, p.unexpected()
+		}
+		op := p.next().text
+		value, isNull, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		return compare(p.src, column, op, value, isNull)
+	}
+
+	if p.acceptKeyword("IS") {
+		negated := p.acceptKeyword("NOT")
+		if !p.acceptKeyword("NULL") {
+			return nil, p.errorf("expected NULL after IS")
+		}
+		if negated {
+			return NotNull(column), nil
+		}
+		return Null(column), nil
+	}
+
+	if p.acceptKeyword("ILIKE") {
+		pattern, err := p.pattern()
+		if err != nil {
+			return nil, err
+		}
+		return ILike(column, pattern), nil
+	}
+
+	negated := p.acceptKeyword("NOT")
+
+	switch {
+	case p.acceptKeyword("IN"):
+		values, err := p.list()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case p.sql:
+			return sqlList(column, negated, values), nil
+		case negated:
+			return NotIn(column, values...), nil
+		}
+		return In(column, values...), nil
+
+	case p.acceptKeyword("BETWEEN"):
+		a, err := p.nonNullValue()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptKeyword("AND") {
+			return nil, p.errorf("expected AND after BETWEEN")
+		}
+		b, err := p.nonNullValue()
+		if err != nil {
+			return nil, err
+		}
+		if negated {
+			return NotBetween(column, a, b), nil
+		}
+		return Between(column, a, b), nil
+
+	case p.acceptKeyword("LIKE"):
+		pattern, err := p.pattern()
+		if err != nil {
+			return nil, err
+		}
+		if negated {
+			return NotLike(column, pattern), nil
+		}
+		return Like(column, pattern), nil
+	}
+
+	return nil, p.errorf("expected column, operator and value")
+}
+
+// sqlList gives the IN or NOT IN condition for a list in SQL. Unlike In and NotIn, null
+// values are kept in the list, so that the condition means the same as the SQL.
+func sqlList(column string, negated bool, values []any) Expression {
+	predicate := " IN ("
+	if negated {
+		predicate = " NOT IN ("
+	}
+	return Condition{Column: column, Predicate: predicate + strings.Repeat(",?", len(values))[1:] + ")", Args: values}
+}
+
+// list parses a parenthesised list of values separated by commas.
+func (p *filterParser) list() ([]any, error) {
+	if !p.accept("(") {
+		return nil, p.errorf("expected '(' after IN")
+	}
+
+	var values []any
+	for {
+		value, _, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		if p.accept(")") {
+			return values, nil
+		}
+		if !p.accept(",") {
+			return nil, p.errorf("expected ',' or ')' in list")
+		}
+	}
+}
+
+// pattern parses the value of a LIKE comparison, which is always a string.
+func (p *filterParser) pattern() (string, error) {
+	value, err := p.nonNullValue()
+	if err == nil && value == nil {
+		err = p.errorf("a pattern cannot be null")
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(value), nil
+}
+
+func (p *filterParser) nonNullValue() (any, error) {
+	value, isNull, err := p.value()
+	if err == nil && isNull {
+		err = p.errorf("null can only be used with =, ==, != or <>")
+	}
+	return value, err
+}
+
+// value parses a quoted or unquoted value; the type of unquoted values is inferred.
+func (p *filterParser) value() (value any, isNull bool, err error) {
+	t := p.peek()
+	switch {
+	case t.kind == stringToken:
+		p.i++
+		return t.text, false, nil
+
+	case t.kind == placeholderToken:
+		if p.used == len(p.args) {
+			return nil, false, fmt.Errorf("%w: more placeholders than the %d given in %q", ErrArgCount, len(p.args), p.src)
+		}
+		p.i++
+		p.used++
+		return p.args[p.used-1], false, nil
+
+	case t.kind == wordToken && !filterKeywords[strings.ToUpper(t.text)]:
+		value, isNull = inferValue(t.text)
+		if p.sql {
+			if _, isText := value.(string); isText {
+				return nil, false, p.errorf("comparing %s with a column is not supported", t.text)
+			}
+			// NULL is kept as a value, so 'column=NULL' means the same as in the SQL
+			isNull = false
+		}
+		p.i++
+		return value, isNull, nil
+	}
+
+	return nil, false, p.errorf("expected a value")
+}
+
+// filterKeywords are the words that are neither columns nor unquoted values.
+var filterKeywords = map[string]bool{
+	"AND": true, "BETWEEN": true, "ILIKE": true, "IN": true, "IS": true,
+	"LIKE": true, "NOT": true, "OR": true,
+}
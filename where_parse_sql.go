@@ -0,0 +1,50 @@
+package where
+
+import "fmt"
+
+// ParseSQL reconstructs an expression from a hand-written SQL fragment, such as
+// "name=? AND (age>? OR city IN (?,?))", and the arguments for its '?' placeholders.
+// The fragment may start with WHERE or HAVING. The result can then be combined with
+// other expressions, inspected, and formatted for any dialect.
+//
+// The syntax is a subset of SQL: comparisons between a column and values, using the
+// operators =, <>, !=, >, >=, <, <=, [NOT] IN, [NOT] BETWEEN, [NOT] LIKE, ILIKE and
+// IS [NOT] NULL, combined using AND, OR, NOT and parentheses. Columns may be quoted
+// using double quotes, backticks or square brackets. Values are either placeholders or
+// literals; literal strings, numbers, TRUE, FALSE and NULL are all converted to
+// arguments. Null values in lists are kept, unlike In and NotIn, so the expression means
+// the same as the SQL, e.g. "x NOT IN (1, NULL)" matches no rows. Anything else, such as
+// functions, sub-queries and comparisons between columns, is reported as an error.
+//
+// The error wraps ErrArgCount if the number of arguments differs from the number of
+// placeholders.
+func ParseSQL(fragment string, args ...any) (Expression, error) {
+	tokens, err := lexFilter(fragment, true)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{src: fragment, tokens: tokens, sql: true, args: args}
+	_ = p.acceptKeyword("WHERE") || p.acceptKeyword("HAVING")
+
+	exp := NoOp()
+	if p.peek().kind != endOfFilter {
+		exp, err = p.parse()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = p.checkArgs(); err != nil {
+		return nil, err
+	}
+	return exp, nil
+}
+
+// checkArgs verifies that all the arguments have been used.
+func (p *filterParser) checkArgs() error {
+	if p.used < len(p.args) {
+		return fmt.Errorf("%w: %d placeholders but %d arguments in %q", ErrArgCount, p.used, len(p.args), p.src)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestParseSQL(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	exp, err := where.ParseSQL(" WHERE name=? AND (age>? OR city IN (?,?))", "Fred", 10, "X", "Y")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(exp).To(Equal(where.And(where.Eq("name", "Fred"), where.Or(where.Gt("age", 10), where.In("city", "X", "Y")))))
+
+	sql, args := where.Where(exp, dialect.Postgres, dialect.ANSIQuotes, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE "name"=$1 AND ("age">$2 OR "city" IN ($3,$4))`))
+	g.Expect(args).To(Equal([]any{"Fred", 10, "X", "Y"}))
+
+	cases := []struct {
+		sql      string
+		args     []any
+		expected string
+	}{
+		{sql: `"first name" <> 'O''Brien' and [age] between ? and 20`, args: []any{1},
+			expected: `first name<>'O''Brien' AND age BETWEEN 1 AND 20`},
+		{sql: "NOT (`active` = TRUE OR x IS NOT NULL)",
+			expected: `NOT (active=true OR x IS NOT NULL)`},
+		{sql: "u.name NOT LIKE ? AND n NOT IN (1, 2.5)", args: []any{"F%"},
+			expected: `u.name NOT LIKE 'F%' AND n NOT IN (1,2.5)`},
+		{sql: "HAVING n < -1", expected: `n<-1`},
+		{sql: "WHERE ", expected: ``},
+	}
+
+	for _, c := range cases {
+		exp, err := where.ParseSQL(c.sql, c.args...)
+		g.Expect(err).NotTo(HaveOccurred(), c.sql)
+		g.Expect(exp.String()).To(Equal(c.expected), c.sql)
+	}
+
+	// NULL is kept as a value, including in lists
+	exp, err = where.ParseSQL("x = NULL OR y IN (?, NULL)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	sql, args = where.Where(exp)
+	g.Expect(sql).To(Equal(` WHERE x=? OR y IN (?,?)`))
+	g.Expect(args).To(Equal([]any{nil, nil, nil}))
+
+	exp, err = where.ParseSQL("y NOT IN (1, NULL)")
+	g.Expect(err).NotTo(HaveOccurred())
+	sql, args = where.Where(exp)
+	g.Expect(sql).To(Equal(` WHERE y NOT IN (?,?)`))
+	g.Expect(args).To(Equal([]any{int64(1), nil}))
+}
+
+func TestParseSQL_errors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, err := where.ParseSQL("a=? AND b=?", 1)
+	g.Expect(err).To(MatchError(where.ErrArgCount))
+
+	_, err = where.ParseSQL("a=?", 1, 2)
+	g.Expect(err).To(MatchError(where.ErrArgCount))
+	g.Expect(err).To(MatchError(`where: wrong number of arguments: 1 placeholders but 2 arguments in "a=?"`))
+
+	errors := map[string]string{
+		"a.id = b.id":       `where: cannot parse "a.id = b.id"; comparing b.id with a column is not supported`,
+		"LOWER(name) = 'x'": `where: cannot parse "LOWER(name) = 'x'"; expected column, operator and value`,
+		"a == 1":            `where: cannot parse "a == 1"; unexpected "=="`,
+		`"a = 1`:            `where: cannot parse "\"a = 1"; unterminated identifier`,
+		"a LIKE NULL":       `where: cannot parse "a LIKE NULL"; a pattern cannot be null`,
+	}
+
+	for s, expected := range errors {
+		_, err := where.ParseSQL(s)
+		g.Expect(err).To(MatchError(expected), s)
+	}
+}
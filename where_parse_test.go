@@ -0,0 +1,106 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+)
+
+func TestParse(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]where.Expression{
+		"age>=10":          where.GtEq("age", int64(10)),
+		" age > -1.5 ":     where.Gt("age", -1.5),
+		"name=Fred":        where.Eq("name", "Fred"),
+		"name == 'Fred B'": where.Eq("name", "Fred B"),
+		`code="10"`:        where.Eq("code", "10"),
+		"u.active!=true":   where.NotEq("u.active", true),
+		"n<>0":             where.NotEq("n", int64(0)),
+		"n<0":              where.Lt("n", int64(0)),
+		"n<=0":             where.LtEq("n", int64(0)),
+		"name~F%":          where.Like("name", "F%"),
+		"name=null":        where.Null("name"),
+		"name <> NULL":     where.NotNull("name"),
+		"name=":            where.Eq("name", ""),
+		"a = nan":          where.Eq("a", "nan"),
+		"a=-Inf":           where.Eq("a", "-Inf"),
+		"a=0x1p-2":         where.Eq("a", "0x1p-2"),
+		"a=2.5e3":          where.Eq("a", 2500.0),
+	}
+
+	for s, expected := range cases {
+		exp, err := where.Parse(s)
+		g.Expect(err).NotTo(HaveOccurred(), s)
+		g.Expect(exp).To(Equal(expected), s)
+	}
+
+	for _, s := range []string{"", "age", "10>age", "a b=1", "age>null", "name;drop=1"} {
+		_, err := where.Parse(s)
+		g.Expect(err).To(HaveOccurred(), s)
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	exp, err := where.ParseAll("age>=10", "name=Fred")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(exp.String()).To(Equal(`age>=10 AND name='Fred'`))
+
+	_, err = where.ParseAll("age>=10", "name")
+	g.Expect(err).To(MatchError(`where: cannot parse "name"; expected column, operator and value`))
+}
+
+func TestParse_compound(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]string{
+		"name = 'Fred' AND (age > 10 OR city IN ('X','Y'))": `name='Fred' AND (age>10 OR city IN ('X','Y'))`,
+		"a=1 or b=2 and c=3":                                     `a=1 OR (b=2 AND c=3)`,
+		"(a=1 OR b=2) AND c=3":                                   `(a=1 OR b=2) AND c=3`,
+		"NOT (a=1 OR b=2)":                                       `NOT (a=1 OR b=2)`,
+		"age NOT BETWEEN 1 AND 9 AND name LIKE 'F%'":             `age NOT BETWEEN 1 AND 9 AND name LIKE 'F%'`,
+		"name not like F% or name ilike 'j%'":                    `name NOT LIKE 'F%' OR LOWER(name) LIKE LOWER('j%')`,
+		"city NOT IN ('X', 'Y') AND x IS NULL AND y is not null": `city NOT IN ('X','Y') AND x IS NULL AND y IS NOT NULL`,
+		"city IN ('X', null)":                                    `city IN ('X') OR city IS NULL`,
+		"name = 'O''Brien' AND active = true":                    `name='O''Brien' AND active=true`,
+		"name='Fred AND Bob'":                                    `name='Fred AND Bob'`,
+		"name=Fred Bloggs":                                       `name='Fred Bloggs'`,
+		"title=War and Peace":                                    `title='War and Peace'`,
+		"note = see (draft)":                                     `note='see (draft)'`,
+	}
+
+	for s, expected := range cases {
+		exp, err := where.Parse(s)
+		g.Expect(err).NotTo(HaveOccurred(), s)
+		g.Expect(exp.String()).To(Equal(expected), s)
+	}
+
+	exp, err := where.Parse("name = 'Fred' AND (age > 10 OR city IN ('X','Y'))")
+	g.Expect(err).NotTo(HaveOccurred())
+	sql, args := where.Where(exp)
+	g.Expect(sql).To(Equal(` WHERE name=? AND (age>? OR city IN (?,?))`))
+	g.Expect(args).To(Equal([]any{"Fred", int64(10), "X", "Y"}))
+
+	errors := map[string]string{
+		"a=1 AND":                 `where: cannot parse "a=1 AND"; expected column, operator and value`,
+		"(a=1 OR b=2":             `where: cannot parse "(a=1 OR b=2"; expected ')'`,
+		"a=1 OR b=2)":             `where: cannot parse "a=1 OR b=2)"; unexpected ")"`,
+		"a IN (1,2":               `where: cannot parse "a IN (1,2"; expected ',' or ')' in list`,
+		"a BETWEEN 1 OR 2":        `where: cannot parse "a BETWEEN 1 OR 2"; expected AND after BETWEEN`,
+		"a > null OR b=1":         `where: cannot parse "a > null OR b=1"; null can only be used with =, ==, != or <>`,
+		"a='x OR b=1":             `where: cannot parse "a='x OR b=1"; unterminated string`,
+		"a=1 AND b=2; DROP TABLE": `where: cannot parse "a=1 AND b=2; DROP TABLE"; unexpected "DROP"`,
+		"a IS 1 AND b=2":          `where: cannot parse "a IS 1 AND b=2"; expected NULL after IS`,
+		"a=Fred Bloggs AND b=2":   `where: cannot parse "a=Fred Bloggs AND b=2"; unexpected "Bloggs"`,
+		"a = 'x' 'y'":             `where: cannot parse "a = 'x' 'y'"; unexpected "y"`,
+		"a=1 OR NOT":              `where: cannot parse "a=1 OR NOT"; expected column, operator and value`,
+	}
+
+	for s, expected := range errors {
+		_, err := where.Parse(s)
+		g.Expect(err).To(MatchError(expected), s)
+	}
+}
@@ -0,0 +1,100 @@
+package where
+
+import "reflect"
+
+// The quantified comparisons compare a column with every value given by a sub-query,
+// or by an array for Postgres, e.g.
+//
+//   - where.GtAll("age", `SELECT age FROM members WHERE team = ?`, "red")
+//   - where.EqAny("id", "?", []int{1, 2, 3}) // Postgres array
+//
+// The sub-query must use '?' placeholders. Its arguments are merged with those of the
+// enclosing expression, in order, and are numbered accordingly when the expression is
+// formatted, as for Exists.
+//
+// Be careful not to allow injection attacks: do not include a string from an external
+// source in the sub-query.
+
+// EqAny returns a condition that the column equals any of the values, i.e. "column=ANY (...)".
+func EqAny(column, subquery string, value ...any) Expression {
+	return quantified(column, "=ANY", subquery, value)
+}
+
+// NotEqAll returns a condition that the column differs from all of the values, i.e.
+// "column<>ALL (...)".
+func NotEqAll(column, subquery string, value ...any) Expression {
+	return quantified(column, "<>ALL", subquery, value)
+}
+
+// GtAny returns a condition that the column is greater than any of the values.
+func GtAny(column, subquery string, value ...any) Expression {
+	return quantified(column, ">ANY", subquery, value)
+}
+
+// GtAll returns a condition that the column is greater than all of the values.
+func GtAll(column, subquery string, value ...any) Expression {
+	return quantified(column, ">ALL", subquery, value)
+}
+
+// GtEqAny returns a condition that the column is greater than or equal to any of the values.
+func GtEqAny(column, subquery string, value ...any) Expression {
+	return quantified(column, ">=ANY", subquery, value)
+}
+
+// GtEqAll returns a condition that the column is greater than or equal to all of the values.
+func GtEqAll(column, subquery string, value ...any) Expression {
+	return quantified(column, ">=ALL", subquery, value)
+}
+
+// LtAny returns a condition that the column is less than any of the values.
+func LtAny(column, subquery string, value ...any) Expression {
+	return quantified(column, "<ANY", subquery, value)
+}
+
+// LtAll returns a condition that the column is less than all of the values.
+func LtAll(column, subquery string, value ...any) Expression {
+	return quantified(column, "<ALL", subquery, value)
+}
+
+// LtEqAny returns a condition that the column is less than or equal to any of the values.
+func LtEqAny(column, subquery string, value ...any) Expression {
+	return quantified(column, "<=ANY", subquery, value)
+}
+
+// LtEqAll returns a condition that the column is less than or equal to all of the values.
+func LtEqAll(column, subquery string, value ...any) Expression {
+	return quantified(column, "<=ALL", subquery, value)
+}
+
+func quantified(column, comparison, subquery string, value []any) Expression {
+	return Literal(column, comparison+" ("+subquery+")", value...)
+}
+
+// EqAnyOf returns a condition that the column equals any element of a slice, i.e.
+// "column=ANY(?)", binding the whole slice as a single array parameter. Unlike In, the
+// statement text is the same however many values there are, so prepared statements can
+// be reused. This suits Postgres drivers that accept slices as arrays, such as pgx; for
+// lib/pq, wrap the slice using pq.Array.
+//
+// If the slice is nil, this becomes a no-op. Otherwise, it must be an array or slice.
+func EqAnyOf(column string, slice any) Expression {
+	return arrayOf(column, "=ANY(?)", slice)
+}
+
+// NotEqAllOf returns a condition that the column differs from every element of a slice,
+// i.e. "column<>ALL(?)", binding the slice as for EqAnyOf.
+func NotEqAllOf(column string, slice any) Expression {
+	return arrayOf(column, "<>ALL(?)", slice)
+}
+
+func arrayOf(column, predicate string, slice any) Expression {
+	if slice == nil {
+		return NoOp()
+	}
+
+	switch reflect.ValueOf(slice).Kind() {
+	case reflect.Array, reflect.Slice:
+		return Literal(column, predicate, slice)
+	}
+	panic("arg must be an array or slice")
+}
@@ -0,0 +1,54 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestQuantified(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Eq("team", "blue").And(where.GtAll("age", `SELECT age FROM members WHERE team = ?`, "red"))
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE team=$1 AND age>ALL (SELECT age FROM members WHERE team = $2)`))
+	g.Expect(args).To(Equal([]any{"blue", "red"}))
+
+	sql, args = where.Where(where.EqAny("id", "?", []int{1, 2}).Or(where.LtEqAny("n", `SELECT n FROM t`)), dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE id=ANY ($1) OR n<=ANY (SELECT n FROM t)`))
+	g.Expect(args).To(Equal([]any{[]int{1, 2}}))
+
+	g.Expect(where.NotEqAll("a", `SELECT b FROM t`).String()).To(Equal(`a<>ALL (SELECT b FROM t)`))
+	g.Expect(where.GtAny("a", `SELECT b FROM t`).String()).To(Equal(`a>ANY (SELECT b FROM t)`))
+	g.Expect(where.GtEqAny("a", `SELECT b FROM t`).String()).To(Equal(`a>=ANY (SELECT b FROM t)`))
+	g.Expect(where.GtEqAll("a", `SELECT b FROM t`).String()).To(Equal(`a>=ALL (SELECT b FROM t)`))
+	g.Expect(where.LtAny("a", `SELECT b FROM t`).String()).To(Equal(`a<ANY (SELECT b FROM t)`))
+	g.Expect(where.LtAll("a", `SELECT b FROM t`).String()).To(Equal(`a<ALL (SELECT b FROM t)`))
+	g.Expect(where.LtEqAll("a", `SELECT b FROM t`).String()).To(Equal(`a<=ALL (SELECT b FROM t)`))
+}
+
+func TestEqAnyOf(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.EqAnyOf("id", []int{1, 2, 3}).And(where.NotEqAllOf("status", []string{"x"}))
+
+	sql, args := where.Where(wh, dialect.Postgres, dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE id=ANY($1) AND status<>ALL($2)`))
+	g.Expect(args).To(Equal([]any{[]int{1, 2, 3}, []string{"x"}}))
+
+	g.Expect(wh.String()).To(Equal(`id=ANY(ARRAY[1,2,3]) AND status<>ALL(ARRAY['x'])`))
+	g.Expect(where.EqAnyOf("id", []any{1, nil}).String()).To(Equal(`id=ANY(ARRAY[1,NULL])`))
+	g.Expect(where.EqAnyOf("id", []int{}).String()).To(Equal(`id=ANY('{}')`))
+
+	g.Expect(where.VerifyDialectSupport(wh, dialect.Postgres)).To(BeEmpty())
+	issues := where.VerifyDialectSupport(wh, dialect.Mysql)
+	g.Expect(issues).To(HaveLen(2))
+	g.Expect(issues[0].Feature).To(Equal("ANY/ALL array"))
+	g.Expect(where.VerifyDialectSupport(where.EqAny("id", `SELECT id FROM t`), dialect.Mysql)).To(BeEmpty())
+
+	g.Expect(where.EqAnyOf("id", nil)).To(Equal(where.NoOp()))
+	g.Expect(func() { where.EqAnyOf("id", 1) }).To(Panic())
+}
@@ -0,0 +1,95 @@
+package where
+
+import (
+	"errors"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// ErrUnscoped is returned by WhereE etc when an expression lacks the scope required by a
+// ScopedBuilder.
+var ErrUnscoped = errors.New("where: expression lacks the required scope condition")
+
+// ScopedBuilder ensures that every expression it formats is AND-ed with a scope condition,
+// such as "tenant_id = ?". This is a safety net for multi-tenant applications: it makes
+// the scope condition impossible to forget.
+//
+// Expressions are scoped using Scope. As a further safeguard, a ScopedBuilder is also a
+// Checker: when it is passed to WhereE etc, expressions lacking the scope are rejected, e.g.
+//
+//	tenants := where.TenantScope("tenant_id", tenant)
+//	sql, args, err := where.WhereE(tenants.Scope(filter), dialect.Postgres, tenants)
+//
+// A ScopedBuilder is immutable and is safe for concurrent use.
+type ScopedBuilder struct {
+	scope Node
+}
+
+// NewScopedBuilder returns a builder that AND-s the scope condition into every expression.
+func NewScopedBuilder(scope Node) ScopedBuilder {
+	return ScopedBuilder{scope: scope}
+}
+
+// TenantScope returns a builder that requires "column = tenant" in every expression.
+func TenantScope(column string, tenant any) ScopedBuilder {
+	return NewScopedBuilder(Eq(column, tenant))
+}
+
+// Scope AND-s the scope condition with an expression, unless it is already present.
+// The scope condition is placed first. If the expression is nil, only the scope
+// condition is returned.
+func (b ScopedBuilder) Scope(wh Node) Expression {
+	if b.IsScoped(wh) {
+		return Wrap(wh)
+	}
+	return And(b.scope, wh)
+}
+
+// IsScoped tests whether an expression requires the scope condition, i.e. whether the
+// expression is the scope condition or is an 'AND' clause that includes it. When the
+// scope is itself an 'AND' clause, each of its conjuncts must be present.
+func (b ScopedBuilder) IsScoped(wh Node) bool {
+	if wh == nil || b.scope == nil {
+		return false
+	}
+	required := conjuncts(b.scope)
+	if len(required) == 0 {
+		return false
+	}
+	present := make(map[string]bool)
+	for _, w := range conjuncts(wh) {
+		present[dedupeKey(w)] = true
+	}
+	for _, r := range required {
+		if !present[dedupeKey(r)] {
+			return false
+		}
+	}
+	return true
+}
+
+// conjuncts flattens nested 'AND' clauses in the same way as And does. Conjuncts are
+// later compared by structure, so values that are formatted alike but differ, such as
+// 1 and "1", do not match.
+func conjuncts(wh Node) []Node {
+	wh = unwrap(wh)
+	if cl, isClause := wh.(Clause); isClause && (cl.conjunction == and || len(cl.wheres) == 0) {
+		var list []Node
+		for _, w := range cl.wheres {
+			list = append(list, conjuncts(w)...)
+		}
+		return list
+	}
+	return []Node{wh}
+}
+
+// Apply has no effect; ScopedBuilder is a Checker, not a format setting.
+func (b ScopedBuilder) Apply(*dialect.FormatConfig) {}
+
+// Check returns ErrUnscoped if the expression lacks the scope condition (see IsScoped).
+func (b ScopedBuilder) Check(wh Node) error {
+	if !b.IsScoped(wh) {
+		return ErrUnscoped
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestScopedBuilder(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	b := where.TenantScope("tenant_id", 42)
+
+	sql, args := where.Where(b.Scope(nameIsFred.Or(nameIsJohn)), dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE tenant_id=$1 AND (name=$2 OR name=$3)`))
+	g.Expect(args).To(Equal([]any{42, "Fred", "John"}))
+
+	sql, args = where.Having(b.Scope(nil))
+	g.Expect(sql).To(Equal(` HAVING tenant_id=?`))
+	g.Expect(args).To(Equal([]any{42}))
+
+	// already scoped expressions are not scoped again
+	scoped := b.Scope(nameIsFred)
+	g.Expect(b.Scope(scoped.And(ageGt5Int)).String()).To(Equal(`tenant_id=42 AND name='Fred' AND age>5`))
+}
+
+func TestScopedBuilder_checker(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	b := where.TenantScope("tenant_id", 42)
+
+	_, _, err := where.WhereE(nameIsFred, b)
+	g.Expect(err).To(MatchError(where.ErrUnscoped))
+
+	_, _, err = where.WhereE(where.Or(where.Eq("tenant_id", 42), nameIsFred), b)
+	g.Expect(err).To(MatchError(where.ErrUnscoped))
+
+	_, _, err = where.HavingE(where.Eq("tenant_id", 43).And(nameIsFred), b)
+	g.Expect(err).To(MatchError(where.ErrUnscoped))
+
+	_, _, err = where.FormatE(nil, b)
+	g.Expect(err).To(MatchError(where.ErrUnscoped))
+
+	sql, args, err := where.WhereE(b.Scope(nameIsFred), b)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sql).To(Equal(` WHERE tenant_id=? AND name=?`))
+	g.Expect(args).To(Equal([]any{42, "Fred"}))
+
+	// checkers do not alter the format settings and are ignored by Where
+	sql, _ = where.Where(nameIsFred, dialect.Dollar, b)
+	g.Expect(sql).To(Equal(` WHERE name=$1`))
+}
+
+func TestScopedBuilder_comparesValues(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	b := where.TenantScope("tenant_id", 1)
+
+	// these are formatted alike but the values differ
+	g.Expect(b.Check(where.Eq("tenant_id", "1").And(nameIsFred))).To(MatchError(where.ErrUnscoped))
+	g.Expect(b.Check(where.Eq("tenant_id", int64(1)))).To(MatchError(where.ErrUnscoped))
+	g.Expect(b.Check(where.Wrap(where.Eq("tenant_id", 1)).And(nameIsFred))).To(Succeed())
+}
+
+func TestScopedBuilder_compoundScope(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	b := where.NewScopedBuilder(where.Eq("tenant_id", 42).And(where.Eq("region", "eu")))
+
+	// the scope is flattened into the expression, so it is not added again
+	scoped := b.Scope(nameIsFred)
+	sql, args, err := where.WhereE(b.Scope(scoped.And(ageGt5Int)), b)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sql).To(Equal(` WHERE tenant_id=? AND region=? AND name=? AND age>?`))
+	g.Expect(args).To(Equal([]any{42, "eu", "Fred", 5}))
+
+	g.Expect(b.Check(where.Eq("region", "eu").And(ageGt5Int).And(where.Eq("tenant_id", 42)))).To(Succeed())
+	g.Expect(b.Check(where.Eq("tenant_id", 42).And(nameIsFred))).To(MatchError(where.ErrUnscoped))
+}
@@ -0,0 +1,48 @@
+//go:build go1.23
+
+package where
+
+import (
+	"iter"
+	"strings"
+)
+
+// InSeq returns an 'IN' condition on a column, as for In, except that the values are
+// taken from a sequence. This suits large or lazily-produced sets of values, such as
+// identifiers streamed from elsewhere, because they are consumed directly without
+// first being collected into a slice.
+//   - If the sequence is nil or empty, this becomes a no-op.
+//   - If any value is nil, an 'IS NULL' expression is OR-ed with the 'IN' expression.
+func InSeq(column string, values iter.Seq[any]) Expression {
+	if values == nil {
+		return NoOp()
+	}
+
+	var args []any
+	hasNull := false
+	buf := &strings.Builder{}
+	buf.WriteString(" IN (")
+	for arg := range values {
+		if arg == nil {
+			hasNull = true
+			continue
+		}
+		if len(args) > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('?')
+		args = append(args, arg)
+	}
+	buf.WriteByte(')')
+
+	result := NoOp()
+	if len(args) > 0 {
+		result = Condition{Column: column, Predicate: buf.String(), Args: args}
+	}
+
+	if hasNull {
+		result = Or(result, Null(column))
+	}
+
+	return result
+}
@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package where_test
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+)
+
+func TestInSeq(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	s, args := where.Where(where.InSeq("id", slices.Values([]any{1, 2, 3})))
+	g.Expect(s).To(Equal(` WHERE id IN (?,?,?)`))
+	g.Expect(args).To(Equal([]any{1, 2, 3}))
+
+	s, args = where.Where(where.InSeq("id", slices.Values([]any{1, nil})))
+	g.Expect(s).To(Equal(` WHERE id IN (?) OR id IS NULL`))
+	g.Expect(args).To(Equal([]any{1}))
+
+	s, args = where.Where(where.InSeq("id", slices.Values([]any{})))
+	g.Expect(s).To(BeEmpty())
+	g.Expect(args).To(BeNil())
+
+	s, args = where.Where(where.InSeq("id", nil))
+	g.Expect(s).To(BeEmpty())
+	g.Expect(args).To(BeNil())
+}
@@ -0,0 +1,512 @@
+package where
+
+import (
+	"cmp"
+	"math"
+	"time"
+
+	"github.com/rickb777/where/v2/predicate"
+)
+
+// Simplify rewrites an expression into a smaller one with the same meaning. This suits
+// filters that have been merged by machine, e.g. from several sources, which often contain
+// redundant or conflicting conditions. It also suits expressions that have been parsed or
+// deserialized, which lack the tidying done by the builder functions.
+//
+// Repeated conditions are removed first (see Dedupe); this also removes empty clauses
+// (see NoOp), merges nested clauses that have the same conjunction and replaces clauses
+// that have a single child with that child. Double negations are removed, e.g.
+// "NOT (NOT x=1)" becomes "x=1".
+//
+// Within each 'AND' clause, the comparisons on each column are combined:
+//   - overlapping ranges are merged, e.g. "x>1 AND x>3 AND x<=5" becomes "x>3 AND x<=5";
+//   - contradictions, e.g. "x=1 AND x=2" or "x IS NULL AND x>0", make the clause False,
+//     except under NOT (see below);
+//   - conditions implied by an equality are dropped, e.g. "x=3 AND x>1" becomes "x=3".
+//
+// Within each 'OR' clause, one-sided ranges on the same column are merged, e.g. "x>3 OR x>5"
+// becomes "x>3", and tautologies such as "x IS NULL OR x IS NOT NULL" make the clause True.
+//
+// True and False conditions are then propagated, e.g. "a AND False" becomes False and
+// "NOT True" becomes False.
+//
+// A contradiction is not strictly False: when the column is null, the comparisons are
+// unknown instead. This makes no difference to the rows that match, except under NOT,
+// because "NOT unknown" is still unknown whereas "NOT False" is True. So contradictions
+// within NOT are kept, although their conditions are otherwise simplified.
+//
+// Only comparisons of columns with constant numbers, strings and times are combined; other
+// conditions, including aggregates and those with type hints, are kept unchanged. Because
+// the order of strings depends on the database collation, strings are combined only when
+// they are identical, e.g. "name='a' AND name<>'a'" becomes False but "name>'A' AND
+// name>'B'" is kept.
+func Simplify(wh Node) Expression {
+	if wh == nil {
+		return nil
+	}
+	return Wrap(simplify(dedupe(wh), false))
+}
+
+// simplify rewrites a node. When negated is true, the node is within NOT, so
+// contradictions must be kept.
+func simplify(node Node, negated bool) Node {
+	switch n := node.(type) {
+	case wrapper:
+		return simplify(n.node, negated)
+
+	case Clause:
+		if len(n.wheres) == 0 {
+			return n
+		}
+		wheres := make([]Node, len(n.wheres))
+		for i, w := range n.wheres {
+			wheres[i] = simplify(w, negated)
+		}
+		if n.conjunction == or {
+			return simplifyOr(wheres)
+		}
+		return simplifyAnd(wheres, negated)
+
+	case not:
+		inner := simplify(n.expression, true)
+		switch x := inner.(type) {
+		case not:
+			return x.expression // already simplified
+		case Clause:
+			if len(x.wheres) == 0 {
+				return x
+			}
+		}
+		switch {
+		case isConstant(inner, alwaysTrue):
+			return False()
+		case isConstant(inner, alwaysFalse):
+			return True()
+		}
+		return not{expression: inner}
+
+	case Composite:
+		kids := n.Children()
+		replaced := make([]Node, len(kids))
+		for i, k := range kids {
+			replaced[i] = simplify(k, negated)
+		}
+		return n.WithChildren(replaced)
+	}
+
+	return node
+}
+
+func isConstant(node Node, constant string) bool {
+	c, ok := node.(Condition)
+	return ok && c.Column == "" && c.Predicate == constant && len(c.Args) == 0
+}
+
+//-------------------------------------------------------------------------------------------------
+
+func simplifyAnd(wheres []Node, negated bool) Node {
+	columns := make(map[string]*columnRange)
+	var order []any // Node or *columnRange
+
+	for _, w := range wheres {
+		switch {
+		case isConstant(w, alwaysTrue):
+			continue
+		case isConstant(w, alwaysFalse):
+			return False()
+		}
+
+		if c, ok := w.(Condition); ok && rangeable(c) {
+			r, exists := columns[c.Column]
+			if !exists {
+				r = &columnRange{column: c.Column}
+				columns[c.Column] = r
+				order = append(order, r)
+			}
+			r.add(c)
+		} else {
+			order = append(order, w)
+		}
+	}
+
+	var result []Node
+	for _, item := range order {
+		switch x := item.(type) {
+		case *columnRange:
+			conditions, contradiction := x.resolve()
+			switch {
+			case contradiction && negated:
+				result = append(result, x.original...)
+			case contradiction:
+				return False()
+			default:
+				result = append(result, conditions...)
+			}
+		case Node:
+			result = append(result, x)
+		}
+	}
+
+	switch len(result) {
+	case 0:
+		return True()
+	case 1:
+		return result[0]
+	}
+	return Clause{wheres: result, conjunction: and}
+}
+
+func simplifyOr(wheres []Node) Node {
+	type bounds struct{ lower, upper, isNull, notNull int }
+	columns := make(map[string]*bounds)
+	var result []Node
+
+	for _, w := range wheres {
+		switch {
+		case isConstant(w, alwaysTrue):
+			return True()
+		case isConstant(w, alwaysFalse):
+			continue
+		}
+
+		c, ok := w.(Condition)
+		if !ok || !rangeable(c) {
+			result = append(result, w)
+			continue
+		}
+
+		b := columns[c.Column]
+		if b == nil {
+			b = &bounds{lower: -1, upper: -1, isNull: -1, notNull: -1}
+			columns[c.Column] = b
+		}
+
+		// merge with an earlier one-sided range, keeping the looser of the two
+		index := -1
+		switch c.Predicate {
+		case predicate.GreaterThan, predicate.GreaterThanOrEqualTo:
+			index = b.lower
+			if index < 0 {
+				b.lower = len(result)
+			} else if loose, ok := looser(c, result[index].(Condition), 1); !ok {
+				index = -1 // keep both
+			} else if loose {
+				result[index] = c
+			}
+		case predicate.LessThan, predicate.LessThanOrEqualTo:
+			index = b.upper
+			if index < 0 {
+				b.upper = len(result)
+			} else if loose, ok := looser(c, result[index].(Condition), -1); !ok {
+				index = -1 // keep both
+			} else if loose {
+				result[index] = c
+			}
+		case predicate.IsNull:
+			if b.notNull >= 0 {
+				return True()
+			}
+			b.isNull = len(result)
+		case predicate.IsNotNull:
+			if b.isNull >= 0 {
+				return True()
+			}
+			b.notNull = len(result)
+		}
+
+		if index < 0 {
+			result = append(result, w)
+		}
+	}
+
+	switch len(result) {
+	case 0:
+		return False()
+	case 1:
+		return result[0]
+	}
+	return Clause{wheres: result, conjunction: or}
+}
+
+// looser tests whether one-sided range a admits more values than b. The direction is
+// 1 for lower bounds and -1 for upper bounds. The second result is false if the values
+// are not comparable.
+func looser(a, b Condition, direction int) (bool, bool) {
+	c, ok := compareValues(a.Args[0], b.Args[0])
+	if !ok {
+		return false, false
+	}
+	if c == 0 {
+		return inclusive(a.Predicate) && !inclusive(b.Predicate), true
+	}
+	return c*direction < 0, true
+}
+
+func inclusive(p string) bool {
+	return p == predicate.GreaterThanOrEqualTo || p == predicate.LessThanOrEqualTo
+}
+
+// rangeable tests whether a condition is a simple comparison of a column with constants.
+func rangeable(c Condition) bool {
+	if c.Column == "" {
+		return false
+	}
+	switch c.Predicate {
+	case predicate.IsNull, predicate.IsNotNull:
+		return len(c.Args) == 0
+	case predicate.EqualTo, predicate.NotEqualTo,
+		predicate.GreaterThan, predicate.GreaterThanOrEqualTo,
+		predicate.LessThan, predicate.LessThanOrEqualTo:
+		return len(c.Args) == 1 && isComparableValue(c.Args[0])
+	case predicate.Between:
+		return len(c.Args) == 2 && isComparableValue(c.Args[0]) && isComparableValue(c.Args[1])
+	}
+	return false
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// bound is one end of a range.
+type bound struct {
+	value     any
+	inclusive bool
+}
+
+// columnRange accumulates the conditions on a column that are AND-ed together.
+type columnRange struct {
+	column          string
+	original        []Node
+	eq              []any
+	ne              []any
+	lower, upper    *bound
+	isNull, notNull bool
+	incomparable    bool
+}
+
+func (r *columnRange) add(c Condition) {
+	r.original = append(r.original, c)
+	switch c.Predicate {
+	case predicate.IsNull:
+		r.isNull = true
+	case predicate.IsNotNull:
+		r.notNull = true
+	case predicate.EqualTo:
+		r.eq = append(r.eq, c.Args[0])
+	case predicate.NotEqualTo:
+		r.ne = append(r.ne, c.Args[0])
+	case predicate.GreaterThan, predicate.GreaterThanOrEqualTo:
+		r.lower = r.tighter(r.lower, &bound{c.Args[0], inclusive(c.Predicate)}, 1)
+	case predicate.LessThan, predicate.LessThanOrEqualTo:
+		r.upper = r.tighter(r.upper, &bound{c.Args[0], inclusive(c.Predicate)}, -1)
+	case predicate.Between:
+		r.lower = r.tighter(r.lower, &bound{c.Args[0], true}, 1)
+		r.upper = r.tighter(r.upper, &bound{c.Args[1], true}, -1)
+	}
+}
+
+// tighter chooses the bound that admits fewer values. The direction is 1 for lower
+// bounds and -1 for upper bounds.
+func (r *columnRange) tighter(a, b *bound, direction int) *bound {
+	if a == nil {
+		return b
+	}
+	c := r.compare(a.value, b.value)
+	if c*direction > 0 || c == 0 && !a.inclusive {
+		return a
+	}
+	return b
+}
+
+func (r *columnRange) compare(a, b any) int {
+	c, ok := compareValues(a, b)
+	if !ok {
+		r.incomparable = true
+	}
+	return c
+}
+
+// resolve gives the simplest conditions equivalent to those added, or reports a contradiction.
+func (r *columnRange) resolve() ([]Node, bool) {
+	hasComparison := len(r.eq) > 0 || len(r.ne) > 0 || r.lower != nil || r.upper != nil
+	if r.isNull && (r.notNull || hasComparison) {
+		return nil, true // comparisons with null are never true
+	}
+
+	nodes, contradiction := r.resolveComparisons()
+	if r.incomparable {
+		return r.original, false
+	}
+	if contradiction {
+		return nil, true
+	}
+
+	switch {
+	case r.isNull:
+		return []Node{Null(r.column)}, false
+	case r.notNull && !hasComparison:
+		return []Node{NotNull(r.column)}, false
+	}
+	return nodes, false
+}
+
+func (r *columnRange) resolveComparisons() ([]Node, bool) {
+	if len(r.eq) > 0 {
+		v := r.eq[0]
+		for _, other := range r.eq[1:] {
+			if r.compare(v, other) != 0 {
+				return nil, true
+			}
+		}
+		if !r.admits(v) {
+			return nil, true
+		}
+		return []Node{Eq(r.column, v)}, false
+	}
+
+	if r.lower != nil && r.upper != nil {
+		c := r.compare(r.lower.value, r.upper.value)
+		switch {
+		case c > 0:
+			return nil, true
+		case c == 0 && r.lower.inclusive && r.upper.inclusive:
+			if !r.admits(r.lower.value) {
+				return nil, true
+			}
+			return []Node{Eq(r.column, r.lower.value)}, false
+		case c == 0:
+			return nil, true
+		}
+	}
+
+	var nodes []Node
+	if r.lower != nil {
+		if r.lower.inclusive {
+			nodes = append(nodes, GtEq(r.column, r.lower.value))
+		} else {
+			nodes = append(nodes, Gt(r.column, r.lower.value))
+		}
+	}
+	if r.upper != nil {
+		if r.upper.inclusive {
+			nodes = append(nodes, LtEq(r.column, r.upper.value))
+		} else {
+			nodes = append(nodes, Lt(r.column, r.upper.value))
+		}
+	}
+	for _, v := range r.ne {
+		if r.within(v) {
+			nodes = append(nodes, NotEq(r.column, v))
+		}
+	}
+	return nodes, false
+}
+
+// admits tests whether a value satisfies all the bounds and exclusions.
+func (r *columnRange) admits(v any) bool {
+	for _, x := range r.ne {
+		if r.compare(v, x) == 0 {
+			return false
+		}
+	}
+	return r.within(v)
+}
+
+// within tests whether a value lies within the bounds.
+func (r *columnRange) within(v any) bool {
+	if r.lower != nil {
+		c := r.compare(v, r.lower.value)
+		if c < 0 || c == 0 && !r.lower.inclusive {
+			return false
+		}
+	}
+	if r.upper != nil {
+		c := r.compare(v, r.upper.value)
+		if c > 0 || c == 0 && !r.upper.inclusive {
+			return false
+		}
+	}
+	return true
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// isComparableValue tests whether a value can be compared by compareValues.
+func isComparableValue(v any) bool {
+	switch v.(type) {
+	case string, time.Time:
+		return true
+	}
+	_, isInt := asInt64(v)
+	_, isFloat := asFloat64(v)
+	return isInt || isFloat
+}
+
+// compareValues compares numbers, strings or times. The result is false if they are
+// not comparable with each other. The order of strings depends on the database collation,
+// so different strings are not comparable; only identical ones are known to be equal.
+func compareValues(a, b any) (int, bool) {
+	switch x := a.(type) {
+	case string:
+		y, ok := b.(string)
+		return 0, ok && x == y
+	case time.Time:
+		y, ok := b.(time.Time)
+		return x.Compare(y), ok
+	}
+
+	if x, ok := asInt64(a); ok {
+		if y, ok := asInt64(b); ok {
+			return cmp.Compare(x, y), true
+		}
+	}
+
+	x, ok1 := asFloat64(a)
+	y, ok2 := asFloat64(b)
+	if !ok1 || !ok2 || math.IsNaN(x) || math.IsNaN(y) {
+		return 0, false
+	}
+	return cmp.Compare(x, y), true
+}
+
+func asInt64(v any) (int64, bool) {
+	switch x := v.(type) {
+	case int:
+		return int64(x), true
+	case int8:
+		return int64(x), true
+	case int16:
+		return int64(x), true
+	case int32:
+		return int64(x), true
+	case int64:
+		return x, true
+	case uint:
+		return int64(x), x <= math.MaxInt64
+	case uint8:
+		return int64(x), true
+	case uint16:
+		return int64(x), true
+	case uint32:
+		return int64(x), true
+	case uint64:
+		return int64(x), x <= math.MaxInt64
+	}
+	return 0, false
+}
+
+func asFloat64(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	case uint:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	}
+	if i, ok := asInt64(v); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
@@ -0,0 +1,85 @@
+package where_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+)
+
+func TestSimplify(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	cases := []struct {
+		wh  where.Node
+		exp string
+	}{
+		{wh: where.And(where.Eq("x", 1), where.Eq("x", 2)), exp: `FALSE`},
+		{wh: where.And(where.Eq("x", 1), where.Eq("x", 1.0)), exp: `x=1`},
+		{wh: where.And(where.Eq("x", 3), where.Gt("x", 1), nameIsFred), exp: `x=3 AND name='Fred'`},
+		{wh: where.And(where.Eq("x", 3), where.Lt("x", 3)), exp: `FALSE`},
+		{wh: where.And(where.Eq("x", 3), where.NotEq("x", 3)), exp: `FALSE`},
+		{wh: where.And(where.Gt("x", 1), where.Gt("x", 3), where.LtEq("x", 5)), exp: `x>3 AND x<=5`},
+		{wh: where.And(where.GtEq("x", 3), where.Gt("x", 3)), exp: `x>3`},
+		{wh: where.And(where.Between("x", 1, 10), where.Between("x", 5, 20)), exp: `x>=5 AND x<=10`},
+		{wh: where.And(where.GtEq("x", 5), where.LtEq("x", 5)), exp: `x=5`},
+		{wh: where.And(where.Gt("x", 5), where.LtEq("x", 5)), exp: `FALSE`},
+		{wh: where.And(where.Gt("x", 5), where.Lt("x", 2)), exp: `FALSE`},
+		{wh: where.And(where.Gt("x", 5), where.NotEq("x", 2), where.NotEq("x", 7)), exp: `x>5 AND x<>7`},
+		{wh: where.And(where.Null("x"), where.Gt("x", 0)), exp: `FALSE`},
+		{wh: where.And(where.Null("x"), where.NotNull("x")), exp: `FALSE`},
+		{wh: where.And(where.NotNull("x"), where.Gt("x", 0)), exp: `x>0`},
+		{wh: where.And(where.GtEq("ts", t1), where.Lt("ts", t2), where.Gt("ts", t1)), exp: `ts>'2024-01-01 00:00:00 +0000 UTC' AND ts<'2024-01-01 01:00:00 +0000 UTC'`},
+		{wh: where.And(where.Gt("name", "A"), where.Gt("name", "B")), exp: `name>'A' AND name>'B'`},
+		{wh: where.And(where.Eq("name", "a"), where.Eq("name", "A")), exp: `name='a' AND name='A'`},
+		{wh: where.And(where.Eq("name", "a"), where.NotEq("name", "a")), exp: `FALSE`},
+		{wh: where.And(where.GtEq("name", "a"), where.Gt("name", "a")), exp: `name>'a'`},
+		{wh: where.And(where.Gt("x", "A"), where.Gt("x", 1)), exp: `x>'A' AND x>1`},
+		{wh: where.And(where.True(), nameIsFred), exp: `name='Fred'`},
+		{wh: where.And(where.False(), nameIsFred), exp: `FALSE`},
+		{wh: where.Or(where.Gt("x", 3), where.Gt("x", 5), where.LtEq("y", 1), where.Lt("y", 1)), exp: `x>3 OR y<=1`},
+		{wh: where.Or(where.Gt("x", 3), where.Gt("x", "A")), exp: `x>3 OR x>'A'`},
+		{wh: where.Or(where.Lt("name", "A"), where.Lt("name", "B")), exp: `name<'A' OR name<'B'`},
+		{wh: where.Or(where.Null("x"), nameIsFred, where.NotNull("x")), exp: `TRUE`},
+		{wh: where.Or(where.False(), nameIsFred), exp: `name='Fred'`},
+		{wh: where.Or(where.And(where.Eq("x", 1), where.Eq("x", 2)), nameIsFred), exp: `name='Fred'`},
+		{wh: where.Not(where.And(where.Eq("x", 1), where.Eq("x", 2))), exp: `NOT (x=1 AND x=2)`},
+		{wh: where.Not(where.And(where.Null("x"), where.Gt("x", 0))), exp: `NOT (x IS NULL AND x>0)`},
+		{wh: where.Not(where.And(where.Gt("x", 1), where.Gt("x", 3), where.Lt("x", 0))), exp: `NOT (x>1 AND x>3 AND x<0)`},
+		{wh: where.Not(where.And(where.Gt("x", 1), where.Gt("x", 3))), exp: `NOT x>3`},
+		{wh: where.Not(where.And(where.False(), nameIsFred)), exp: `TRUE`},
+		{wh: where.Not(where.Or(where.And(where.Eq("x", 1), where.Eq("x", 2)), nameIsFred)), exp: `NOT ((x=1 AND x=2) OR name='Fred')`},
+		{wh: where.Not(where.Or(where.Null("x"), where.NotNull("x"))), exp: `FALSE`},
+		{wh: where.And(nameIsFred, nameIsFred, where.Label("l", where.Or(where.Gt("x", 1), where.Gt("x", 0)))), exp: `name='Fred' AND x>0`},
+		{wh: where.And(where.CountGt("x", 1), where.CountGt("x", 2)), exp: `COUNT(x)>1 AND COUNT(x)>2`},
+		{wh: where.Not(where.Not(nameIsFred)), exp: `name='Fred'`},
+		{wh: where.Not(where.Not(where.Not(nameIsFred))), exp: `NOT name='Fred'`},
+		{wh: where.And(where.Not(where.Not(where.Or(nameIsFred, nameIsJohn))), where.Gt("x", 1)), exp: `(name='Fred' OR name='John') AND x>1`},
+		{wh: where.Not(where.Not(where.Gt("x", 1).And(where.Gt("x", 2)))), exp: `x>2`},
+	}
+
+	for _, c := range cases {
+		g.Expect(where.Simplify(c.wh).String()).To(Equal(c.exp), c.wh.String())
+	}
+
+	g.Expect(where.Simplify(nil)).To(BeNil())
+}
+
+func TestSimplify_structure(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// as might arise from a parser, without the tidying done by the builder functions
+	wh := where.Map(where.And(where.Or(nameIsFred), where.Not(where.Not(where.And(where.Gt("x", 1), where.Null("tmp"))))),
+		func(c where.Condition) where.Expression {
+			if c.Column == "tmp" {
+				return nil
+			}
+			return c
+		})
+
+	g.Expect(where.Simplify(wh).String()).To(Equal(`name='Fred' AND x>1`))
+}
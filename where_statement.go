@@ -0,0 +1,82 @@
+package where
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// StatementName derives a short, stable identifier from a formatted SQL statement, for use
+// as a prepared statement name or as a statement-cache key. Because the arguments are
+// bound separately, expressions that differ only in their argument values share the same
+// statement, and so the same name. Leading and trailing whitespace is ignored.
+//
+// The name is "w_" followed by 24 hexadecimal digits, which suits the naming rules of all the
+// supported databases.
+//
+// The name is derived from the text, so that it always matches the SQL that is prepared and
+// the order of the arguments bound to it. Format the canonical form of each filter (see
+// Canonical) so that logically equal filters, such as "a=? AND b=?" and "b=? AND a=?",
+// give the same text and so share a statement.
+func StatementName(query string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(query)))
+	return "w_" + hex.EncodeToString(sum[:12])
+}
+
+// Canonical rewrites an expression into a standard form, so that expressions that are
+// logically equal apart from the order of their operands produce the same SQL. Repeated
+// conditions are removed first (see Dedupe); then the operands of each 'AND' and 'OR'
+// clause are sorted by their SQL, formatted without the argument values. The argument
+// values do not affect the order, so expressions that differ only in their values still
+// give the same SQL, e.g. for a prepared statement (see StatementName).
+func Canonical(wh Node) Expression {
+	if wh == nil {
+		return nil
+	}
+	c := newConfig(dialect.FormatConfig{}, []dialect.FormatOption{WithoutDefaults()})
+	return Wrap(canonical(dedupe(wh), c))
+}
+
+func canonical(node Node, c config) Node {
+	switch n := node.(type) {
+	case wrapper:
+		return canonical(n.node, c)
+
+	case Clause:
+		type operand struct {
+			node Node
+			key  string
+		}
+		operands := make([]operand, len(n.wheres))
+		for i, w := range n.wheres {
+			w = canonical(w, c)
+			key, _ := formatNode(w, c)
+			operands[i] = operand{node: w, key: key}
+		}
+		slices.SortStableFunc(operands, func(a, b operand) int {
+			return cmp.Compare(a.key, b.key)
+		})
+		wheres := make([]Node, len(operands))
+		for i, o := range operands {
+			wheres[i] = o.node
+		}
+		return Clause{wheres: wheres, conjunction: n.conjunction}
+
+	case not:
+		return not{expression: canonical(n.expression, c)}
+
+	case Composite:
+		kids := n.Children()
+		replaced := make([]Node, len(kids))
+		for i, k := range kids {
+			replaced[i] = canonical(k, c)
+		}
+		return n.WithChildren(replaced)
+	}
+
+	return node
+}
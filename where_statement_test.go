@@ -0,0 +1,54 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestStatementName(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sql1, _ := where.Where(where.Eq("name", "Fred").And(where.Gt("age", 10)), dialect.Dollar)
+	sql2, _ := where.Where(where.Eq("name", "John").And(where.Gt("age", 20)), dialect.Dollar)
+	sql3, _ := where.Where(where.Eq("name", "John").Or(where.Gt("age", 20)), dialect.Dollar)
+
+	name := where.StatementName("SELECT * FROM users" + sql1)
+	g.Expect(name).To(MatchRegexp(`^w_[0-9a-f]{24}$`))
+	g.Expect(where.StatementName("SELECT * FROM users" + sql2)).To(Equal(name))
+	g.Expect(where.StatementName(" SELECT * FROM users" + sql2 + "\n")).To(Equal(name))
+	g.Expect(where.StatementName("SELECT * FROM users" + sql3)).NotTo(Equal(name))
+
+	// the order of the operands matters, unless the canonical form is used
+	sql4, _ := where.Where(where.Gt("age", 10).And(where.Eq("name", "Fred")), dialect.Dollar)
+	g.Expect(where.StatementName("SELECT * FROM users" + sql4)).NotTo(Equal(name))
+
+	canon1, _ := where.Where(where.Canonical(where.Eq("name", "Fred").And(where.Gt("age", 10))), dialect.Dollar)
+	canon4, args4 := where.Where(where.Canonical(where.Gt("age", 20).And(where.Eq("name", "John"))), dialect.Dollar)
+	g.Expect(canon4).To(Equal(` WHERE age>$1 AND name=$2`))
+	g.Expect(args4).To(Equal([]any{20, "John"}))
+	g.Expect(where.StatementName("SELECT * FROM users" + canon4)).To(Equal(where.StatementName("SELECT * FROM users" + canon1)))
+
+	// repeated conditions do not, after Dedupe
+	sql5, _ := where.Where(where.Dedupe(where.Eq("name", "Fred").And(where.Gt("age", 10)).And(where.Eq("name", "Fred"))), dialect.Dollar)
+	g.Expect(where.StatementName("SELECT * FROM users" + sql5)).To(Equal(name))
+}
+
+func TestCanonical(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := where.Eq("a", 1)
+	b := where.Gt("b", 2)
+	c := where.Null("c")
+
+	g.Expect(where.Canonical(where.And(c, b, a)).String()).To(Equal(`a=1 AND b>2 AND c IS NULL`))
+	g.Expect(where.Canonical(where.Or(b, where.And(c, a), b)).String()).To(Equal(`(a=1 AND c IS NULL) OR b>2`))
+	g.Expect(where.Canonical(where.Not(where.Or(b, a))).String()).To(Equal(`NOT (a=1 OR b>2)`))
+
+	// the values do not affect the order
+	g.Expect(where.Canonical(where.Or(where.Eq("a", 9), where.Eq("a", 1))).String()).To(Equal(`a=9 OR a=1`))
+
+	g.Expect(where.Canonical(nil)).To(BeNil())
+}
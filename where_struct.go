@@ -0,0 +1,93 @@
+package where
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// structOperators maps the operators accepted in struct tags (see FromStruct) to the
+// corresponding condition functions.
+var structOperators = map[string]func(column string, value any) Expression{
+	"eq":    Eq,
+	"ne":    NotEq,
+	"gt":    Gt,
+	"gte":   GtEq,
+	"lt":    Lt,
+	"lte":   LtEq,
+	"like":  func(column string, value any) Expression { return Like(column, fmt.Sprint(value)) },
+	"ilike": func(column string, value any) Expression { return ILike(column, fmt.Sprint(value)) },
+	"in":    InSlice,
+	"notin": NotInSlice,
+}
+
+// FromStruct builds an 'AND' clause of conditions from the fields of a struct (or a pointer
+// to one), using their "where" tags. Each tag gives the column and, optionally, the
+// operator, e.g.
+//
+//	type Search struct {
+//		Name     string   `where:"name"`            // name=?
+//		MinAge   int      `where:"age,gte"`         // age>=?
+//		Statuses []string `where:"status,in"`       // status IN (?,...)
+//		Manager  *int64   `where:"manager_id"`      // manager_id=?, if not nil
+//		Page     int      `where:"-"`               // ignored
+//	}
+//
+// The operators are eq (the default), ne, gt, gte, lt, lte, like, ilike, in and notin.
+// Fields that are nil or have the zero value of their type are skipped, as are fields
+// without tags; use a pointer for a value that may legitimately be zero. Non-nil pointers
+// are dereferenced. The fields of embedded structs are included.
+//
+// FromStruct panics if v is not a struct or if a tag has an unknown operator.
+func FromStruct(v any) Expression {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("where: FromStruct requires a struct, not %T", v))
+	}
+	return And(structConditions(value)...)
+}
+
+func structConditions(value reflect.Value) []Node {
+	var conditions []Node
+	t := value.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup("where")
+
+		if field.Anonymous && !tagged {
+			if embedded := reflect.Indirect(value.Field(i)); embedded.Kind() == reflect.Struct {
+				conditions = append(conditions, structConditions(embedded)...)
+			}
+			continue
+		}
+
+		if !tagged || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		column, op, _ := strings.Cut(tag, ",")
+		if column == "" {
+			column = field.Name
+		}
+		if op == "" {
+			op = "eq"
+		}
+
+		fn, ok := structOperators[op]
+		if !ok {
+			panic(fmt.Sprintf("where: FromStruct field %s has unknown operator %q", field.Name, op))
+		}
+
+		fv := value.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			fv = fv.Elem()
+		}
+		conditions = append(conditions, fn(column, fv.Interface()))
+	}
+
+	return conditions
+}
@@ -0,0 +1,46 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+type Paging struct {
+	Sort string `where:"-"`
+}
+
+type Search struct {
+	Paging
+	Name     string   `where:"name"`
+	MinAge   int      `where:"age,gte"`
+	MaxAge   int      `where:"age,lt"`
+	Statuses []string `where:"status,in"`
+	Manager  *int64   `where:"manager_id"`
+	Active   *bool    `where:"active"`
+	Pattern  string   `where:",like"`
+	Page     int
+}
+
+func TestFromStruct(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	no := false
+	s := Search{Name: "Fred", MinAge: 18, Statuses: []string{"open", "held"}, Active: &no, Page: 2}
+
+	sql, args := where.Where(where.FromStruct(&s), dialect.Dollar)
+	g.Expect(sql).To(Equal(` WHERE name=$1 AND age>=$2 AND status IN ($3,$4) AND active=$5`))
+	g.Expect(args).To(Equal([]any{"Fred", 18, "open", "held", false}))
+
+	g.Expect(where.FromStruct(Search{Pattern: "F%"}).String()).To(Equal(`Pattern LIKE 'F%'`))
+	g.Expect(where.FromStruct(Search{}).String()).To(BeEmpty())
+
+	g.Expect(func() { where.FromStruct(1) }).To(PanicWith(`where: FromStruct requires a struct, not int`))
+	g.Expect(func() {
+		where.FromStruct(struct {
+			A int `where:"a,approx"`
+		}{A: 1})
+	}).To(PanicWith(`where: FromStruct field A has unknown operator "approx"`))
+}
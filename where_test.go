@@ -15,6 +15,22 @@ var nameIsJohn = where.Eq("name", "John")
 var ageLt10Int = where.Lt("age", 10)
 var ageGt5Int = where.Gt("age", 5)
 
+type colour int
+
+const (
+	red colour = iota
+	green
+)
+
+func (c colour) String() string { return [...]string{"red", "green"}[c] }
+
+type size string
+
+const (
+	small size = "S"
+	large size = "L"
+)
+
 var (
 	buildWhereClauseHappyCases = []struct {
 		wh           where.Expression
@@ -85,6 +101,15 @@ var (
 			args:         []any{"F%"},
 		},
 
+		{
+			wh:           where.NotLike("name", "F%"),
+			expMySql:     " WHERE `name` NOT LIKE ?",
+			expPostgres:  ` WHERE "name" NOT LIKE $1`,
+			expSqlServer: ` WHERE [name] NOT LIKE @p1`,
+			expString:    `name NOT LIKE 'F%'`,
+			args:         []any{"F%"},
+		},
+
 		{
 			wh:           where.NoOp().And(nameIsFred),
 			expMySql:     " WHERE `name`=?",
@@ -184,6 +209,15 @@ var (
 			args:         []any{int8(10), int16(12), int32(14)},
 		},
 
+		{
+			wh:           where.Eq("a", 1).And(where.Exists(`SELECT 1 FROM t WHERE t.b = ?`, 2)).And(where.NotExists(`SELECT 1 FROM u`)),
+			expMySql:     " WHERE `a`=? AND EXISTS (SELECT 1 FROM t WHERE t.b = ?) AND NOT EXISTS (SELECT 1 FROM u)",
+			expPostgres:  ` WHERE "a"=$1 AND EXISTS (SELECT 1 FROM t WHERE t.b = $2) AND NOT EXISTS (SELECT 1 FROM u)`,
+			expSqlServer: ` WHERE [a]=@p1 AND EXISTS (SELECT 1 FROM t WHERE t.b = @p2) AND NOT EXISTS (SELECT 1 FROM u)`,
+			expString:    `a=1 AND EXISTS (SELECT 1 FROM t WHERE t.b = 2) AND NOT EXISTS (SELECT 1 FROM u)`,
+			args:         []any{1, 2},
+		},
+
 		{ // 'In' without any vararg parameters
 			wh: where.In("age"),
 		},
@@ -227,6 +261,68 @@ var (
 			wh: where.InSlice("ages", nil),
 		},
 
+		{ // 'NotIn' with mixed value and nil vararg parameters
+			wh:           where.NotIn("age", 1, nil, 2),
+			expMySql:     " WHERE `age` NOT IN (?,?) AND `age` IS NOT NULL",
+			expPostgres:  ` WHERE "age" NOT IN ($1,$2) AND "age" IS NOT NULL`,
+			expSqlServer: ` WHERE [age] NOT IN (@p1,@p2) AND [age] IS NOT NULL`,
+			expString:    `age NOT IN (1,2) AND age IS NOT NULL`,
+			args:         []any{1, 2},
+		},
+
+		{ // 'NotIn' without any vararg parameters
+			wh: where.NotIn("age"),
+		},
+
+		{ // 'NotInSlice' with mixed value and nil parameters
+			wh:           where.NotInSlice("ages", []any{nil, 10}),
+			expMySql:     " WHERE `ages` NOT IN (?) AND `ages` IS NOT NULL",
+			expPostgres:  ` WHERE "ages" NOT IN ($1) AND "ages" IS NOT NULL`,
+			expSqlServer: ` WHERE [ages] NOT IN (@p1) AND [ages] IS NOT NULL`,
+			expString:    `ages NOT IN (10) AND ages IS NOT NULL`,
+			args:         []any{10},
+		},
+
+		{ // 'NotInSlice' with only a nil parameter
+			wh: where.NotInSlice("ages", nil),
+		},
+
+		{
+			wh:           where.InStringers("colour", red, green),
+			expMySql:     " WHERE `colour` IN (?,?)",
+			expPostgres:  ` WHERE "colour" IN ($1,$2)`,
+			expSqlServer: ` WHERE [colour] IN (@p1,@p2)`,
+			expString:    `colour IN ('red','green')`,
+			args:         []any{"red", "green"},
+		},
+
+		{
+			wh:           where.InStrings("size", small, large),
+			expMySql:     " WHERE `size` IN (?,?)",
+			expPostgres:  ` WHERE "size" IN ($1,$2)`,
+			expSqlServer: ` WHERE [size] IN (@p1,@p2)`,
+			expString:    `size IN ('S','L')`,
+			args:         []any{"S", "L"},
+		},
+
+		{ // 'InChunked' with mixed value and nil parameters
+			wh:           where.InChunked("ages", []any{1, nil, 2, 3}, 2),
+			expMySql:     " WHERE `ages` IN (?,?) OR `ages` IN (?) OR `ages` IS NULL",
+			expPostgres:  ` WHERE "ages" IN ($1,$2) OR "ages" IN ($3) OR "ages" IS NULL`,
+			expSqlServer: ` WHERE [ages] IN (@p1,@p2) OR [ages] IN (@p3) OR [ages] IS NULL`,
+			expString:    `ages IN (1,2) OR ages IN (3) OR ages IS NULL`,
+			args:         []any{1, 2, 3},
+		},
+
+		{ // 'InChunked' that needs only one chunk
+			wh:           where.InChunked("ages", []any{1, 2}, 2),
+			expMySql:     " WHERE `ages` IN (?,?)",
+			expPostgres:  ` WHERE "ages" IN ($1,$2)`,
+			expSqlServer: ` WHERE [ages] IN (@p1,@p2)`,
+			expString:    `ages IN (1,2)`,
+			args:         []any{1, 2},
+		},
+
 		{
 			wh:           nameIsFred.Or(nameIsJohn),
 			expMySql:     " WHERE `name`=? OR `name`=?",
@@ -453,6 +549,70 @@ func TestBuildHavingClause_SQLServer_happyCases(t *testing.T) {
 	}
 }
 
+func TestExpr(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Expr("LOWER(name)", "=?", "fred").And(where.Expr("COALESCE(p.age, 0)", ">?", 18))
+
+	sql, args := where.Where(wh, dialect.ANSIQuotes, dialect.Dollar, where.WithTableAlias("t"))
+	g.Expect(sql).To(Equal(` WHERE LOWER("t"."name")=$1 AND COALESCE("p"."age", 0)>$2`))
+	g.Expect(args).To(Equal([]any{"fred", 18}))
+
+	sql, args = where.Where(wh, dialect.Named)
+	g.Expect(sql).To(Equal(` WHERE LOWER(name)=:LOWER_name__1 AND COALESCE(p.age, 0)>:COALESCE_p_age__0__1`))
+	g.Expect(args).To(HaveLen(2))
+
+	g.Expect(wh.String()).To(Equal(`LOWER(name)='fred' AND COALESCE(p.age, 0)>18`))
+	g.Expect(where.Describe(wh)).To(Equal(`LOWER(name) is 'fred' and COALESCE(p.age, 0) is greater than 18`))
+}
+
+func TestEqOrNull(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	name := "Fred"
+	var none *string
+
+	g.Expect(where.EqOrNull("name", &name)).To(Equal(where.Eq("name", "Fred")))
+	g.Expect(where.EqOrNull("name", none)).To(Equal(where.Null("name")))
+	g.Expect(where.EqOrNull("name", nil)).To(Equal(where.Null("name")))
+	g.Expect(where.EqOrNull("age", 10)).To(Equal(where.Eq("age", 10)))
+}
+
+func TestRaw(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Eq(where.Raw("Name"), "Fred").And(where.Gt("age", 18))
+
+	sql, args := where.Where(wh, dialect.ANSIQuotes, dialect.Dollar, where.WithTableAlias("t"))
+	g.Expect(sql).To(Equal(` WHERE Name=$1 AND "t"."age">$2`))
+	g.Expect(args).To(Equal([]any{"Fred", 18}))
+
+	sql, _ = where.Where(wh, dialect.Named)
+	g.Expect(sql).To(Equal(` WHERE Name=:Name_1 AND age>:age_1`))
+
+	g.Expect(wh.String()).To(Equal(`Name='Fred' AND age>18`))
+	g.Expect(where.Describe(wh)).To(Equal(`Name is 'Fred' and age is greater than 18`))
+	g.Expect(where.OrderBy(where.Raw("Name"), "age").Format(dialect.Postgres, dialect.ANSIQuotes)).To(Equal(` ORDER BY Name, "age"`))
+}
+
+func TestWhereAnd_WhereOr(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Eq("a", 1).Or(where.Gt("b", 2))
+
+	sql, args := where.WhereAnd(wh, dialect.Dollar, where.WithPlaceholderOffset(2))
+	g.Expect(sql).To(Equal(` AND (a=$3 OR b>$4)`))
+	g.Expect(args).To(Equal([]any{1, 2}))
+
+	sql, args = where.WhereOr(where.Eq("c", 3), dialect.AtP, where.WithPlaceholderOffset(1))
+	g.Expect(sql).To(Equal(` OR (c=@p2)`))
+	g.Expect(args).To(Equal([]any{3}))
+
+	sql, args = where.WhereAnd(where.NoOp())
+	g.Expect(sql).To(BeEmpty())
+	g.Expect(args).To(BeNil())
+}
+
 func BenchmarkBuildWhereClause_happyCases_build(b *testing.B) {
 	for _, c := range buildWhereClauseHappyCases {
 		_, _ = where.Where(c.wh, dialect.ANSIQuotes)
@@ -558,3 +718,35 @@ func ExampleWhere_sqlserver_using_parameters() {
 	// Output: WHERE ([name]=@p1 OR [name]=@p2) AND [age]>@p3 AND [likes] IN (@p4,@p5)
 	// [John Peter 10 cats dogs]
 }
+
+func TestClause_isImmutable(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the base clause has spare capacity in its slice
+	base := where.And(where.Eq("a", 1), where.Eq("b", 2), where.Eq("c", 3))
+
+	x := base.And(where.Eq("x", 1))
+	y := base.And(where.Eq("y", 2))
+	g.Expect(x.String()).To(Equal(`a=1 AND b=2 AND c=3 AND x=1`))
+	g.Expect(y.String()).To(Equal(`a=1 AND b=2 AND c=3 AND y=2`))
+
+	p := x.And(where.And(where.Eq("p", 1), where.Eq("q", 2)))
+	r := x.And(where.And(where.Eq("r", 1), where.Eq("s", 2)))
+	g.Expect(p.String()).To(Equal(`a=1 AND b=2 AND c=3 AND x=1 AND p=1 AND q=2`))
+	g.Expect(r.String()).To(Equal(`a=1 AND b=2 AND c=3 AND x=1 AND r=1 AND s=2`))
+	g.Expect(base.String()).To(Equal(`a=1 AND b=2 AND c=3`))
+}
+
+func BenchmarkWhere_nestedClauses(b *testing.B) {
+	wh := where.And(
+		where.Or(where.Eq("name", "John"), where.Eq("name", "Peter"), where.Null("name")),
+		where.Gt("age", 10),
+		where.In("likes", "cats", "dogs"),
+		where.Not(where.And(where.Eq("city", "Leeds"), where.Like("postcode", "LS%"))),
+	)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = where.Where(wh, dialect.Dollar)
+	}
+}
@@ -0,0 +1,99 @@
+package where
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// Within returns a condition that a timestamp column lies in the window [t-d, t+d), i.e.
+// "column >= ? AND column < ?". This suits queries for recent events near a point in time.
+//
+// The window bounds are converted to UTC, so that the arguments do not depend on the local
+// time zone. The sign of d is ignored.
+func Within(column string, t time.Time, d time.Duration) Expression {
+	d = absDuration(d)
+	return window(column, t.Add(-d), t.Add(d))
+}
+
+// WithinBefore returns a condition that a timestamp column lies in the window [t-d, t),
+// as for Within. For example, WithinBefore("created_at", time.Now(), time.Hour) matches
+// the last hour.
+func WithinBefore(column string, t time.Time, d time.Duration) Expression {
+	return window(column, t.Add(-absDuration(d)), t)
+}
+
+// WithinAfter returns a condition that a timestamp column lies in the window [t, t+d),
+// as for Within.
+func WithinAfter(column string, t time.Time, d time.Duration) Expression {
+	return window(column, t, t.Add(absDuration(d)))
+}
+
+// OnDate returns a condition that a timestamp column lies within the calendar day of t,
+// in t's location. This is rendered as a range, i.e. "column >= ? AND column < ?", rather
+// than by truncating the column, so that an index on the column can be used. As for
+// Within, the bounds are converted to UTC.
+func OnDate(column string, t time.Time) Expression {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return window(column, day, day.AddDate(0, 0, 1))
+}
+
+// BeforeNow returns a condition that a timestamp column is earlier than the current time
+// according to the database, i.e. "column<CURRENT_TIMESTAMP".
+func BeforeNow(column string) Expression {
+	return Literal(column, "<CURRENT_TIMESTAMP")
+}
+
+// AfterNow returns a condition that a timestamp column is later than the current time
+// according to the database, i.e. "column>CURRENT_TIMESTAMP".
+func AfterNow(column string) Expression {
+	return Literal(column, ">CURRENT_TIMESTAMP")
+}
+
+// WithinLast returns a condition that a timestamp column is no earlier than the duration d
+// before the current time according to the database, e.g. "column>=CURRENT_TIMESTAMP-INTERVAL
+// '3600' SECOND". The date arithmetic is rendered for the dialect, e.g. using DATEADD for
+// SQL-Server and Firebird, UNITS for Informix and datetime for SQLite. Unlike WithinBefore, this relies on the database
+// clock. The sign of d is ignored and it is truncated to whole seconds.
+func WithinLast(column string, d time.Duration) Expression {
+	seconds := strconv.FormatInt(int64(absDuration(d)/time.Second), 10)
+	return Literal(column, intervalPrefix+seconds+intervalSuffix)
+}
+
+const (
+	intervalPrefix = ">=CURRENT_TIMESTAMP-INTERVAL '"
+	intervalSuffix = "' SECOND"
+)
+
+// adaptInterval renders the date arithmetic of WithinLast for the dialect.
+func adaptInterval(predicate string, d dialect.Dialect) string {
+	seconds := strings.TrimSuffix(strings.TrimPrefix(predicate, intervalPrefix), intervalSuffix)
+	switch d {
+	case dialect.Sqlite:
+		return ">=datetime('now', '-" + seconds + " seconds')"
+	case dialect.SqlServer:
+		return ">=DATEADD(second, -" + seconds + ", CURRENT_TIMESTAMP)"
+	case dialect.Firebird:
+		return ">=DATEADD(SECOND, -" + seconds + ", CURRENT_TIMESTAMP)"
+	case dialect.Informix:
+		return ">=CURRENT - " + seconds + " UNITS SECOND"
+	case dialect.DB2:
+		return ">=CURRENT_TIMESTAMP - " + seconds + " SECONDS"
+	case dialect.Mysql, dialect.MariaDB:
+		return ">=CURRENT_TIMESTAMP - INTERVAL " + seconds + " SECOND"
+	}
+	return predicate
+}
+
+func window(column string, from, to time.Time) Expression {
+	return And(GtEq(column, from.UTC()), Lt(column, to.UTC()))
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,70 @@
+package where_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestWithin(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, paris)
+	utc := t0.UTC()
+
+	sql, args := where.Where(where.Within("at", t0, time.Hour))
+	g.Expect(sql).To(Equal(` WHERE at>=? AND at<?`))
+	g.Expect(args).To(Equal([]any{utc.Add(-time.Hour), utc.Add(time.Hour)}))
+	g.Expect(args[0].(time.Time).Location()).To(Equal(time.UTC))
+
+	_, args = where.Where(where.WithinBefore("at", t0, -time.Minute))
+	g.Expect(args).To(Equal([]any{utc.Add(-time.Minute), utc}))
+
+	_, args = where.Where(where.WithinAfter("at", t0, time.Minute))
+	g.Expect(args).To(Equal([]any{utc, utc.Add(time.Minute)}))
+}
+
+func TestOnDate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+	t0 := time.Date(2024, 6, 1, 12, 30, 0, 0, paris)
+
+	sql, args := where.Where(where.OnDate("at", t0))
+	g.Expect(sql).To(Equal(` WHERE at>=? AND at<?`))
+	g.Expect(args).To(Equal([]any{
+		time.Date(2024, 5, 31, 22, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 1, 22, 0, 0, 0, time.UTC),
+	}))
+}
+
+func TestNowConditions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.BeforeNow("expires").And(where.WithinLast("created", -90*time.Minute))
+
+	sql, args := where.Where(wh, dialect.Postgres)
+	g.Expect(sql).To(Equal(` WHERE expires<CURRENT_TIMESTAMP AND created>=CURRENT_TIMESTAMP-INTERVAL '5400' SECOND`))
+	g.Expect(args).To(BeNil())
+
+	sql, _ = where.Where(wh, dialect.Mysql)
+	g.Expect(sql).To(Equal(` WHERE expires<CURRENT_TIMESTAMP AND created>=CURRENT_TIMESTAMP - INTERVAL 5400 SECOND`))
+
+	sql, _ = where.Where(wh, dialect.Sqlite)
+	g.Expect(sql).To(Equal(` WHERE expires<CURRENT_TIMESTAMP AND created>=datetime('now', '-5400 seconds')`))
+
+	sql, _ = where.Where(where.WithinLast("created", time.Hour), dialect.SqlServer)
+	g.Expect(sql).To(Equal(` WHERE created>=DATEADD(second, -3600, CURRENT_TIMESTAMP)`))
+
+	sql, _ = where.Where(where.WithinLast("created", time.Hour), dialect.Firebird)
+	g.Expect(sql).To(Equal(` WHERE created>=DATEADD(SECOND, -3600, CURRENT_TIMESTAMP)`))
+
+	sql, _ = where.Where(where.WithinLast("created", time.Hour), dialect.Informix)
+	g.Expect(sql).To(Equal(` WHERE created>=CURRENT - 3600 UNITS SECOND`))
+
+	g.Expect(where.AfterNow("starts").String()).To(Equal(`starts>CURRENT_TIMESTAMP`))
+}
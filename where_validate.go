@@ -0,0 +1,56 @@
+package where
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrColumnNotAllowed is returned by Validate for columns that are not in the allow-list.
+var ErrColumnNotAllowed = errors.New("where: column is not allowed")
+
+// Validate checks an expression before it is used, which is essential when it has been
+// constructed from user-supplied filter parameters. Every column used by the expression
+// (see Columns) must be in the allow-list; the error wraps ErrColumnNotAllowed for each
+// one that is not. Custom nodes that implement Validator are also checked.
+//
+// Conditions without a column, such as those from Predicate and Exists, are not checked,
+// so these should not be built from user-supplied values. The result is nil if the
+// expression is valid.
+func Validate(wh Node, allowed []string) error {
+	var errs []error
+
+	for _, column := range Columns(wh) {
+		if !slices.Contains(allowed, column) {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrColumnNotAllowed, column))
+		}
+	}
+
+	Walk(wh, func(n Node) bool {
+		if v, ok := n.(Validator); ok {
+			if err := v.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return true
+	})
+
+	return errors.Join(errs...)
+}
+
+// Validate checks that every column used in the ORDER BY terms of the query constraint is
+// in the allow-list, in the same way as for expressions (see Validate). The result is nil
+// if the query constraint is valid.
+func (qc *Constraint) Validate(allowed []string) error {
+	if qc == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, term := range qc.orderBy {
+		if column := plainColumn(term.column); !slices.Contains(allowed, column) {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrColumnNotAllowed, column))
+		}
+	}
+	return errors.Join(errs...)
+}
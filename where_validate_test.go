@@ -0,0 +1,49 @@
+package where_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+)
+
+// Validate implements where.Validator.
+func (n tagsContain) Validate() error {
+	if len(n) != 2 {
+		return errors.New("tagsContain requires two values")
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	allowed := []string{"name", "age"}
+
+	g.Expect(where.Validate(where.And(nameIsFred, where.Not(ageGt5Int)), allowed)).To(Succeed())
+	g.Expect(where.Validate(where.NoOp(), allowed)).To(Succeed())
+
+	err := where.Validate(where.And(nameIsFred, where.Eq("password", "x"), where.Eq(where.Raw("1=1 OR name"), 1)), allowed)
+	g.Expect(err).To(MatchError(where.ErrColumnNotAllowed))
+	g.Expect(err.Error()).To(Equal("where: column is not allowed: password\nwhere: column is not allowed: 1=1 OR name"))
+
+	err = where.Validate(where.Wrap(pair{a: nameIsFred, b: tagsContain{1}}), allowed)
+	g.Expect(err).To(MatchError("tagsContain requires two values"))
+}
+
+func TestConstraint_Validate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	allowed := []string{"name", "age"}
+
+	g.Expect(where.OrderBy("name").Desc().OrderBy("age").Validate(allowed)).To(Succeed())
+	g.Expect(where.Limit(10).Validate(allowed)).To(Succeed())
+
+	var qc *where.Constraint
+	g.Expect(qc.Validate(allowed)).To(Succeed())
+
+	err := where.OrderBy("name", "salary").Validate(allowed)
+	g.Expect(err).To(MatchError(where.ErrColumnNotAllowed))
+	g.Expect(err.Error()).To(Equal("where: column is not allowed: salary"))
+}
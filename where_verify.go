@@ -0,0 +1,146 @@
+package where
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// Issue describes a feature used by an expression or query constraint that a dialect
+// cannot execute.
+type Issue struct {
+	// Node is the node containing the feature; it is nil for query constraints.
+	Node Node
+
+	// Feature names the unsupported SQL feature, e.g. "ILIKE".
+	Feature string
+
+	// Dialect is the dialect that does not support the feature.
+	Dialect dialect.Dialect
+}
+
+func (i Issue) String() string {
+	if i.Node == nil {
+		return fmt.Sprintf("%s is not supported by %s", i.Feature, i.Dialect)
+	}
+	return fmt.Sprintf("%s is not supported by %s in %s", i.Feature, i.Dialect, i.Node)
+}
+
+type dialectRule struct {
+	feature   string
+	pattern   *regexp.Regexp
+	supported []dialect.Dialect
+}
+
+var dialectRules = []dialectRule{
+	{"ILIKE", regexp.MustCompile(`\bILIKE\b`), []dialect.Dialect{dialect.Postgres}},
+	{"SIMILAR TO", regexp.MustCompile(`\bSIMILAR\s+TO\b`), []dialect.Dialect{dialect.Postgres}},
+	{"BETWEEN SYMMETRIC", regexp.MustCompile(`\bBETWEEN\s+SYMMETRIC\b`), []dialect.Dialect{dialect.Postgres}},
+	{"ARRAY[...]", regexp.MustCompile(`\bARRAY\s*\[`), []dialect.Dialect{dialect.Postgres}},
+	{"ANY/ALL array", regexp.MustCompile(`\b(ANY|ALL)\s*\(\s*\?\s*\)`), []dialect.Dialect{dialect.Postgres}},
+	{"array operator", regexp.MustCompile(`@>|<@|&&`), []dialect.Dialect{dialect.Postgres}},
+	{"inet operator", regexp.MustCompile(`<<|(?:^|[^-])>>`), []dialect.Dialect{dialect.Postgres}},
+	{":: cast", regexp.MustCompile(`::`), []dialect.Dialect{dialect.Postgres}},
+	{"REGEXP", regexp.MustCompile(`\bREGEXP\b`), []dialect.Dialect{dialect.Mysql, dialect.MariaDB, dialect.Sqlite}},
+	{"<=>", regexp.MustCompile(`<=>`), []dialect.Dialect{dialect.Mysql, dialect.MariaDB}},
+	{"IS DISTINCT FROM", regexp.MustCompile(`\bIS\s+(NOT\s+)?DISTINCT\s+FROM\b`), []dialect.Dialect{dialect.Postgres, dialect.Sqlite, dialect.SqlServer, dialect.DB2, dialect.Firebird}},
+	{"JSON_CONTAINS", regexp.MustCompile(`\bJSON_CONTAINS\s*\(`), []dialect.Dialect{dialect.Mysql, dialect.MariaDB}},
+	{"TRUE/FALSE literal", regexp.MustCompile(`\b(TRUE|FALSE)\b`), []dialect.Dialect{dialect.Mysql, dialect.MariaDB, dialect.Postgres, dialect.Sqlite, dialect.DB2, dialect.Firebird}},
+}
+
+// VerifyDialectSupport walks an expression tree (see Walk) and reports the predicates that
+// dialect d cannot execute, such as ILIKE on MySQL. This allows products that support several
+// databases to detect problems in their tests, rather than in production.
+//
+// The checks are based on the SQL syntax in each predicate, ignoring quoted strings and
+// identifiers; they are not exhaustive. Custom nodes are checked by formatting them for
+// dialect d unless they implement Composite, in which case their children are checked instead.
+// The result is empty if no problems are found.
+func VerifyDialectSupport(wh Node, d dialect.Dialect) []Issue {
+	var issues []Issue
+
+	c := config{FormatConfig: dialect.ConfigFor(d)}
+
+	Walk(wh, func(n Node) bool {
+		var sql string
+		switch x := n.(type) {
+		case Condition:
+			sql = c.adapt(x).Predicate
+		case Clause, not, Composite:
+			return true
+		default:
+			sql, _ = formatNode(n, c)
+		}
+
+		sql = strings.ToUpper(stripQuoted(sql))
+		for _, rule := range dialectRules {
+			if !supportedBy(rule.supported, d) && rule.pattern.MatchString(sql) {
+				issues = append(issues, Issue{Node: n, Feature: rule.feature, Dialect: d})
+			}
+		}
+		return true
+	})
+
+	return issues
+}
+
+// VerifyDialectSupport reports the parts of the query constraint that dialect d cannot
+// execute, such as NULLS FIRST on SQL-Server. The result is empty if no problems are found.
+func (qc *Constraint) VerifyDialectSupport(d dialect.Dialect) []Issue {
+	if qc == nil {
+		return nil
+	}
+
+	var issues []Issue
+
+	if qc.nulls != unset && len(qc.orderBy) > 0 && !d.SupportsNullsOrdering() {
+		feature := "NULLS FIRST"
+		if qc.nulls == last {
+			feature = "NULLS LAST"
+		}
+		issues = append(issues, Issue{Feature: feature, Dialect: d})
+	}
+
+	if qc.offset > 0 && qc.limit > 0 && d.LimitStyle() == dialect.Top {
+		issues = append(issues, Issue{Feature: "TOP with OFFSET", Dialect: d})
+	}
+
+	if qc.offset > 0 && qc.limit == 0 && (d == dialect.Mysql || d == dialect.MariaDB) {
+		issues = append(issues, Issue{Feature: "OFFSET without LIMIT", Dialect: d})
+	}
+
+	return issues
+}
+
+func supportedBy(supported []dialect.Dialect, d dialect.Dialect) bool {
+	for _, s := range supported {
+		if s == d {
+			return true
+		}
+	}
+	return false
+}
+
+// stripQuoted removes the content of quoted strings and identifiers.
+func stripQuoted(sql string) string {
+	buf := &strings.Builder{}
+	buf.Grow(len(sql))
+	var quote rune
+	for _, r := range sql {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				buf.WriteRune(r)
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+			buf.WriteRune(r)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,53 @@
+package where_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+func TestVerifyDialectSupport(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ilike := where.Literal("name", " NOT ILIKE ?", "f%")
+	distinct := where.Literal("a", " IS DISTINCT FROM ?", 1)
+	quoted := where.Literal("name", " = 'ILIKE'")
+	wh := where.And(nameIsFred, where.Not(ilike.Or(distinct)), quoted, where.Wrap(tagsContain{"a", "b"}))
+
+	g.Expect(where.VerifyDialectSupport(wh, dialect.Postgres)).To(BeEmpty())
+
+	issues := where.VerifyDialectSupport(wh, dialect.Mysql)
+	g.Expect(issues).To(HaveLen(4))
+	g.Expect(issues[0]).To(Equal(where.Issue{Node: ilike, Feature: "ILIKE", Dialect: dialect.Mysql}))
+	g.Expect(issues[0].String()).To(Equal(`ILIKE is not supported by Mysql in name NOT ILIKE 'f%'`))
+	g.Expect(issues[1].Feature).To(Equal("IS DISTINCT FROM"))
+	g.Expect(issues[2].Feature).To(Equal("ARRAY[...]"))
+	g.Expect(issues[3].Feature).To(Equal("array operator"))
+
+	issues = where.VerifyDialectSupport(where.Predicate("active = TRUE"), dialect.SqlServer)
+	g.Expect(issues).To(HaveLen(1))
+	g.Expect(issues[0].Feature).To(Equal("TRUE/FALSE literal"))
+
+	g.Expect(where.VerifyDialectSupport(nil, dialect.SqlServer)).To(BeEmpty())
+
+	// adapted for the dialect
+	g.Expect(where.VerifyDialectSupport(where.ILike("name", "f%"), dialect.Mysql)).To(BeEmpty())
+	g.Expect(where.VerifyDialectSupport(where.EqNullSafe("a", 1), dialect.Mysql)).To(BeEmpty())
+}
+
+func TestConstraint_VerifyDialectSupport(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	qc := where.OrderBy("name").NullsFirst().Limit(10).Offset(20)
+
+	g.Expect(qc.VerifyDialectSupport(dialect.Postgres)).To(BeEmpty())
+	g.Expect(qc.VerifyDialectSupport(dialect.Mysql)).To(Equal([]where.Issue{{Feature: "NULLS FIRST", Dialect: dialect.Mysql}}))
+
+	issues := qc.VerifyDialectSupport(dialect.SqlServer)
+	g.Expect(issues).To(HaveLen(2))
+	g.Expect(issues[1].String()).To(Equal(`TOP with OFFSET is not supported by SqlServer`))
+
+	g.Expect(where.Offset(5).VerifyDialectSupport(dialect.Mysql)[0].Feature).To(Equal("OFFSET without LIMIT"))
+}
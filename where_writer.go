@@ -0,0 +1,107 @@
+package where
+
+import (
+	"io"
+
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// WriteWhere writes the SQL clause beginning "WHERE ..." to w, as for Where, returning the
+// arguments. This allows a large query to be assembled in one buffer. The expression is
+// still formatted as a string with '?' placeholders, but the placeholders are replaced as
+// it is written to w, which saves the copies made by Where and by joining its result to
+// the rest of the query. Many small writes are made, so w should be buffered, e.g. a
+// strings.Builder or a bufio.Writer. Nothing is written if the expression is empty or nil.
+func WriteWhere(w io.Writer, wh Node, option ...dialect.FormatOption) ([]any, error) {
+	return writeNode(w, whereConjunction, wh, option)
+}
+
+// WriteHaving writes the SQL clause beginning "HAVING ..." to w, as for Having, returning
+// the arguments. Nothing is written if the expression is empty or nil.
+func WriteHaving(w io.Writer, wh Node, option ...dialect.FormatOption) ([]any, error) {
+	return writeNode(w, havingConjunction, wh, option)
+}
+
+// WriteExpression writes an expression to w, as for its Format method, returning the
+// arguments. Nothing is written if the expression is empty or nil.
+//
+// This is a function rather than a method of Expression because adding a method to the
+// Expression and Node interfaces would break their implementations outside this package.
+func WriteExpression(w io.Writer, wh Node, option ...dialect.FormatOption) ([]any, error) {
+	return writeNode(w, "", wh, option)
+}
+
+// WriteSQL writes the query constraint to w, as for FormatArgs, returning the arguments.
+// Nothing is written if the query constraint is nil or empty.
+func (qc *Constraint) WriteSQL(w io.Writer, d dialect.Dialect, option ...dialect.FormatOption) ([]any, error) {
+	if qc == nil {
+		return nil, nil
+	}
+
+	c := newConfig(dialect.FormatConfig{Dialect: d}, option)
+	s, args := qc.compose(c)
+	if s == "" {
+		return nil, nil
+	}
+
+	sw := &sqlWriter{w: w}
+	args = writePlaceholders(sw, s, args, c, c.PlaceholderOffset+1)
+	if sw.err != nil {
+		return nil, sw.err
+	}
+	return args, nil
+}
+
+// writeNode writes an expression, preceded by the conjunction and followed by any comment
+// unless the conjunction is blank. Only the replacement of the placeholders is streamed;
+// custom nodes at the top are written as given by their Format method.
+func writeNode(w io.Writer, conjunction string, wh Node, option []dialect.FormatOption) ([]any, error) {
+	if wh == nil {
+		return nil, nil
+	}
+
+	c := newConfig(dialect.FormatConfig{}, option)
+
+	f, isFormatter := wh.(formatter)
+	var s string
+	var args []any
+	if isFormatter {
+		s, args = f.doFormat(c)
+	} else {
+		s, args = wh.Format(c.FormatConfig) // placeholders already replaced
+	}
+	if s == "" {
+		return nil, nil
+	}
+
+	sw := &sqlWriter{w: w}
+	if conjunction != "" {
+		sw.WriteString(c.keyword(conjunction))
+	}
+	if isFormatter {
+		args = writePlaceholders(sw, s, args, c, c.PlaceholderOffset+1)
+	} else {
+		sw.WriteString(s)
+	}
+	if conjunction != "" {
+		sw.WriteString(c.comment())
+	}
+
+	if sw.err != nil {
+		return nil, sw.err
+	}
+	return args, nil
+}
+
+// sqlWriter writes SQL to an io.Writer, keeping the first error so that the formatting
+// code need not check every write.
+type sqlWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (sw *sqlWriter) WriteString(s string) {
+	if sw.err == nil && s != "" {
+		_, sw.err = io.WriteString(sw.w, s)
+	}
+}
@@ -0,0 +1,81 @@
+package where_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriteWhere(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Eq("name", "Fred").And(where.Gt("age", 10))
+
+	buf := &strings.Builder{}
+	buf.WriteString("SELECT * FROM users")
+
+	args, err := where.WriteWhere(buf, wh, dialect.Dollar)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]any{"Fred", 10}))
+
+	_, err = where.OrderBy("name").Limit(10).WriteSQL(buf, dialect.Postgres)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(buf.String()).To(Equal(`SELECT * FROM users WHERE name=$1 AND age>$2 ORDER BY name LIMIT 10`))
+
+	buf.Reset()
+	args, err = where.WriteHaving(buf, where.CountGt("*", 1))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal([]any{1}))
+	g.Expect(buf.String()).To(Equal(` HAVING COUNT(*)>?`))
+
+	buf.Reset()
+	args, err = where.WriteExpression(buf, wh, dialect.AtP)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(HaveLen(2))
+	g.Expect(buf.String()).To(Equal(`name=@p1 AND age>@p2`))
+
+	buf.Reset()
+	args, err = where.WriteWhere(buf, where.NoOp())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(BeNil())
+	g.Expect(buf.Len()).To(BeZero())
+
+	_, err = where.WriteWhere(failingWriter{}, wh)
+	g.Expect(err).To(MatchError("disk full"))
+}
+
+func TestWriteWhere_matchesWhere(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh := where.Eq("name", "Fred").And(where.In("age", 10, 11)).And(where.Wrap(tagsContain{"a", "b"}))
+
+	for _, opts := range [][]dialect.FormatOption{
+		{dialect.Query},
+		{dialect.Dollar, where.WithPlaceholderOffset(2)},
+		{dialect.AtP},
+		{dialect.Named},
+		{dialect.Inline},
+		{dialect.Postgres, dialect.FormatConfig{Comment: "/* report */"}},
+	} {
+		expSQL, expArgs := where.Where(wh, opts...)
+		buf := &strings.Builder{}
+		args, err := where.WriteWhere(buf, wh, opts...)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(buf.String()).To(Equal(expSQL))
+		g.Expect(args).To(Equal(expArgs))
+	}
+
+	buf := &strings.Builder{}
+	args, err := where.WriteWhere(buf, tagsContain{"a", "b"}, dialect.Dollar)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(buf.String()).To(Equal(` WHERE tags @> ARRAY[$1,$2]`))
+	g.Expect(args).To(Equal([]any{"a", "b"}))
+}
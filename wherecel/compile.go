@@ -0,0 +1,441 @@
+// Package wherecel compiles a restricted subset of CEL (the Common Expression Language)
+// into where-expressions. This allows policy-style filters, such as
+//
+//	status == 'open' && (age > 18 || guardian != null)
+//
+// to be stored as strings and then executed as SQL. Only declared columns may be used,
+// and every value is bound as an argument, so the filter cannot alter the structure of
+// the SQL.
+//
+// The subset comprises:
+//   - comparisons between a column and a literal using ==, !=, <, <=, > and >=;
+//   - comparisons with null using == and !=;
+//   - membership tests of the form column in [literal, ...];
+//   - the string methods startsWith, endsWith and contains, e.g. name.startsWith('F');
+//   - boolean columns, which may be used alone, e.g. active && !deleted;
+//   - the logical operators &&, || and !, with parentheses for grouping.
+//
+// Literals are strings (in single or double quotes), integers, decimal numbers, true,
+// false and null.
+package wherecel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rickb777/where/v2"
+)
+
+// Type is the declared type of a column.
+type Type int
+
+// These are the column types.
+const (
+	String Type = iota + 1
+	Int
+	Double
+	Bool
+)
+
+func (t Type) String() string {
+	switch t {
+	case String:
+		return "string"
+	case Int:
+		return "int"
+	case Double:
+		return "double"
+	case Bool:
+		return "bool"
+	}
+	return "unknown"
+}
+
+// Env declares the columns that may be used in expressions, along with their types.
+type Env map[string]Type
+
+// Error reports a problem with an expression.
+type Error struct {
+	// Pos is the byte offset of the problem within the expression.
+	Pos int
+
+	// Msg describes the problem.
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("wherecel: at %d: %s", e.Pos, e.Msg)
+}
+
+// Compile compiles an expression into a where-expression. Each column must be declared
+// in the environment and each literal must suit the column's type; integers are
+// acceptable for double columns. The error is an *Error.
+func (env Env) Compile(src string) (where.Expression, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{env: env, tokens: tokens}
+	exp, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != eof {
+		return nil, p.unexpected(t)
+	}
+	return exp, nil
+}
+
+//-------------------------------------------------------------------------------------------------
+
+type parser struct {
+	env    Env
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != eof {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) accept(punctuation string) bool {
+	if t := p.peek(); t.kind == punct && t.text == punctuation {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punctuation string) error {
+	if !p.accept(punctuation) {
+		return &Error{Pos: p.peek().pos, Msg: fmt.Sprintf("expected %q", punctuation)}
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	if t.kind == eof {
+		return &Error{Pos: t.pos, Msg: "unexpected end of expression"}
+	}
+	return &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.text)}
+}
+
+func (p *parser) or() (where.Expression, error) {
+	return p.binary("||", p.and, where.Or)
+}
+
+func (p *parser) and() (where.Expression, error) {
+	return p.binary("&&", p.unary, where.And)
+}
+
+func (p *parser) binary(op string, operand func() (where.Expression, error), combine func(...where.Node) where.Expression) (where.Expression, error) {
+	exp, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []where.Node{exp}
+	for p.accept(op) {
+		exp, err = operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, exp)
+	}
+
+	if len(operands) == 1 {
+		return exp, nil
+	}
+	return combine(operands...), nil
+}
+
+func (p *parser) unary() (where.Expression, error) {
+	if p.accept("!") {
+		exp, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return where.Not(exp), nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (where.Expression, error) {
+	if p.accept("(") {
+		exp, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return exp, p.expect(")")
+	}
+
+	t := p.peek()
+	if t.kind == ident && !isKeyword(t.text) {
+		return p.columnTest()
+	}
+
+	// a literal on the left, e.g. 18 < age
+	left, err := p.literal()
+	if err != nil {
+		return nil, err
+	}
+	op := p.next()
+	if !isComparison(op) {
+		return nil, p.unexpected(op)
+	}
+	if c := p.peek(); c.kind != ident || isKeyword(c.text) {
+		return nil, &Error{Pos: c.pos, Msg: "expected a column"}
+	}
+	column, typ, err := p.column()
+	if err != nil {
+		return nil, err
+	}
+	return comparison(column, typ, reversed[op.text], left)
+}
+
+// columnTest parses a condition that starts with a column.
+func (p *parser) columnTest() (where.Expression, error) {
+	column, typ, err := p.column()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	switch {
+	case isComparison(t):
+		p.next()
+		right, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		return comparison(column, typ, t.text, right)
+
+	case t.kind == ident && t.text == "in":
+		p.next()
+		return p.in(column, typ)
+
+	case t.kind == punct && t.text == ".":
+		p.next()
+		return p.method(column, typ)
+	}
+
+	if typ.typ != Bool {
+		return nil, &Error{Pos: typ.pos, Msg: fmt.Sprintf("%s is not a bool column", column)}
+	}
+	return where.Eq(column, true), nil
+}
+
+// columnType notes the type of a column and where it was used.
+type columnType struct {
+	typ Type
+	pos int
+}
+
+// column parses a column name, which may be qualified, e.g. "p.name".
+func (p *parser) column() (string, columnType, error) {
+	start := p.next()
+	name := start.text
+
+	// the qualified name is preferred if it is declared
+	for p.i+1 < len(p.tokens) && p.peek().text == "." && p.tokens[p.i+1].kind == ident {
+		qualified := name + "." + p.tokens[p.i+1].text
+		if _, ok := p.env[qualified]; !ok {
+			break
+		}
+		name = qualified
+		p.i += 2
+	}
+
+	typ, ok := p.env[name]
+	if !ok {
+		return "", columnType{}, &Error{Pos: start.pos, Msg: fmt.Sprintf("undeclared column %s", name)}
+	}
+	return name, columnType{typ: typ, pos: start.pos}, nil
+}
+
+func (p *parser) in(column string, typ columnType) (where.Expression, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+
+	var values []any
+	for !p.accept("]") {
+		if len(values) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		v, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		value, err := typ.check(column, v)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	if len(values) == 0 {
+		return nil, &Error{Pos: typ.pos, Msg: "empty list"}
+	}
+	return where.In(column, values...), nil
+}
+
+// likeEscape escapes the LIKE wildcards in a value, using '!' as the escape character.
+var likeEscape = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func (p *parser) method(column string, typ columnType) (where.Expression, error) {
+	name := p.next()
+	if name.kind != ident {
+		return nil, p.unexpected(name)
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	arg, err := p.literal()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	if typ.typ != String {
+		return nil, &Error{Pos: name.pos, Msg: fmt.Sprintf("%s requires a string column", name.text)}
+	}
+	s, ok := arg.value.(string)
+	if !ok {
+		return nil, &Error{Pos: arg.pos, Msg: fmt.Sprintf("%s requires a string argument", name.text)}
+	}
+
+	var pattern string
+	switch name.text {
+	case "startsWith":
+		pattern = likeEscape.Replace(s) + "%"
+	case "endsWith":
+		pattern = "%" + likeEscape.Replace(s)
+	case "contains":
+		pattern = "%" + likeEscape.Replace(s) + "%"
+	default:
+		return nil, &Error{Pos: name.pos, Msg: fmt.Sprintf("unsupported method %s", name.text)}
+	}
+	return where.Literal(column, " LIKE ? ESCAPE '!'", pattern), nil
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// literalValue is a literal along with its position.
+type literalValue struct {
+	value any // nil for null
+	pos   int
+}
+
+func (p *parser) literal() (literalValue, error) {
+	t := p.next()
+	switch t.kind {
+	case stringLit:
+		return literalValue{value: t.text, pos: t.pos}, nil
+	case intLit:
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return literalValue{}, &Error{Pos: t.pos, Msg: "integer out of range"}
+		}
+		return literalValue{value: n, pos: t.pos}, nil
+	case floatLit:
+		f, _ := strconv.ParseFloat(t.text, 64)
+		return literalValue{value: f, pos: t.pos}, nil
+	case ident:
+		switch t.text {
+		case "true":
+			return literalValue{value: true, pos: t.pos}, nil
+		case "false":
+			return literalValue{value: false, pos: t.pos}, nil
+		case "null":
+			return literalValue{pos: t.pos}, nil
+		}
+	}
+	if t.kind == eof {
+		return literalValue{}, p.unexpected(t)
+	}
+	return literalValue{}, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a literal but got %q", t.text)}
+}
+
+// check verifies that a literal suits the column type, converting it if necessary.
+func (typ columnType) check(column string, v literalValue) (any, error) {
+	switch x := v.value.(type) {
+	case string:
+		if typ.typ == String {
+			return x, nil
+		}
+	case int64:
+		switch typ.typ {
+		case Int:
+			return x, nil
+		case Double:
+			return float64(x), nil
+		}
+	case float64:
+		if typ.typ == Double {
+			return x, nil
+		}
+	case bool:
+		if typ.typ == Bool {
+			return x, nil
+		}
+	case nil:
+		return nil, &Error{Pos: v.pos, Msg: "null can only be compared using == or !="}
+	}
+	return nil, &Error{Pos: v.pos, Msg: fmt.Sprintf("%s requires a value of type %s", column, typ.typ)}
+}
+
+func comparison(column string, typ columnType, op string, v literalValue) (where.Expression, error) {
+	if v.value == nil {
+		switch op {
+		case "==":
+			return where.Null(column), nil
+		case "!=":
+			return where.NotNull(column), nil
+		}
+	}
+
+	value, err := typ.check(column, v)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "==":
+		return where.Eq(column, value), nil
+	case "!=":
+		return where.NotEq(column, value), nil
+	case "<":
+		return where.Lt(column, value), nil
+	case "<=":
+		return where.LtEq(column, value), nil
+	case ">":
+		return where.Gt(column, value), nil
+	}
+	return where.GtEq(column, value), nil
+}
+
+var reversed = map[string]string{"==": "==", "!=": "!=", "<": ">", "<=": ">=", ">": "<", ">=": "<="}
+
+func isComparison(t token) bool {
+	_, ok := reversed[t.text]
+	return t.kind == punct && ok
+}
+
+func isKeyword(s string) bool {
+	return s == "true" || s == "false" || s == "null" || s == "in"
+}
@@ -0,0 +1,81 @@
+package wherecel_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/wherecel"
+)
+
+var env = wherecel.Env{
+	"status":   wherecel.String,
+	"name":     wherecel.String,
+	"p.name":   wherecel.String,
+	"age":      wherecel.Int,
+	"score":    wherecel.Double,
+	"active":   wherecel.Bool,
+	"guardian": wherecel.String,
+}
+
+func TestCompile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]string{
+		`status == 'open' && age > 18`:                       `status='open' AND age>18`,
+		`status == "open" && (age > 18 || guardian != null)`: `status='open' AND (age>18 OR guardian IS NOT NULL)`,
+		`18 <= age && age < 65`:                              `age>=18 AND age<65`,
+		`score >= 1 || score < -2.5`:                         `score>=1 OR score<-2.5`,
+		`active && !(guardian == null)`:                      `active=true AND (NOT guardian IS NULL)`,
+		`!active`:                                            `NOT active=true`,
+		`status in ['open', 'held']`:                         `status IN ('open','held')`,
+		`name.startsWith('F_1%')`:                            `name LIKE 'F!_1!%%' ESCAPE '!'`,
+		`name.endsWith('d') || p.name.contains('x')`:         `name LIKE '%d' ESCAPE '!' OR p.name LIKE '%x%' ESCAPE '!'`,
+		`name == 'it\'s'`:                                    `name='it''s'`,
+		`age != 3 && active == false`:                        `age<>3 AND active=false`,
+	}
+
+	for src, exp := range cases {
+		wh, err := env.Compile(src)
+		g.Expect(err).NotTo(HaveOccurred(), src)
+		g.Expect(wh.String()).To(Equal(exp), src)
+	}
+}
+
+func TestCompile_args(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wh, err := env.Compile(`status == 'open' && score > 2`)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	s, args := where.Where(wh, dialect.Dollar)
+	g.Expect(s).To(Equal(` WHERE status=$1 AND score>$2`))
+	g.Expect(args).To(Equal([]any{"open", float64(2)}))
+}
+
+func TestCompile_errors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]string{
+		`size == 1`:           `wherecel: at 0: undeclared column size`,
+		`age == 'x'`:          `wherecel: at 7: age requires a value of type int`,
+		`age > null`:          `wherecel: at 6: null can only be compared using == or !=`,
+		`status`:              `wherecel: at 0: status is not a bool column`,
+		`status == 'open' &&`: `wherecel: at 19: unexpected end of expression`,
+		`(age > 1`:            `wherecel: at 8: expected ")"`,
+		`age > 1 age`:         `wherecel: at 8: unexpected "age"`,
+		`age.startsWith('1')`: `wherecel: at 4: startsWith requires a string column`,
+		`name.matches('x')`:   `wherecel: at 5: unsupported method matches`,
+		`status in []`:        `wherecel: at 0: empty list`,
+		`name == 'x`:          `wherecel: at 8: unterminated string`,
+		`name == 1 + 2`:       `wherecel: at 10: unexpected '+'`,
+		`1 == 2`:              `wherecel: at 5: expected a column`,
+		`name == status`:      `wherecel: at 8: expected a literal but got "status"`,
+	}
+
+	for src, exp := range cases {
+		_, err := env.Compile(src)
+		g.Expect(err).To(MatchError(exp), src)
+	}
+}
@@ -0,0 +1,118 @@
+package wherecel
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	eof tokenKind = iota
+	ident
+	intLit
+	floatLit
+	stringLit
+	punct
+)
+
+type token struct {
+	kind tokenKind
+	text string // the literal value for strings, otherwise the source text
+	pos  int
+}
+
+// punctuation lists the operators and delimiters, longest first.
+var punctuation = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(src) {
+				r, size = utf8.DecodeRuneInString(src[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: ident, text: src[start:i], pos: start})
+
+		case '0' <= r && r <= '9' || r == '-' && i+1 < len(src) && '0' <= src[i+1] && src[i+1] <= '9':
+			start := i
+			i++ // the first digit or the sign
+			kind := intLit
+			for i < len(src) && ('0' <= src[i] && src[i] <= '9' || src[i] == '.' && kind == intLit && i+1 < len(src) && '0' <= src[i+1] && src[i+1] <= '9') {
+				if src[i] == '.' {
+					kind = floatLit
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: kind, text: src[start:i], pos: start})
+
+		case r == '\'' || r == '"':
+			s, end, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: stringLit, text: s, pos: i})
+			i = end
+
+		default:
+			matched := false
+			for _, p := range punctuation {
+				if strings.HasPrefix(src[i:], p) {
+					tokens = append(tokens, token{kind: punct, text: p, pos: i})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &Error{Pos: i, Msg: fmt.Sprintf("unexpected %q", r)}
+			}
+		}
+	}
+
+	return append(tokens, token{kind: eof, pos: len(src)}), nil
+}
+
+// lexString reads a quoted string starting at i, returning its value and the index after it.
+func lexString(src string, i int) (string, int, error) {
+	quote := src[i]
+	buf := &strings.Builder{}
+	for j := i + 1; j < len(src); j++ {
+		switch ch := src[j]; ch {
+		case quote:
+			return buf.String(), j + 1, nil
+		case '\\':
+			j++
+			if j >= len(src) {
+				break
+			}
+			switch src[j] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 't':
+				buf.WriteByte('\t')
+			case '\\', '\'', '"':
+				buf.WriteByte(src[j])
+			default:
+				return "", 0, &Error{Pos: j - 1, Msg: fmt.Sprintf("unsupported escape \\%c", src[j])}
+			}
+		default:
+			buf.WriteByte(ch)
+		}
+	}
+	return "", 0, &Error{Pos: i, Msg: "unterminated string"}
+}
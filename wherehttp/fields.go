@@ -0,0 +1,201 @@
+// Package wherehttp connects where-expressions with HTTP requests. Clients filter and
+// sort collections using query parameters such as
+//
+//	?filter=age>=18&filter=name~F%25&sort=-age,name&limit=20&offset=40
+//
+// Only the columns listed in a Fields allow-list can be used, each with its own type
+// and permitted operators. The same list generates the OpenAPI description of the
+// parameters, so the API documentation stays in step with what is actually accepted.
+//
+// Fields.Middleware parses these parameters for every request, storing the resulting
+// Query in the request context; invalid requests are rejected with status 400.
+package wherehttp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rickb777/where/v2"
+)
+
+// These are the names of the query parameters.
+const (
+	FilterParam = "filter"
+	SortParam   = "sort"
+	LimitParam  = "limit"
+	OffsetParam = "offset"
+)
+
+// Type is the type of the values of a field.
+type Type string
+
+// These are the field types, named as in JSON schema.
+const (
+	String  Type = "string"
+	Integer Type = "integer"
+	Number  Type = "number"
+	Boolean Type = "boolean"
+)
+
+// Operators lists all the filter operators: "~" is LIKE and the others are the usual
+// SQL comparisons, except that "!=" is "<>".
+var Operators = []string{"=", "!=", ">", ">=", "<", "<=", "~"}
+
+// Field describes a column that clients may use in filter and sort parameters.
+type Field struct {
+	// Column is the name of the column, as used by clients and in the SQL.
+	Column string
+
+	// Type is the type of the values; if blank, String is used.
+	Type Type
+
+	// Operators lists the filter operators permitted for the field (see Operators).
+	// If nil, all of them are permitted, except "~" for non-string fields.
+	Operators []string
+
+	// Sortable allows results to be sorted by the field.
+	Sortable bool
+
+	// Description, if not blank, is included in the generated documentation.
+	Description string
+}
+
+// Fields is an allow-list of the columns that clients may use in filter and sort parameters.
+type Fields []Field
+
+// Lookup finds the field for a column, if it is in the allow-list.
+func (fs Fields) Lookup(column string) (Field, bool) {
+	for _, f := range fs {
+		if f.Column == column {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// operators gets the operators permitted for the field.
+func (f Field) operators() []string {
+	if f.Operators != nil {
+		return f.Operators
+	}
+	if f.typ() == String {
+		return Operators
+	}
+	return Operators[:len(Operators)-1]
+}
+
+func (f Field) typ() Type {
+	if f.Type == "" {
+		return String
+	}
+	return f.Type
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Error reports a query parameter that is not acceptable.
+type Error struct {
+	// Param is the name of the query parameter, e.g. "filter".
+	Param string `json:"param"`
+
+	// Value is the offending value.
+	Value string `json:"value"`
+
+	// Reason explains the problem.
+	Reason string `json:"reason"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("wherehttp: %s %q: %s", e.Param, e.Value, e.Reason)
+}
+
+var filterSyntax = regexp.MustCompile(`^([\pL_][\pL\pN_]*(?:\.[\pL_][\pL\pN_]*)*)(!=|>=|<=|=|>|<|~)(.*)$`)
+
+// ParseFilter converts the value of one filter parameter, such as "age>=18", into a
+// condition. The column must be in the allow-list and the operator must be permitted
+// for it. The value is converted according to the type of the field and is bound as
+// an argument, so it cannot alter the structure of the SQL.
+//
+// The error is an *Error.
+func (fs Fields) ParseFilter(s string) (where.Expression, error) {
+	m := filterSyntax.FindStringSubmatch(s)
+	if m == nil {
+		return nil, &Error{Param: FilterParam, Value: s, Reason: "expected column, operator and value"}
+	}
+
+	column, op, text := m[1], m[2], m[3]
+	f, ok := fs.Lookup(column)
+	if !ok {
+		return nil, &Error{Param: FilterParam, Value: s, Reason: "unknown column " + column}
+	}
+	if !slices.Contains(f.operators(), op) {
+		return nil, &Error{Param: FilterParam, Value: s, Reason: "operator " + op + " is not permitted for " + column}
+	}
+
+	value, err := f.convert(text)
+	if err != nil {
+		return nil, &Error{Param: FilterParam, Value: s, Reason: fmt.Sprintf("%s requires %s %s value", column, article(f.typ()), f.typ())}
+	}
+
+	switch op {
+	case "=":
+		return where.Eq(column, value), nil
+	case "!=":
+		return where.NotEq(column, value), nil
+	case ">":
+		return where.Gt(column, value), nil
+	case ">=":
+		return where.GtEq(column, value), nil
+	case "<":
+		return where.Lt(column, value), nil
+	case "<=":
+		return where.LtEq(column, value), nil
+	}
+	return where.Like(column, text), nil // ~
+}
+
+// valuePatterns give the syntax of the values of each type. These are used both for
+// parsing and in the generated documentation.
+var valuePatterns = map[Type]string{
+	String:  `.*`,
+	Integer: `-?[0-9]+`,
+	Number:  `-?[0-9]+(\.[0-9]+)?`,
+	Boolean: `true|false`,
+}
+
+var valueSyntax = map[Type]*regexp.Regexp{
+	String:  regexp.MustCompile(`^(?:` + valuePatterns[String] + `)$`),
+	Integer: regexp.MustCompile(`^(?:` + valuePatterns[Integer] + `)$`),
+	Number:  regexp.MustCompile(`^(?:` + valuePatterns[Number] + `)$`),
+	Boolean: regexp.MustCompile(`^(?:` + valuePatterns[Boolean] + `)$`),
+}
+
+func (f Field) convert(text string) (any, error) {
+	syntax, ok := valueSyntax[f.typ()]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s", f.typ())
+	}
+	if !syntax.MatchString(text) {
+		return nil, strconv.ErrSyntax
+	}
+
+	switch f.typ() {
+	case Integer:
+		return strconv.ParseInt(text, 10, 64)
+	case Number:
+		return strconv.ParseFloat(text, 64)
+	case Boolean:
+		return strconv.ParseBool(text)
+	}
+	return text, nil
+}
+
+func article(t Type) string {
+	if strings.IndexByte("aeiou", t[0]) >= 0 {
+		return "an"
+	}
+	return "a"
+}
@@ -0,0 +1,92 @@
+package wherehttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2/wherehttp"
+)
+
+var fields = wherehttp.Fields{
+	{Column: "name", Sortable: true},
+	{Column: "age", Type: wherehttp.Integer, Sortable: true},
+	{Column: "score", Type: wherehttp.Number, Operators: []string{">", "<"}},
+	{Column: "active", Type: wherehttp.Boolean, Operators: []string{"="}},
+}
+
+func TestParseFilter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]string{
+		"name=Fred":     `name='Fred'`,
+		"name~F%":       `name LIKE 'F%'`,
+		"name>=10":      `name>='10'`,
+		"age!=-3":       `age<>-3`,
+		"age<=18":       `age<=18`,
+		"score>0.5":     `score>0.5`,
+		"active=true":   `active=true`,
+		"name=a=b c":    `name='a=b c'`,
+		"name=":         `name=''`,
+		"age>=12345678": `age>=12345678`,
+	}
+
+	for s, exp := range cases {
+		wh, err := fields.ParseFilter(s)
+		g.Expect(err).NotTo(HaveOccurred(), s)
+		g.Expect(wh.String()).To(Equal(exp), s)
+	}
+}
+
+func TestParseFilter_errors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]string{
+		"name":        `wherehttp: filter "name": expected column, operator and value`,
+		"size=1":      `wherehttp: filter "size=1": unknown column size`,
+		"age~1":       `wherehttp: filter "age~1": operator ~ is not permitted for age`,
+		"score=1":     `wherehttp: filter "score=1": operator = is not permitted for score`,
+		"age=x":       `wherehttp: filter "age=x": age requires an integer value`,
+		"score>1e3":   `wherehttp: filter "score>1e3": score requires a number value`,
+		"active=TRUE": `wherehttp: filter "active=TRUE": active requires a boolean value`,
+	}
+
+	for s, exp := range cases {
+		_, err := fields.ParseFilter(s)
+		g.Expect(err).To(MatchError(exp), s)
+
+		var e *wherehttp.Error
+		g.Expect(errors.As(err, &e)).To(BeTrue())
+		g.Expect(e.Param).To(Equal("filter"))
+		g.Expect(e.Value).To(Equal(s))
+	}
+}
+
+func TestParameters(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	params := fields.Parameters()
+	g.Expect(params).To(HaveLen(4))
+
+	b, err := json.Marshal(params[1:])
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(b)).To(Equal(`[` +
+		`{"name":"sort","in":"query","description":"Comma-separated columns by which to sort; prefix a column with '-' for descending order.","schema":{"type":"string","pattern":"^-?(name|age)(,-?(name|age))*$"}},` +
+		`{"name":"limit","in":"query","description":"The maximum number of results.","schema":{"type":"integer","minimum":0}},` +
+		`{"name":"offset","in":"query","description":"The number of results to skip.","schema":{"type":"integer","minimum":0}}]`))
+
+	filter := params[0]
+	g.Expect(filter.Name).To(Equal("filter"))
+	g.Expect(filter.Schema.Type).To(Equal("array"))
+	g.Expect(filter.Description).To(ContainSubstring("\n- score (number) > <"))
+
+	// the documented pattern accepts exactly what the parser accepts
+	pattern := regexp.MustCompile(filter.Schema.Items.Pattern)
+	for _, s := range []string{"name=Fred", "name~F%", "age!=-3", "age<=18", "score>0.5", "active=true", "name=",
+		"name", "size=1", "age~1", "score=1", "age=x", "score>1e3", "active=TRUE", "active!=true"} {
+		_, err := fields.ParseFilter(s)
+		g.Expect(pattern.MatchString(s)).To(Equal(err == nil), s)
+	}
+}
@@ -0,0 +1,116 @@
+package wherehttp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Parameter is an OpenAPI 3 parameter object, sufficient to describe the query
+// parameters accepted by this package.
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Explode     *bool  `json:"explode,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+// Schema is an OpenAPI 3 (JSON schema) object.
+type Schema struct {
+	Type        string  `json:"type"`
+	Description string  `json:"description,omitempty"`
+	Pattern     string  `json:"pattern,omitempty"`
+	Minimum     *int    `json:"minimum,omitempty"`
+	Items       *Schema `json:"items,omitempty"`
+}
+
+// Parameters describes the filter, sort, limit and offset query parameters that are
+// accepted for the allow-list. The result can be marshalled as JSON and included in
+// the parameters of an OpenAPI operation.
+//
+// Each filter parameter is described by a pattern that admits exactly the columns,
+// operators and value syntax accepted by ParseFilter. Likewise, the sort parameter admits
+// only the sortable columns. The sort parameter is omitted if no field is sortable.
+func (fs Fields) Parameters() []Parameter {
+	zero := 0
+	explode := true
+
+	params := []Parameter{{
+		Name:        FilterParam,
+		In:          "query",
+		Description: fs.describeFilter(),
+		Explode:     &explode,
+		Schema: Schema{
+			Type:  "array",
+			Items: &Schema{Type: "string", Pattern: fs.filterPattern()},
+		},
+	}}
+
+	if sortable := fs.sortable(); len(sortable) > 0 {
+		column := "-?(" + strings.Join(sortable, "|") + ")"
+		params = append(params, Parameter{
+			Name:        SortParam,
+			In:          "query",
+			Description: "Comma-separated columns by which to sort; prefix a column with '-' for descending order.",
+			Schema:      Schema{Type: "string", Pattern: "^" + column + "(," + column + ")*$"},
+		})
+	}
+
+	return append(params,
+		Parameter{
+			Name:        LimitParam,
+			In:          "query",
+			Description: "The maximum number of results.",
+			Schema:      Schema{Type: "integer", Minimum: &zero},
+		},
+		Parameter{
+			Name:        OffsetParam,
+			In:          "query",
+			Description: "The number of results to skip.",
+			Schema:      Schema{Type: "integer", Minimum: &zero},
+		},
+	)
+}
+
+// filterPattern gives a regular expression, in the common subset of RE2 and ECMA
+// syntax, that matches the acceptable filter values.
+func (fs Fields) filterPattern() string {
+	alternatives := make([]string, 0, len(fs))
+	for _, f := range fs {
+		ops := make([]string, len(f.operators()))
+		for i, op := range f.operators() {
+			ops[i] = regexp.QuoteMeta(op)
+		}
+		alternatives = append(alternatives, regexp.QuoteMeta(f.Column)+
+			"("+strings.Join(ops, "|")+")("+valuePatterns[f.typ()]+")")
+	}
+	return "^(" + strings.Join(alternatives, "|") + ")$"
+}
+
+func (fs Fields) describeFilter() string {
+	buf := &strings.Builder{}
+	buf.WriteString("Conditions of the form column, operator, value, all of which must be true. The columns are:")
+	for _, f := range fs {
+		buf.WriteString("\n- ")
+		buf.WriteString(f.Column)
+		buf.WriteString(" (")
+		buf.WriteString(string(f.typ()))
+		buf.WriteString(") ")
+		buf.WriteString(strings.Join(f.operators(), " "))
+		if f.Description != "" {
+			buf.WriteString(": ")
+			buf.WriteString(f.Description)
+		}
+	}
+	return buf.String()
+}
+
+func (fs Fields) sortable() []string {
+	var columns []string
+	for _, f := range fs {
+		if f.Sortable {
+			columns = append(columns, regexp.QuoteMeta(f.Column))
+		}
+	}
+	return columns
+}
@@ -0,0 +1,155 @@
+package wherehttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/rickb777/where/v2"
+)
+
+// Query holds the filter and the paging constraint parsed from a request.
+type Query struct {
+	// Filter AND-s all the filter parameters; it is a no-op if there are none.
+	Filter where.Expression
+
+	// Constraint holds the sort, limit and offset parameters; it is never nil.
+	Constraint *where.Constraint
+}
+
+// Parse converts the filter, sort, limit and offset parameters into a Query. Other
+// parameters are ignored. All the parameters are checked, so the error reports every
+// problem; it joins an *Error for each offending parameter (see errors.As).
+func (fs Fields) Parse(values url.Values) (Query, error) {
+	var errs []error
+	q := Query{Constraint: &where.Constraint{}}
+
+	filters := make([]where.Node, 0, len(values[FilterParam]))
+	for _, s := range values[FilterParam] {
+		wh, err := fs.ParseFilter(s)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			filters = append(filters, wh)
+		}
+	}
+	q.Filter = where.And(filters...)
+
+	for _, s := range values[SortParam] {
+		for _, column := range strings.Split(s, ",") {
+			descending := strings.HasPrefix(column, "-")
+			column = strings.TrimPrefix(column, "-")
+			if f, ok := fs.Lookup(column); !ok || !f.Sortable {
+				errs = append(errs, &Error{Param: SortParam, Value: s, Reason: "cannot sort by " + column})
+			} else if descending {
+				q.Constraint.OrderBy(column).Desc()
+			} else {
+				q.Constraint.OrderBy(column).Asc()
+			}
+		}
+	}
+
+	if n, err := count(values, LimitParam); err != nil {
+		errs = append(errs, err)
+	} else {
+		q.Constraint.Limit(n)
+	}
+
+	if n, err := count(values, OffsetParam); err != nil {
+		errs = append(errs, err)
+	} else {
+		q.Constraint.Offset(n)
+	}
+
+	return q, errors.Join(errs...)
+}
+
+// count parses a non-negative integer parameter, which may be absent.
+func count(values url.Values, param string) (int, error) {
+	s := values.Get(param)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, &Error{Param: param, Value: s, Reason: "requires a non-negative integer"}
+	}
+	return n, nil
+}
+
+// ParseRequest parses the query parameters of a request (see Parse). This suits
+// frameworks such as echo, whose handlers have access to the *http.Request.
+func (fs Fields) ParseRequest(r *http.Request) (Query, error) {
+	return fs.Parse(r.URL.Query())
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Middleware returns net/http middleware that parses the query parameters of each request
+// (see Parse) and stores the Query in the request context (see FromContext). Invalid
+// requests are rejected with status 400, using WriteError.
+//
+// The middleware has the signature used by routers such as chi; for echo, it can be
+// adapted using echo.WrapMiddleware.
+func (fs Fields) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q, err := fs.ParseRequest(r)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), q)))
+	})
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the parent context that carries a query.
+func NewContext(parent context.Context, q Query) context.Context {
+	return context.WithValue(parent, contextKey{}, q)
+}
+
+// FromContext gets the query carried by a context, if any.
+func FromContext(ctx context.Context) (Query, bool) {
+	q, ok := ctx.Value(contextKey{}).(Query)
+	return q, ok
+}
+
+// errorResponse is the JSON body written by WriteError.
+type errorResponse struct {
+	Errors []*Error `json:"errors"`
+}
+
+// WriteError responds with status 400 and a JSON body listing the problems, e.g.
+//
+//	{"errors":[{"param":"filter","value":"size=1","reason":"unknown column size"}]}
+//
+// Each *Error within err is listed; any other error is reported without a parameter.
+func WriteError(w http.ResponseWriter, err error) {
+	body := errorResponse{Errors: flattenErrors(err)}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func flattenErrors(err error) []*Error {
+	var e *Error
+	switch x := err.(type) {
+	case nil:
+		return nil
+	case interface{ Unwrap() []error }:
+		var list []*Error
+		for _, inner := range x.Unwrap() {
+			list = append(list, flattenErrors(inner)...)
+		}
+		return list
+	}
+	if errors.As(err, &e) {
+		return []*Error{e}
+	}
+	return []*Error{{Reason: err.Error()}}
+}
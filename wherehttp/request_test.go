@@ -0,0 +1,69 @@
+package wherehttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/wherehttp"
+)
+
+func TestParse(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	values, _ := url.ParseQuery("filter=age>=18&filter=name~F%25&sort=-age,name&limit=20&offset=40&other=x")
+	q, err := fields.Parse(values)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	s, args := where.Where(q.Filter, dialect.Dollar)
+	g.Expect(s).To(Equal(` WHERE age>=$1 AND name LIKE $2`))
+	g.Expect(args).To(Equal([]any{int64(18), "F%"}))
+	g.Expect(q.Constraint.Format(dialect.Postgres)).To(Equal(` ORDER BY age DESC, name ASC LIMIT 20 OFFSET 40`))
+
+	q, err = fields.Parse(url.Values{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(q.Filter.String()).To(BeEmpty())
+	g.Expect(q.Constraint.Format(dialect.Postgres)).To(BeEmpty())
+}
+
+func TestParse_errors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	values, _ := url.ParseQuery("filter=size=1&filter=age=18&sort=score&limit=-1&offset=x")
+	_, err := fields.Parse(values)
+	g.Expect(err).To(MatchError(`wherehttp: filter "size=1": unknown column size
+wherehttp: sort "score": cannot sort by score
+wherehttp: limit "-1": requires a non-negative integer
+wherehttp: offset "x": requires a non-negative integer`))
+}
+
+func TestMiddleware(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var q wherehttp.Query
+	var found bool
+	handler := fields.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q, found = wherehttp.FromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/people?filter=active=true&limit=5", nil))
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(found).To(BeTrue())
+	g.Expect(q.Filter.String()).To(Equal(`active=true`))
+	g.Expect(q.Constraint.Format(dialect.Sqlite)).To(Equal(` LIMIT 5`))
+
+	found = false
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/people?filter=size=1&limit=x", nil))
+	g.Expect(w.Code).To(Equal(http.StatusBadRequest))
+	g.Expect(found).To(BeFalse())
+	g.Expect(w.Header().Get("Content-Type")).To(Equal("application/json"))
+	g.Expect(w.Body.String()).To(MatchJSON(`{"errors":[
+		{"param":"filter","value":"size=1","reason":"unknown column size"},
+		{"param":"limit","value":"x","reason":"requires a non-negative integer"}]}`))
+}
@@ -0,0 +1,86 @@
+package wheretest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// ConformanceTable is the name of the table created by ConformanceSchema.
+const ConformanceTable = "where_conformance"
+
+// ConformanceSchema gives the statement that creates the table queried by Corpus.
+func ConformanceSchema(d dialect.Dialect) string {
+	text, stamp := "VARCHAR(100)", "TIMESTAMP"
+	switch d {
+	case dialect.SqlServer:
+		text, stamp = "NVARCHAR(100)", "DATETIME2"
+	case dialect.Sqlite:
+		text = "TEXT"
+	}
+
+	q := d.Quoter()
+	return fmt.Sprintf("CREATE TABLE %s (%s INTEGER PRIMARY KEY, %s %s, %s INTEGER, %s %s NULL)",
+		q.Quote(ConformanceTable), q.Quote("id"), q.Quote("name"), text, q.Quote("age"),
+		q.Quote(where.DeletedColumn), stamp)
+}
+
+// Corpus returns a set of expressions that exercises the constructors in the where
+// package. They refer to the columns of ConformanceTable.
+func Corpus() []where.Node {
+	return []where.Node{
+		where.Eq("name", "Fred"),
+		where.NotEq("name", "Fred"),
+		where.Gt("age", 10),
+		where.GtEq("age", 10),
+		where.Lt("age", 10),
+		where.LtEq("age", 10),
+		where.Between("age", 10, 20),
+		where.Like("name", "F%"),
+		where.Null("name"),
+		where.NotNull("name"),
+		where.In("age", 10, 11, 12),
+		where.In("age", 10, nil),
+		where.InSlice("name", []string{"Fred", "John"}),
+		where.Not(where.In("age", 10, 11)),
+		where.Eq("name", "Fred").Or(where.Gt("age", 10)),
+		where.And(where.Eq("name", "Fred"), where.Or(where.Lt("age", 5), where.Gt("age", 65))),
+		where.Not(where.And(where.Gt("age", 10), where.Like("name", "J%"))),
+		where.NotDeleted(),
+		where.OnlyDeleted().And(where.Eq("id", 1)),
+		where.NoOp(),
+	}
+}
+
+// Conform executes each expression using the database, so that the database itself
+// confirms that the generated SQL is valid for dialect d. Each statement is
+// "SELECT COUNT(*) FROM table WHERE ...", formatted using the dialect's usual quoter
+// and placeholders, followed by any query constraints.
+//
+// An error is returned for each expression that the database rejects; the result is empty if
+// all of them are accepted. The table must already exist (see ConformanceSchema), although
+// it need not contain any data.
+func Conform(ctx context.Context, db *sql.DB, d dialect.Dialect, table string, exprs []where.Node, qc ...where.QueryConstraint) []error {
+	var errs []error
+	for _, expr := range exprs {
+		query, args := statement(d, "SELECT COUNT(*) FROM "+d.Quoter().Quote(table), expr, qc)
+
+		var n int64
+		if err := db.QueryRowContext(ctx, query, args...).Scan(&n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", d, query, err))
+		}
+	}
+	return errs
+}
+
+// AssertConform calls Conform and reports each rejected expression as a test error.
+func AssertConform(t testing.TB, db *sql.DB, d dialect.Dialect, table string, exprs []where.Node, qc ...where.QueryConstraint) {
+	t.Helper()
+	for _, err := range Conform(context.Background(), db, d, table, exprs, qc...) {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,86 @@
+package wheretest_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/wheretest"
+)
+
+// fakeDriver accepts every query except those containing "ILIKE", recording them all.
+type fakeDriver struct{ queries []string }
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.queries = append(c.d.queries, query)
+	if strings.Contains(query, "ILIKE") {
+		return nil, errors.New("syntax error")
+	}
+	return fakeStmt{}, nil
+}
+
+func (c fakeConn) Close() error              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("unsupported") }
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return nil, errors.New("unsupported") }
+func (fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return &fakeRows{}, nil }
+
+type fakeRows struct{ done bool }
+
+func (r *fakeRows) Columns() []string { return []string{"count"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(0)
+	return nil
+}
+
+var fake = &fakeDriver{}
+
+func init() {
+	sql.Register("wheretest-fake", fake)
+}
+
+func TestConform(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	db, err := sql.Open("wheretest-fake", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer db.Close()
+
+	corpus := wheretest.Corpus()
+	errs := wheretest.Conform(context.Background(), db, dialect.Postgres, wheretest.ConformanceTable, corpus)
+	g.Expect(errs).To(BeEmpty())
+	g.Expect(fake.queries).To(HaveLen(len(corpus)))
+	g.Expect(fake.queries[0]).To(Equal(`SELECT COUNT(*) FROM "where_conformance" WHERE "name"=$1`))
+
+	bad := []where.Node{where.Literal("name", " ILIKE ?", "f%"), where.Eq("age", 1)}
+	errs = wheretest.Conform(context.Background(), db, dialect.Postgres, "t", bad)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0]).To(MatchError(ContainSubstring(`"name" ILIKE $1`)))
+}
+
+func TestConformanceSchema(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(wheretest.ConformanceSchema(dialect.SqlServer)).To(Equal(
+		`CREATE TABLE [where_conformance] ([id] INTEGER PRIMARY KEY, [name] NVARCHAR(100), [age] INTEGER, [deleted_at] DATETIME2 NULL)`))
+}
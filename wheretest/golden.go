@@ -0,0 +1,84 @@
+package wheretest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// Dialects lists the dialects used by Golden.
+var Dialects = []dialect.Dialect{dialect.Sqlite, dialect.Mysql, dialect.Postgres, dialect.SqlServer}
+
+// GoldenDir is the directory containing golden files.
+var GoldenDir = "testdata"
+
+// Update causes Golden to write the golden files instead of comparing them.
+// By default, it is set when the environment variable WHERE_UPDATE_GOLDEN is not blank.
+// It can also be bound to a command-line flag, e.g.
+//
+//	flag.BoolVar(&wheretest.Update, "update", false, "update golden files")
+var Update = os.Getenv("WHERE_UPDATE_GOLDEN") != ""
+
+// Golden formats the WHERE clause for an expression, followed by any query constraints,
+// for every one of the Dialects. The result is compared with the golden file
+// GoldenDir/name.golden, reporting any difference as a test error. This locks down the
+// generated SQL so that unintended changes, e.g. after upgrading, are detected.
+//
+// When Update is true, the golden file is written instead.
+func Golden(t testing.TB, name string, expr where.Node, qc ...where.QueryConstraint) {
+	t.Helper()
+
+	actual := Snapshot(expr, qc...)
+	file := filepath.Join(GoldenDir, name+".golden")
+
+	if Update {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(actual), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("%v (set WHERE_UPDATE_GOLDEN=1 to create it)", err)
+	}
+
+	if string(expected) != actual {
+		t.Errorf("%s differs from the generated SQL\n--- expected ---\n%s--- actual ---\n%s", file, expected, actual)
+	}
+}
+
+// Snapshot formats the WHERE clause for an expression, followed by any query constraints,
+// for every one of the Dialects, using each dialect's usual quoter and placeholders.
+// This is the content of the golden files used by Golden.
+func Snapshot(expr where.Node, qc ...where.QueryConstraint) string {
+	buf := &strings.Builder{}
+	for _, d := range Dialects {
+		config := dialect.ConfigFor(d)
+		sql, args := where.Where(expr, config)
+
+		top := ""
+		for _, c := range qc {
+			var s string
+			top += c.FormatTOP(d)
+			s, args = formatConstraint(c, d, config, args)
+			sql += s
+		}
+
+		fmt.Fprintf(buf, "-- %s --\n", d)
+		if top != "" {
+			fmt.Fprintf(buf, "top: %s\n", strings.TrimSpace(top))
+		}
+		fmt.Fprintf(buf, "sql: %s\n", strings.TrimSpace(sql))
+		fmt.Fprintf(buf, "args: %#v\n", args)
+	}
+	return buf.String()
+}
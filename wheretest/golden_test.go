@@ -0,0 +1,60 @@
+package wheretest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/wheretest"
+)
+
+// recorder captures test errors.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestGolden(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	wheretest.GoldenDir = t.TempDir()
+	defer func() { wheretest.GoldenDir = "testdata" }()
+
+	qc := where.OrderBy("name").Limit(10)
+
+	wheretest.Update = true
+	wheretest.Golden(t, "people", expr, qc)
+	wheretest.Update = false
+
+	content, err := os.ReadFile(filepath.Join(wheretest.GoldenDir, "people.golden"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(content)).To(Equal(`-- Sqlite --
+sql: WHERE "name"=? AND "age" IN (?,?) ORDER BY "name" LIMIT 10
+args: []interface {}{"Fred", 10, 11}
+-- Mysql --
+sql: WHERE ` + "`name`=? AND `age` IN (?,?) ORDER BY `name`" + ` LIMIT 10
+args: []interface {}{"Fred", 10, 11}
+-- Postgres --
+sql: WHERE "name"=$1 AND "age" IN ($2,$3) ORDER BY "name" LIMIT 10
+args: []interface {}{"Fred", 10, 11}
+-- SqlServer --
+top: TOP (10)
+sql: WHERE [name]=@p1 AND [age] IN (@p2,@p3) ORDER BY [name]
+args: []interface {}{"Fred", 10, 11}
+`))
+
+	wheretest.Golden(t, "people", expr, qc)
+
+	r := &recorder{TB: t}
+	wheretest.Golden(r, "people", expr.And(where.Null("x")), qc)
+	g.Expect(r.errors).To(HaveLen(1))
+}
@@ -0,0 +1,86 @@
+package wheretest
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"strings"
+
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// MockQuery builds the statement that code under test would send to the database, for
+// use with mocking libraries such as github.com/DATA-DOG/go-sqlmock. The query is the
+// start of the statement, e.g. "SELECT * FROM users". The WHERE clause for the expression
+// and then any query constraints are appended to it, using the dialect's usual quoter and
+// placeholders (see dialect.ConfigFor). For SQL-Server, any TOP clause is inserted after
+// "SELECT" or "SELECT DISTINCT".
+//
+// The exact statement is returned, along with its arguments, which suit sqlmock's WithArgs.
+// Use it with sqlmock.QueryMatcherEqual; otherwise see MockPattern.
+func MockQuery(d dialect.Dialect, query string, expr where.Node, qc ...where.QueryConstraint) (string, []driver.Value) {
+	sql, args := statement(d, query, expr, qc)
+	return sql, driverValues(args)
+}
+
+// statement appends the formatted expression and query constraints to the query.
+func statement(d dialect.Dialect, query string, expr where.Node, qc []where.QueryConstraint) (string, []any) {
+	config := dialect.ConfigFor(d)
+	sql, args := where.Where(expr, config)
+
+	top := ""
+	for _, c := range qc {
+		var s string
+		top += c.FormatTOP(d)
+		s, args = formatConstraint(c, d, config, args)
+		sql += s
+	}
+
+	return insertTOP(query, top) + sql, args
+}
+
+// formatConstraint formats a query constraint, appending any arguments that it needs
+// (see where.Constraint.FormatArgs) to those of the WHERE clause.
+func formatConstraint(qc where.QueryConstraint, d dialect.Dialect, config dialect.FormatConfig, args []any) (string, []any) {
+	if c, ok := qc.(*where.Constraint); ok {
+		s, more := c.FormatArgs(d, config, where.WithPlaceholderOffset(len(args)))
+		return s, append(args, more...)
+	}
+	return qc.Format(d, config), args
+}
+
+// MockPattern is like MockQuery but returns a regular expression that matches only the
+// exact statement, which suits sqlmock's default query matcher, e.g.
+//
+//	pattern, args := wheretest.MockPattern(dialect.Postgres, "SELECT * FROM users", expr)
+//	mock.ExpectQuery(pattern).WithArgs(args...).WillReturnRows(rows)
+func MockPattern(d dialect.Dialect, query string, expr where.Node, qc ...where.QueryConstraint) (string, []driver.Value) {
+	sql, args := MockQuery(d, query, expr, qc...)
+	return "^" + regexp.QuoteMeta(strings.TrimSpace(sql)) + "$", args
+}
+
+func insertTOP(query, top string) string {
+	if top == "" {
+		return query
+	}
+
+	for _, prefix := range []string{"SELECT DISTINCT ", "SELECT "} {
+		if len(query) >= len(prefix) && strings.EqualFold(query[:len(prefix)], prefix) {
+			n := len(prefix) - 1 // top begins with a space
+			return query[:n] + top + query[n:]
+		}
+	}
+	return query
+}
+
+func driverValues(args []any) []driver.Value {
+	if len(args) == 0 {
+		return nil
+	}
+
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a
+	}
+	return values
+}
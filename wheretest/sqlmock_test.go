@@ -0,0 +1,45 @@
+package wheretest_test
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/wheretest"
+)
+
+func TestMockQuery(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	qc := where.OrderBy("name").Limit(10)
+
+	sql, args := wheretest.MockQuery(dialect.Postgres, "SELECT * FROM users", expr, qc)
+	g.Expect(sql).To(Equal(`SELECT * FROM users WHERE "name"=$1 AND "age" IN ($2,$3) ORDER BY "name" LIMIT 10`))
+	g.Expect(args).To(Equal([]driver.Value{"Fred", 10, 11}))
+
+	sql, _ = wheretest.MockQuery(dialect.SqlServer, "select distinct * FROM users", expr, qc)
+	g.Expect(sql).To(Equal(`select distinct TOP (10) * FROM users WHERE [name]=@p1 AND [age] IN (@p2,@p3) ORDER BY [name]`))
+
+	sql, args = wheretest.MockQuery(dialect.Postgres, "SELECT * FROM users", expr, where.OrderByRank("doc", "cat"))
+	g.Expect(sql).To(Equal(`SELECT * FROM users WHERE "name"=$1 AND "age" IN ($2,$3) ORDER BY ts_rank("doc", plainto_tsquery($4)) DESC`))
+	g.Expect(args).To(Equal([]driver.Value{"Fred", 10, 11, "cat"}))
+
+	sql, args = wheretest.MockQuery(dialect.Sqlite, "DELETE FROM users", nil)
+	g.Expect(sql).To(Equal(`DELETE FROM users`))
+	g.Expect(args).To(BeNil())
+}
+
+func TestMockPattern(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	pattern, args := wheretest.MockPattern(dialect.Mysql, "SELECT * FROM users", expr)
+	g.Expect(pattern).To(Equal("^SELECT \\* FROM users WHERE `name`=\\? AND `age` IN \\(\\?,\\?\\)$"))
+	g.Expect(args).To(HaveLen(3))
+
+	re := regexp.MustCompile(pattern)
+	g.Expect(re.MatchString("SELECT * FROM users WHERE `name`=? AND `age` IN (?,?)")).To(BeTrue())
+	g.Expect(re.MatchString("SELECT * FROM users WHERE `name`=? AND `age` IN (?,?) LIMIT 1")).To(BeFalse())
+}
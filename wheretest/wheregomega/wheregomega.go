@@ -0,0 +1,48 @@
+// Package wheregomega provides a Gomega matcher for where-expressions. It is separate
+// from the wheretest package so that only code using Gomega depends on it.
+package wheregomega
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega/types"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/wheretest"
+)
+
+// HaveSQL returns a Gomega matcher that applies wheretest.MatchSQL to an actual expression, e.g.
+//
+//	Expect(expr).To(wheregomega.HaveSQL(dialect.Postgres, `"name"=$1`, "Fred"))
+func HaveSQL(d dialect.Dialect, expectedSQL string, expectedArgs ...any) types.GomegaMatcher {
+	return &sqlMatcher{d: d, sql: expectedSQL, args: expectedArgs}
+}
+
+type sqlMatcher struct {
+	d    dialect.Dialect
+	sql  string
+	args []any
+	err  error
+}
+
+func (m *sqlMatcher) Match(actual any) (bool, error) {
+	if actual == nil {
+		m.err = wheretest.MatchSQL(nil, m.d, m.sql, m.args...)
+		return m.err == nil, nil
+	}
+
+	expr, ok := actual.(where.Node)
+	if !ok {
+		return false, fmt.Errorf("HaveSQL expects a where.Node, not %T", actual)
+	}
+	m.err = wheretest.MatchSQL(expr, m.d, m.sql, m.args...)
+	return m.err == nil, nil
+}
+
+func (m *sqlMatcher) FailureMessage(actual any) string {
+	return m.err.Error()
+}
+
+func (m *sqlMatcher) NegatedFailureMessage(actual any) string {
+	return fmt.Sprintf("expected SQL not to match for %s\n  %s", m.d, m.sql)
+}
@@ -0,0 +1,20 @@
+package wheregomega_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/wheretest/wheregomega"
+)
+
+var expr = where.And(where.Eq("name", "Fred"), where.In("age", 10, 11))
+
+func TestHaveSQL(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(expr).To(wheregomega.HaveSQL(dialect.Postgres, `"name"=$1 AND "age" IN ($2,$3)`, "Fred", 10, 11))
+	g.Expect(expr).NotTo(wheregomega.HaveSQL(dialect.Postgres, `"name"=$1`, "Fred"))
+	g.Expect(nil).To(wheregomega.HaveSQL(dialect.Postgres, ``))
+}
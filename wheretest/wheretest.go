@@ -0,0 +1,129 @@
+// Package wheretest provides helpers for testing code that builds where-expressions.
+// Rather than comparing formatted strings by hand, MatchSQL formats an expression for
+// a dialect and compares it with the expected SQL, ignoring unimportant differences
+// in whitespace and placeholder style.
+//
+// MatchSQL returns an error, so it can be used with any test framework. For convenience,
+// AssertSQL suits the standard testing package; the wheregomega sub-package provides
+// an equivalent Gomega matcher.
+package wheretest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+)
+
+// MatchSQL formats an expression for a dialect, using its usual quoter and placeholders
+// (see dialect.ConfigFor), then compares the result with the expected SQL and arguments.
+// It returns nil if they match, or an error describing the difference otherwise.
+//
+// The expected SQL may be written with or without the "WHERE" keyword. Whitespace is
+// normalised: runs of spaces are equivalent to a single space, and spaces beside
+// operators, commas and parentheses are ignored. Placeholders are normalised too, so
+// "?", "$1", "@p1" and ":name_1" are all equivalent.
+func MatchSQL(expr where.Node, d dialect.Dialect, expectedSQL string, expectedArgs ...any) error {
+	var actualSQL string
+	var actualArgs []any
+	if expr != nil {
+		actualSQL, actualArgs = expr.Format(dialect.ConfigFor(d))
+	}
+
+	exp := Normalise(strings.TrimPrefix(strings.TrimSpace(expectedSQL), "WHERE "))
+	act := Normalise(actualSQL)
+	if act != exp {
+		return fmt.Errorf("SQL mismatch for %s\n  expected: %s\n    actual: %s", d, expectedSQL, actualSQL)
+	}
+
+	if len(actualArgs) != len(expectedArgs) || (len(expectedArgs) > 0 && !reflect.DeepEqual(actualArgs, expectedArgs)) {
+		return fmt.Errorf("args mismatch for %s\n  expected: %#v\n    actual: %#v", d, expectedArgs, actualArgs)
+	}
+
+	return nil
+}
+
+// AssertSQL calls MatchSQL and reports any mismatch as a test error.
+func AssertSQL(t testing.TB, expr where.Node, d dialect.Dialect, expectedSQL string, expectedArgs ...any) {
+	t.Helper()
+	if err := MatchSQL(expr, d, expectedSQL, expectedArgs...); err != nil {
+		t.Error(err)
+	}
+}
+
+// Normalise reduces SQL to a canonical form for comparison. This is used by MatchSQL.
+// Quoted strings and identifiers are left unchanged.
+func Normalise(sql string) string {
+	buf := &strings.Builder{}
+	buf.Grow(len(sql))
+
+	var quote byte
+	pendingSpace := false
+	rs := []byte(sql)
+
+	for i := 0; i < len(rs); i++ {
+		ch := rs[i]
+
+		if quote != 0 {
+			buf.WriteByte(ch)
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case isSpace(ch):
+			pendingSpace = true
+			continue
+
+		case ch == '?', ch == '$' && i+1 < len(rs) && isDigit(rs[i+1]),
+			ch == '@' && i+2 < len(rs) && rs[i+1] == 'p' && isDigit(rs[i+2]),
+			ch == ':' && i+1 < len(rs) && isWordStart(rs[i+1]) && (i == 0 || rs[i-1] != ':'):
+			// skip the rest of the placeholder
+			for i+1 < len(rs) && (isDigit(rs[i+1]) || isWordStart(rs[i+1]) || (ch == '@' && rs[i+1] == 'p')) {
+				i++
+			}
+			ch = '?'
+		}
+
+		if pendingSpace && buf.Len() > 0 && !isPunct(ch) && !isPunct(lastByte(buf)) {
+			buf.WriteByte(' ')
+		}
+		pendingSpace = false
+
+		switch ch {
+		case '\'', '"', '`':
+			quote = ch
+		case '[':
+			quote = ']'
+		}
+		buf.WriteByte(ch)
+	}
+
+	return buf.String()
+}
+
+func lastByte(buf *strings.Builder) byte {
+	s := buf.String()
+	return s[len(s)-1]
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+func isWordStart(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+}
+
+func isPunct(ch byte) bool {
+	return strings.IndexByte("=<>!(),", ch) >= 0
+}
@@ -0,0 +1,37 @@
+package wheretest_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/where/v2"
+	"github.com/rickb777/where/v2/dialect"
+	"github.com/rickb777/where/v2/wheretest"
+)
+
+var expr = where.And(where.Eq("name", "Fred"), where.In("age", 10, 11))
+
+func TestMatchSQL(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(wheretest.MatchSQL(expr, dialect.Postgres, `"name" = ? AND "age" IN (?, ?)`, "Fred", 10, 11)).To(Succeed())
+	g.Expect(wheretest.MatchSQL(expr, dialect.SqlServer, ` WHERE  [name]=$1 AND [age] IN ($2,$3) `, "Fred", 10, 11)).To(Succeed())
+	g.Expect(wheretest.MatchSQL(expr, dialect.Mysql, "`name`=:n AND `age` IN (@p1,@p2)", "Fred", 10, 11)).To(Succeed())
+	g.Expect(wheretest.MatchSQL(where.NoOp(), dialect.Mysql, "")).To(Succeed())
+
+	g.Expect(wheretest.MatchSQL(expr, dialect.Postgres, `"name"=? OR "age" IN (?,?)`, "Fred", 10, 11)).To(MatchError(ContainSubstring("SQL mismatch")))
+	g.Expect(wheretest.MatchSQL(expr, dialect.Postgres, `"name"=? AND "age" IN (?,?)`, "Fred", 10)).To(MatchError(ContainSubstring("args mismatch")))
+	g.Expect(wheretest.MatchSQL(where.Literal("a", " = 'x  y'"), dialect.Sqlite, `"a" = 'x y'`)).To(HaveOccurred())
+}
+
+func TestAssertSQL(t *testing.T) {
+	wheretest.AssertSQL(t, expr, dialect.Postgres, `"name"=$1 AND "age" IN ($2,$3)`, "Fred", 10, 11)
+}
+
+func TestNormalise(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(wheretest.Normalise(" a  =  $12 AND\n b IN ( @p2 , :x_1 ) ")).To(Equal(`a=? AND b IN(?,?)`))
+	g.Expect(wheretest.Normalise(`"a  b" = '  '`)).To(Equal(`"a  b"='  '`))
+	g.Expect(wheretest.Normalise(`id = $1::uuid`)).To(Equal(`id=?::uuid`))
+}